@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	betRepo "github.com/ttagiyeva/entain/internal/bet/repository"
+	"github.com/ttagiyeva/entain/internal/config"
+	"github.com/ttagiyeva/entain/internal/database"
+	exclusionRepo "github.com/ttagiyeva/entain/internal/exclusion/repository"
+	ledgerRepo "github.com/ttagiyeva/entain/internal/ledger/repository"
+	limitRepo "github.com/ttagiyeva/entain/internal/limit/repository"
+	"github.com/ttagiyeva/entain/internal/registry"
+	registryRepo "github.com/ttagiyeva/entain/internal/registry/repository"
+	registryUsecase "github.com/ttagiyeva/entain/internal/registry/usecase"
+	"github.com/ttagiyeva/entain/internal/transaction"
+	"github.com/ttagiyeva/entain/internal/transaction/repository"
+	"github.com/ttagiyeva/entain/internal/transaction/usecase"
+	transferRepo "github.com/ttagiyeva/entain/internal/transfer/repository"
+	"github.com/ttagiyeva/entain/internal/user"
+	userRepo "github.com/ttagiyeva/entain/internal/user/repository"
+	userUsecase "github.com/ttagiyeva/entain/internal/user/usecase"
+)
+
+const usage = `Usage: entainctl <command> [arguments]
+
+Commands:
+  migrate up|down|version|force   manage the database migrations
+  user create|show|balance        manage users
+  tx list|cancel|replay           manage transactions
+  reconcile                       compare the wallets with the ledger
+
+The configuration is read from the same ENTAIN_ environment variables as the server.
+`
+
+// usageError is an error in the arguments of a command, it is reported with exit code 2.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// main is the entry point of the admin command-line tool.
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "entainctl:", err)
+
+		var uErr *usageError
+		if errors.As(err, &uErr) {
+			os.Exit(2)
+		}
+
+		os.Exit(1)
+	}
+}
+
+// run executes the command given by args. The arguments are checked before connecting to the database.
+func run(ctx context.Context, args []string, in io.Reader, out, errOut io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(errOut, usage)
+
+		return usagef("missing command")
+	}
+
+	a := &app{ctx: ctx, in: in, out: out, errOut: errOut}
+
+	switch args[0] {
+	case "migrate":
+		return a.migrate(args[1:])
+	case "user":
+		return a.user(args[1:])
+	case "tx":
+		return a.tx(args[1:])
+	case "reconcile":
+		return a.reconcile(args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(out, usage)
+
+		return nil
+	default:
+		return usagef("unknown command %q", args[0])
+	}
+}
+
+// app holds what the commands share, the dependencies are built like in the server.
+type app struct {
+	ctx    context.Context
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+
+	conf *config.Config
+	log  *slog.Logger
+	db   *database.Postgres
+}
+
+// connect reads the configuration and connects to the database.
+func (a *app) connect() error {
+	a.conf = config.New()
+	// Logs go to stderr so they never mix with the output of a command.
+	a.log = slog.New(slog.NewJSONHandler(a.errOut, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	a.db = database.NewPostgres()
+
+	err := a.db.Connect(a.ctx, a.conf)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return nil
+}
+
+func (a *app) close() {
+	if a.db != nil && a.db.Connection != nil {
+		a.db.Close()
+	}
+}
+
+func (a *app) registryUsecase() registry.Usecase {
+	return registryUsecase.New(a.log, a.conf, registryRepo.New(a.db.Connection))
+}
+
+func (a *app) userUsecase() user.Usecase {
+	return userUsecase.New(a.log, userRepo.New(a.db.Connection))
+}
+
+func (a *app) transactionUsecase() transaction.Usecase {
+	return usecase.New(
+		a.log,
+		a.conf,
+		repository.New(a.db.Connection),
+		userRepo.New(a.db.Connection),
+		ledgerRepo.New(a.db.Connection),
+		limitRepo.New(a.db.Connection),
+		exclusionRepo.New(a.db.Connection),
+		a.registryUsecase(),
+		betRepo.New(a.db.Connection),
+		transferRepo.New(a.db.Connection),
+		a.db,
+		database.NewLeader(a.db, a.conf.PostProcess.LockKey),
+	)
+}
+
+// print writes v as indented json to the output.
+func (a *app) print(v any) error {
+	enc := json.NewEncoder(a.out)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// parse parses the flags of a subcommand, its errors are usage errors.
+func parse(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(io.Discard)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return usagef("%s: %v", fs.Name(), err)
+	}
+
+	return nil
+}
+
+// subcommand splits the subcommand from its arguments.
+func subcommand(cmd string, args []string, names ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, usagef("%s: missing subcommand, one of %v", cmd, names)
+	}
+
+	for _, n := range names {
+		if args[0] == n {
+			return n, args[1:], nil
+		}
+	}
+
+	return "", nil, usagef("%s: unknown subcommand %q, one of %v", cmd, args[0], names)
+}
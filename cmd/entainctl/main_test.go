@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunUsage(t *testing.T) {
+	// Every case fails on its arguments, before connecting to the database.
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "Missing command", args: nil, err: "missing command"},
+		{name: "Unknown command", args: []string{"serve"}, err: `unknown command "serve"`},
+		{name: "Missing subcommand", args: []string{"migrate"}, err: "migrate: missing subcommand, one of [up down version force]"},
+		{name: "Unknown subcommand", args: []string{"user", "delete"}, err: `user: unknown subcommand "delete", one of [create show balance]`},
+		{name: "Down without confirmation", args: []string{"migrate", "down"}, err: "migrate down: it drops every table, confirm with -yes"},
+		{name: "Force without version", args: []string{"migrate", "force"}, err: "migrate force: expected a version"},
+		{name: "Force with invalid version", args: []string{"migrate", "force", "x"}, err: "migrate force: version must be an integer of at least -1"},
+		{name: "Show without id", args: []string{"user", "show"}, err: "user show: expected a user id"},
+		{name: "List with invalid time", args: []string{"tx", "list", "-from", "yesterday", "id"}, err: "tx list: -from must be a RFC3339 time"},
+		{name: "List with invalid cancelled", args: []string{"tx", "list", "-cancelled", "maybe", "id"}, err: "tx list: -cancelled must be true or false"},
+		{name: "Unknown flag", args: []string{"tx", "cancel", "-force", "id"}, err: "tx cancel: flag provided but not defined: -force"},
+		{name: "Reconcile with arguments", args: []string{"reconcile", "now"}, err: "reconcile: unexpected arguments [now]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := run(context.Background(), tt.args, nil, &out, &out)
+			require.EqualError(t, err, tt.err)
+
+			var uErr *usageError
+			require.True(t, errors.As(err, &uErr))
+		})
+	}
+}
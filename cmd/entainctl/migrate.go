@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+// migrate runs the database migrations embedded in the binary, the same ones the server runs on start.
+func (a *app) migrate(args []string) error {
+	sub, args, err := subcommand("migrate", args, "up", "down", "version", "force")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("migrate "+sub, flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "confirm reverting every migration")
+
+	err = parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	version := 0
+
+	switch sub {
+	case "down":
+		if !*yes {
+			return usagef("migrate down: it drops every table, confirm with -yes")
+		}
+	case "force":
+		if fs.NArg() != 1 {
+			return usagef("migrate force: expected a version")
+		}
+
+		version, err = strconv.Atoi(fs.Arg(0))
+		if err != nil || version < -1 {
+			return usagef("migrate force: version must be an integer of at least -1")
+		}
+	}
+
+	if sub != "force" && fs.NArg() != 0 {
+		return usagef("migrate %s: unexpected arguments %v", sub, fs.Args())
+	}
+
+	err = a.connect()
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	switch sub {
+	case "up":
+		err = a.db.MigrateUp()
+	case "down":
+		err = a.db.MigrateDown()
+	case "force":
+		err = a.db.MigrateForce(version)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Fprintln(a.out, "no change")
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", sub, err)
+	}
+
+	return a.version()
+}
+
+// version prints the migration version of the database against the latest migration of the service.
+func (a *app) version() error {
+	status, err := a.db.MigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status.Dirty:
+		fmt.Fprintf(a.out, "version %d of %d (dirty)\n", status.Version, status.Latest)
+	case status.Version == 0:
+		fmt.Fprintf(a.out, "no migration applied of %d\n", status.Latest)
+	default:
+		fmt.Fprintf(a.out, "version %d of %d\n", status.Version, status.Latest)
+	}
+
+	return nil
+}
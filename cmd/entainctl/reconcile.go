@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	ledgerRepo "github.com/ttagiyeva/entain/internal/ledger/repository"
+)
+
+// reconcile compares the balance of every wallet with the sum of its ledger entries.
+// The mismatches are printed and the command fails, so it can run as a scheduled check.
+func (a *app) reconcile(args []string) error {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+
+	err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() != 0 {
+		return usagef("reconcile: unexpected arguments %v", fs.Args())
+	}
+
+	err = a.connect()
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	mismatches, err := ledgerRepo.New(a.db.Connection).GetMismatches(a.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get ledger mismatches: %w", err)
+	}
+
+	if len(mismatches) == 0 {
+		fmt.Fprintln(a.out, "wallets match the ledger")
+
+		return nil
+	}
+
+	err = a.print(mismatches)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%d wallets do not match the ledger", len(mismatches))
+}
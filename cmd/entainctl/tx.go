@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// defaultPageSize is the number of transactions listed when no limit is given, the same as the history endpoint.
+const defaultPageSize = 50
+
+// tx lists, cancels and replays transactions.
+func (a *app) tx(args []string) error {
+	sub, args, err := subcommand("tx", args, "list", "cancel", "replay")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list":
+		return a.txList(args)
+	case "cancel":
+		return a.txCancel(args)
+	default:
+		return a.txReplay(args)
+	}
+}
+
+// txList prints a page of the transaction history of a user, the latest transactions first.
+func (a *app) txList(args []string) error {
+	fs := flag.NewFlagSet("tx list", flag.ContinueOnError)
+	state := fs.String("state", "", "only transactions in this state")
+	sourceType := fs.String("source-type", "", "only transactions of this source type")
+	cancelled := fs.String("cancelled", "", "only cancelled (true) or uncancelled (false) transactions")
+	from := fs.String("from", "", "only transactions made at or after this RFC3339 time")
+	to := fs.String("to", "", "only transactions made before this RFC3339 time")
+	limit := fs.Int("limit", defaultPageSize, "maximum number of transactions")
+	cursor := fs.String("cursor", "", "cursor of the page returned by a previous call")
+
+	err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return usagef("tx list: expected a user id")
+	}
+
+	filter := &model.TransactionFilter{
+		UserID:     fs.Arg(0),
+		State:      *state,
+		SourceType: *sourceType,
+		Limit:      *limit,
+	}
+
+	switch *cancelled {
+	case "":
+	case "true", "false":
+		c := *cancelled == "true"
+		filter.Cancelled = &c
+	default:
+		return usagef("tx list: -cancelled must be true or false")
+	}
+
+	times := []struct {
+		name  string
+		value string
+		field **time.Time
+	}{
+		{"from", *from, &filter.From},
+		{"to", *to, &filter.To},
+	}
+
+	for _, t := range times {
+		if t.value == "" {
+			continue
+		}
+
+		tm, err := time.Parse(time.RFC3339, t.value)
+		if err != nil {
+			return usagef("tx list: -%s must be a RFC3339 time", t.name)
+		}
+
+		*t.field = &tm
+	}
+
+	filter.Cursor, err = model.DecodeCursor(*cursor)
+	if err != nil {
+		return usagef("tx list: -cursor is not valid")
+	}
+
+	err = a.connect()
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	reg, err := a.registryUsecase().Get(a.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the registry: %w", err)
+	}
+
+	err = reg.Validator().Struct(filter)
+	if err != nil {
+		return usagef("tx list: %v", err)
+	}
+
+	page, err := a.transactionUsecase().GetTransactions(a.ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return a.print(page)
+}
+
+// txCancel cancels a processed transaction and reverts its effect on the wallet.
+func (a *app) txCancel(args []string) error {
+	fs := flag.NewFlagSet("tx cancel", flag.ContinueOnError)
+
+	err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return usagef("tx cancel: expected a transaction id")
+	}
+
+	err = a.connect()
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	err = a.transactionUsecase().Cancel(a.ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.out, "cancelled", fs.Arg(0))
+
+	return nil
+}
+
+// txReplay processes newline-delimited json transactions from a file or stdin, like the import endpoint.
+// Transactions which were already processed are reported as duplicates, so a replay can be repeated safely.
+func (a *app) txReplay(args []string) error {
+	fs := flag.NewFlagSet("tx replay", flag.ContinueOnError)
+	sourceType := fs.String("source-type", "", "source type of every transaction, overriding the one of the lines")
+
+	err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() > 1 {
+		return usagef("tx replay: expected at most one file")
+	}
+
+	in := a.in
+
+	if fs.NArg() == 1 && fs.Arg(0) != "-" {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("failed to open file: %w", err)
+		}
+		defer f.Close()
+
+		in = f
+	}
+
+	err = a.connect()
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	progress, err := a.transactionUsecase().Import(a.ctx, in, *sourceType, &replayReporter{w: a.errOut})
+	if err != nil {
+		return err
+	}
+
+	err = a.print(progress)
+	if err != nil {
+		return err
+	}
+
+	if progress.Failed > 0 {
+		return fmt.Errorf("%d transactions failed", progress.Failed)
+	}
+
+	return nil
+}
+
+// replayReporter writes the failed lines and the progress of a replay as json lines.
+type replayReporter struct {
+	w io.Writer
+}
+
+// Progress implements transaction.ImportReporter.
+func (r *replayReporter) Progress(p model.ImportProgress) {
+	if p.Done {
+		// The final progress is the result of the command.
+		return
+	}
+
+	json.NewEncoder(r.w).Encode(&p)
+}
+
+// Failure implements transaction.ImportReporter.
+func (r *replayReporter) Failure(f *model.ImportFailure) {
+	f.Error = &model.Error{Message: f.Err.Error()}
+
+	json.NewEncoder(r.w).Encode(f)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// user creates users and shows their status and balance.
+func (a *app) user(args []string) error {
+	sub, args, err := subcommand("user", args, "create", "show", "balance")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("user "+sub, flag.ContinueOnError)
+
+	err = parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case sub == "create" && fs.NArg() != 0:
+		return usagef("user create: unexpected arguments %v", fs.Args())
+	case sub != "create" && fs.NArg() != 1:
+		return usagef("user %s: expected a user id", sub)
+	}
+
+	err = a.connect()
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	u := a.userUsecase()
+
+	switch sub {
+	case "create":
+		user, err := u.CreateUser(a.ctx)
+		if err != nil {
+			return err
+		}
+
+		return a.print(user)
+	case "show":
+		user, err := u.GetUser(a.ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		return a.print(user)
+	default:
+		balance, err := u.GetBalance(a.ctx, fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("failed to get balance: %w", err)
+		}
+
+		return a.print(balance)
+	}
+}
@@ -3,19 +3,37 @@ package main
 import (
 	"context"
 
-	"github.com/golang-migrate/migrate"
 	"go.uber.org/fx"
 
+	"github.com/ttagiyeva/entain/internal/bet"
+	betRepo "github.com/ttagiyeva/entain/internal/bet/repository"
 	"github.com/ttagiyeva/entain/internal/config"
 	"github.com/ttagiyeva/entain/internal/database"
+	"github.com/ttagiyeva/entain/internal/exclusion"
+	exclusionHttp "github.com/ttagiyeva/entain/internal/exclusion/delivery/http"
+	exclusionRepo "github.com/ttagiyeva/entain/internal/exclusion/repository"
+	exclusionUsecase "github.com/ttagiyeva/entain/internal/exclusion/usecase"
+	"github.com/ttagiyeva/entain/internal/ledger"
+	ledgerRepo "github.com/ttagiyeva/entain/internal/ledger/repository"
+	"github.com/ttagiyeva/entain/internal/limit"
+	limitHttp "github.com/ttagiyeva/entain/internal/limit/delivery/http"
+	limitRepo "github.com/ttagiyeva/entain/internal/limit/repository"
+	limitUsecase "github.com/ttagiyeva/entain/internal/limit/usecase"
 	"github.com/ttagiyeva/entain/internal/logger"
+	"github.com/ttagiyeva/entain/internal/registry"
+	registryRepo "github.com/ttagiyeva/entain/internal/registry/repository"
+	registryUsecase "github.com/ttagiyeva/entain/internal/registry/usecase"
 	"github.com/ttagiyeva/entain/internal/service"
 	"github.com/ttagiyeva/entain/internal/transaction"
 	"github.com/ttagiyeva/entain/internal/transaction/delivery/http"
 	"github.com/ttagiyeva/entain/internal/transaction/repository"
 	"github.com/ttagiyeva/entain/internal/transaction/usecase"
+	"github.com/ttagiyeva/entain/internal/transfer"
+	transferRepo "github.com/ttagiyeva/entain/internal/transfer/repository"
 	"github.com/ttagiyeva/entain/internal/user"
+	userHttp "github.com/ttagiyeva/entain/internal/user/delivery/http"
 	userRepo "github.com/ttagiyeva/entain/internal/user/repository"
+	userUsecase "github.com/ttagiyeva/entain/internal/user/usecase"
 )
 
 // main is the entry point of the application.
@@ -26,6 +44,9 @@ func main() {
 			logger.NewLogger,
 			service.NewServer,
 			http.NewHandler,
+			userHttp.NewHandler,
+			limitHttp.NewHandler,
+			exclusionHttp.NewHandler,
 			database.NewPostgres,
 
 			fx.Annotate(
@@ -41,6 +62,26 @@ func main() {
 				fx.As(new(transaction.Usecase)),
 			),
 
+			fx.Annotate(
+				userUsecase.New,
+				fx.As(new(user.Usecase)),
+			),
+
+			fx.Annotate(
+				limitUsecase.New,
+				fx.As(new(limit.Usecase)),
+			),
+
+			fx.Annotate(
+				exclusionUsecase.New,
+				fx.As(new(exclusion.Usecase)),
+			),
+
+			fx.Annotate(
+				registryUsecase.New,
+				fx.As(new(registry.Usecase)),
+			),
+
 			fx.Annotate(
 				func(postgres *database.Postgres) transaction.Repository {
 					return repository.New(postgres.Connection)
@@ -49,6 +90,14 @@ func main() {
 				fx.As(new(transaction.Repository)),
 			),
 
+			fx.Annotate(
+				func(postgres *database.Postgres, c *config.Config) transaction.Elector {
+					return database.NewLeader(postgres, c.PostProcess.LockKey)
+				},
+
+				fx.As(new(transaction.Elector)),
+			),
+
 			fx.Annotate(
 				func(postgres *database.Postgres) user.Repository {
 					return userRepo.New(postgres.Connection)
@@ -56,6 +105,54 @@ func main() {
 
 				fx.As(new(user.Repository)),
 			),
+
+			fx.Annotate(
+				func(postgres *database.Postgres) ledger.Repository {
+					return ledgerRepo.New(postgres.Connection)
+				},
+
+				fx.As(new(ledger.Repository)),
+			),
+
+			fx.Annotate(
+				func(postgres *database.Postgres) limit.Repository {
+					return limitRepo.New(postgres.Connection)
+				},
+
+				fx.As(new(limit.Repository)),
+			),
+
+			fx.Annotate(
+				func(postgres *database.Postgres) exclusion.Repository {
+					return exclusionRepo.New(postgres.Connection)
+				},
+
+				fx.As(new(exclusion.Repository)),
+			),
+
+			fx.Annotate(
+				func(postgres *database.Postgres) registry.Repository {
+					return registryRepo.New(postgres.Connection)
+				},
+
+				fx.As(new(registry.Repository)),
+			),
+
+			fx.Annotate(
+				func(postgres *database.Postgres) bet.Repository {
+					return betRepo.New(postgres.Connection)
+				},
+
+				fx.As(new(bet.Repository)),
+			),
+
+			fx.Annotate(
+				func(postgres *database.Postgres) transfer.Repository {
+					return transferRepo.New(postgres.Connection)
+				},
+
+				fx.As(new(transfer.Repository)),
+			),
 		),
 		// Creating connection to database
 		fx.Invoke(
@@ -66,20 +163,18 @@ func main() {
 				}
 			},
 		),
-		// Executing database migrations
+		// Executing or checking database migrations, the service refuses to serve with a schema behind or dirty
 		fx.Invoke(
-			func(p *database.Postgres) {
-				err := p.MigrateUp()
-				if err != nil && err == migrate.ErrNoChange {
+			func(p *database.Postgres, c *config.Config) {
+				err := p.Migrate(c.Migrate.Mode)
+				if err != nil {
 					panic(err)
 				}
 			},
 		),
 		fx.Invoke(
-			func(uc transaction.Usecase) {
-				go uc.PostProcess(context.Background())
-			},
-
+			service.RegisterPostProcess,
+			service.RegisterBetExpiry,
 			service.RegisterRouters,
 		),
 	).Run()
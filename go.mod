@@ -6,7 +6,6 @@ require (
 	github.com/brianvoe/gofakeit/v6 v6.28.0
 	github.com/bxcodec/faker/v3 v3.8.1
 	github.com/go-playground/validator v9.31.0+incompatible
-	github.com/golang-migrate/migrate v3.5.4+incompatible
 	github.com/golang-migrate/migrate/v4 v4.17.1
 	github.com/golang/mock v1.6.0
 	github.com/jmoiron/sqlx v1.4.0
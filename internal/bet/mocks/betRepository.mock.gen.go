@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/ttagiyeva/entain/internal/model"
+)
+
+// MockBetRepository is a mock of Repository interface.
+type MockBetRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBetRepositoryMockRecorder
+}
+
+// MockBetRepositoryMockRecorder is the mock recorder for MockBetRepository.
+type MockBetRepositoryMockRecorder struct {
+	mock *MockBetRepository
+}
+
+// NewMockBetRepository creates a new mock instance.
+func NewMockBetRepository(ctrl *gomock.Controller) *MockBetRepository {
+	mock := &MockBetRepository{ctrl: ctrl}
+	mock.recorder = &MockBetRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBetRepository) EXPECT() *MockBetRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateBet mocks base method.
+func (m *MockBetRepository) CreateBet(tx *sql.Tx, ctx context.Context, bet *model.BetDao) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBet", tx, ctx, bet)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBet indicates an expected call of CreateBet.
+func (mr *MockBetRepositoryMockRecorder) CreateBet(tx, ctx, bet interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBet", reflect.TypeOf((*MockBetRepository)(nil).CreateBet), tx, ctx, bet)
+}
+
+// GetBetByBetID mocks base method.
+func (m *MockBetRepository) GetBetByBetID(tx *sql.Tx, ctx context.Context, betID string) (*model.BetDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBetByBetID", tx, ctx, betID)
+	ret0, _ := ret[0].(*model.BetDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBetByBetID indicates an expected call of GetBetByBetID.
+func (mr *MockBetRepositoryMockRecorder) GetBetByBetID(tx, ctx, betID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBetByBetID", reflect.TypeOf((*MockBetRepository)(nil).GetBetByBetID), tx, ctx, betID)
+}
+
+// GetBetForUpdate mocks base method.
+func (m *MockBetRepository) GetBetForUpdate(tx *sql.Tx, ctx context.Context, id string) (*model.BetDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBetForUpdate", tx, ctx, id)
+	ret0, _ := ret[0].(*model.BetDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBetForUpdate indicates an expected call of GetBetForUpdate.
+func (mr *MockBetRepositoryMockRecorder) GetBetForUpdate(tx, ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBetForUpdate", reflect.TypeOf((*MockBetRepository)(nil).GetBetForUpdate), tx, ctx, id)
+}
+
+// GetExpiredBets mocks base method.
+func (m *MockBetRepository) GetExpiredBets(ctx context.Context, limit int) ([]*model.BetDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredBets", ctx, limit)
+	ret0, _ := ret[0].([]*model.BetDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredBets indicates an expected call of GetExpiredBets.
+func (mr *MockBetRepositoryMockRecorder) GetExpiredBets(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredBets", reflect.TypeOf((*MockBetRepository)(nil).GetExpiredBets), ctx, limit)
+}
+
+// UpdateBet mocks base method.
+func (m *MockBetRepository) UpdateBet(tx *sql.Tx, ctx context.Context, bet *model.BetDao) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBet", tx, ctx, bet)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBet indicates an expected call of UpdateBet.
+func (mr *MockBetRepositoryMockRecorder) UpdateBet(tx, ctx, bet interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBet", reflect.TypeOf((*MockBetRepository)(nil).UpdateBet), tx, ctx, bet)
+}
@@ -0,0 +1,19 @@
+package bet
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+//go:generate mockgen -source ./repository.go -mock_names Repository=MockBetRepository -package mocks -destination mocks/betRepository.mock.gen.go
+
+// Repository is a repository for the two-phase bets of game rounds.
+type Repository interface {
+	CreateBet(tx *sql.Tx, ctx context.Context, bet *model.BetDao) error
+	GetBetByBetID(tx *sql.Tx, ctx context.Context, betID string) (*model.BetDao, error)
+	GetBetForUpdate(tx *sql.Tx, ctx context.Context, id string) (*model.BetDao, error)
+	UpdateBet(tx *sql.Tx, ctx context.Context, bet *model.BetDao) error
+	GetExpiredBets(ctx context.Context, limit int) ([]*model.BetDao, error)
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Bet is the repository for bets.
+type Bet struct {
+	conn *sqlx.DB
+}
+
+// New returns a new Bet object.
+func New(conn *sqlx.DB) *Bet {
+	return &Bet{
+		conn: conn,
+	}
+}
+
+// CreateBet inserts a placed bet within the given db tx.
+func (b *Bet) CreateBet(tx *sql.Tx, ctx context.Context, bet *model.BetDao) error {
+	query := `
+		INSERT INTO bets (user_id, currency, bet_id, stake, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, state, created_at;
+	`
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		bet.UserID,
+		bet.Currency,
+		bet.BetID,
+		bet.Stake,
+		bet.ExpiresAt,
+	).Scan(&bet.ID, &bet.State, &bet.CreatedAt)
+
+	if err != nil {
+		var pqError *pq.Error
+		if errors.As(err, &pqError) {
+			switch pqError.Constraint {
+			case "unique_bet_id":
+				return fmt.Errorf("failed to insert bet because of unique constraint: %w", model.ErrorBetAlreadyExists)
+			case "bets_wallet_fkey":
+				return fmt.Errorf("failed because the user has no %s wallet: %w", bet.Currency, model.ErrorWalletNotFound)
+			}
+		}
+
+		return fmt.Errorf("failed to execute insert bet query: %w", err)
+	}
+
+	return nil
+}
+
+// GetBetByBetID returns a bet by the betId given by the game server.
+func (b *Bet) GetBetByBetID(tx *sql.Tx, ctx context.Context, betID string) (*model.BetDao, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			currency,
+			bet_id,
+			stake,
+			state,
+			win_amount,
+			transaction_id,
+			created_at,
+			expires_at,
+			settled_at
+		FROM bets
+		WHERE bet_id = $1;
+	`
+
+	return scanBet(tx.QueryRowContext(ctx, query, betID))
+}
+
+// GetBetForUpdate returns a bet and locks the row until the given db tx ends.
+func (b *Bet) GetBetForUpdate(tx *sql.Tx, ctx context.Context, id string) (*model.BetDao, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			currency,
+			bet_id,
+			stake,
+			state,
+			win_amount,
+			transaction_id,
+			created_at,
+			expires_at,
+			settled_at
+		FROM bets
+		WHERE id = $1 FOR UPDATE;
+	`
+
+	return scanBet(tx.QueryRowContext(ctx, query, id))
+}
+
+// UpdateBet stores the outcome of a bet within the given db tx.
+func (b *Bet) UpdateBet(tx *sql.Tx, ctx context.Context, bet *model.BetDao) error {
+	query := `
+		UPDATE bets
+		SET state = $2,
+			win_amount = $3,
+			transaction_id = $4,
+			settled_at = $5
+		WHERE id = $1
+		RETURNING id;
+	`
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		bet.ID,
+		bet.State,
+		bet.WinAmount,
+		bet.TransactionID,
+		bet.SettledAt,
+	).Scan(&bet.ID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed because bet not found: %w", model.ErrorBetNotFound)
+		}
+
+		return fmt.Errorf("failed to execute update bet query: %w", err)
+	}
+
+	return nil
+}
+
+// GetExpiredBets returns the placed bets whose expiry has passed, the longest expired first.
+func (b *Bet) GetExpiredBets(ctx context.Context, limit int) ([]*model.BetDao, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			currency,
+			bet_id,
+			stake,
+			state,
+			win_amount,
+			transaction_id,
+			created_at,
+			expires_at,
+			settled_at
+		FROM bets
+		WHERE state = 'placed' AND expires_at <= NOW()
+		ORDER BY expires_at
+		LIMIT $1;
+	`
+	rows, err := b.conn.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get expired bets query: %w", err)
+	}
+
+	defer rows.Close()
+
+	bets := []*model.BetDao{}
+
+	for rows.Next() {
+		bet := &model.BetDao{}
+		err = rows.Scan(
+			&bet.ID,
+			&bet.UserID,
+			&bet.Currency,
+			&bet.BetID,
+			&bet.Stake,
+			&bet.State,
+			&bet.WinAmount,
+			&bet.TransactionID,
+			&bet.CreatedAt,
+			&bet.ExpiresAt,
+			&bet.SettledAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bet row: %w", err)
+		}
+
+		bets = append(bets, bet)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate bet rows: %w", err)
+	}
+
+	return bets, nil
+}
+
+func scanBet(row *sql.Row) (*model.BetDao, error) {
+	bet := &model.BetDao{}
+
+	err := row.Scan(
+		&bet.ID,
+		&bet.UserID,
+		&bet.Currency,
+		&bet.BetID,
+		&bet.Stake,
+		&bet.State,
+		&bet.WinAmount,
+		&bet.TransactionID,
+		&bet.CreatedAt,
+		&bet.ExpiresAt,
+		&bet.SettledAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed because bet not found: %w", model.ErrorBetNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to execute get bet query: %w", err)
+	}
+
+	return bet, nil
+}
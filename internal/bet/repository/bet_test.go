@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/ttagiyeva/entain/internal/bet/repository"
+	"github.com/ttagiyeva/entain/internal/database"
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/util"
+)
+
+const userID = "00000000-0000-0000-0000-000000000001"
+
+type betRepoTestSuite struct {
+	suite.Suite
+	testcontainers.Container
+	db   *database.Postgres
+	repo *repository.Bet
+	ctx  context.Context
+}
+
+func TestBetRepoTestSuite(t *testing.T) {
+	suite.Run(t, &betRepoTestSuite{})
+}
+
+func (b *betRepoTestSuite) SetupSuite() {
+	b.ctx = context.Background()
+	b.db = util.CreateTestContainer(b.ctx, &b.Suite)
+	b.repo = repository.New(b.db.Connection)
+}
+
+func (b *betRepoTestSuite) SetupTest() {
+	if err := b.db.MigrateUp(); err != nil || errors.Is(err, migrate.ErrNoChange) {
+		b.Require().NoError(err)
+	}
+}
+
+func (b *betRepoTestSuite) TearDownTest() {
+	b.NoError(b.db.MigrateDown())
+}
+
+func (b *betRepoTestSuite) TestCreateBet() {
+	tx := b.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
+
+	bet := &model.BetDao{
+		UserID:    userID,
+		Currency:  "EUR",
+		BetID:     faker.UUIDHyphenated(),
+		Stake:     100,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	b.Require().NoError(b.repo.CreateBet(tx, b.ctx, bet))
+	b.NotEmpty(bet.ID)
+	b.Equal(model.BetStatePlaced, bet.State)
+
+	stored, err := b.repo.GetBetByBetID(tx, b.ctx, bet.BetID)
+	b.NoError(err)
+	b.Equal(bet.ID, stored.ID)
+
+	err = b.repo.CreateBet(tx, b.ctx, &model.BetDao{UserID: userID, Currency: "USD", BetID: faker.UUIDHyphenated(), Stake: 100, ExpiresAt: time.Now()})
+	b.Equal(true, errors.Is(err, model.ErrorWalletNotFound))
+
+	_, err = b.repo.GetBetForUpdate(tx, b.ctx, faker.UUIDHyphenated())
+	b.Equal(true, errors.Is(err, model.ErrorBetNotFound))
+}
+
+func (b *betRepoTestSuite) TestCreateBetTwice() {
+	tx := b.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
+
+	bet := &model.BetDao{UserID: userID, Currency: "EUR", BetID: faker.UUIDHyphenated(), Stake: 100, ExpiresAt: time.Now()}
+	b.Require().NoError(b.repo.CreateBet(tx, b.ctx, bet))
+
+	err := b.repo.CreateBet(tx, b.ctx, bet)
+	b.Equal(true, errors.Is(err, model.ErrorBetAlreadyExists))
+}
+
+func (b *betRepoTestSuite) TestGetExpiredBets() {
+	tx := b.db.Connection.MustBegin().Tx
+
+	expired := &model.BetDao{UserID: userID, Currency: "EUR", BetID: faker.UUIDHyphenated(), Stake: 100, ExpiresAt: time.Now().Add(-time.Minute)}
+	b.Require().NoError(b.repo.CreateBet(tx, b.ctx, expired))
+
+	open := &model.BetDao{UserID: userID, Currency: "EUR", BetID: faker.UUIDHyphenated(), Stake: 100, ExpiresAt: time.Now().Add(time.Hour)}
+	b.Require().NoError(b.repo.CreateBet(tx, b.ctx, open))
+
+	settled := &model.BetDao{UserID: userID, Currency: "EUR", BetID: faker.UUIDHyphenated(), Stake: 100, ExpiresAt: time.Now().Add(-time.Minute)}
+	b.Require().NoError(b.repo.CreateBet(tx, b.ctx, settled))
+
+	now := time.Now()
+	settled.State = model.BetStateWon
+	settled.WinAmount = 50
+	settled.SettledAt = &now
+	b.Require().NoError(b.repo.UpdateBet(tx, b.ctx, settled))
+	b.Require().NoError(tx.Commit())
+
+	bets, err := b.repo.GetExpiredBets(b.ctx, 10)
+	b.NoError(err)
+	b.Len(bets, 1)
+	b.Equal(expired.ID, bets[0].ID)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -21,10 +22,81 @@ type logger struct {
 	Encoding string
 }
 
+// PostProcess represents a configuration of the transaction cancellation worker.
+type PostProcess struct {
+	Enabled   bool
+	Interval  time.Duration
+	BatchSize int
+	Jitter    time.Duration
+	LockKey   int64
+}
+
+// Limits represents a configuration of the responsible gambling limits.
+type Limits struct {
+	// CoolOff is the time a raised limit waits before it applies.
+	CoolOff time.Duration
+}
+
+// Registry represents a configuration of the registry of source types and states.
+type Registry struct {
+	// TTL is how long the registry is cached before it is loaded from the database again.
+	TTL time.Duration
+}
+
+// Bets represents a configuration of the two-phase bets and of their expiry worker.
+type Bets struct {
+	// TTL is how long the stake of a placed bet stays reserved before the bet expires.
+	TTL       time.Duration
+	Enabled   bool
+	Interval  time.Duration
+	BatchSize int
+}
+
+// Batch represents a configuration of the batch transaction ingestion.
+type Batch struct {
+	// MaxSize is the maximum number of transactions of a batch.
+	MaxSize int
+}
+
+// Import represents a configuration of the streaming import of transactions.
+type Import struct {
+	// ProgressEvery is the number of lines after which the progress of an import is reported.
+	ProgressEvery int
+}
+
+const (
+	// MigrateModeAuto runs the pending migrations on start.
+	MigrateModeAuto = "auto"
+	// MigrateModeCheckOnly refuses to start if migrations are pending, they are run with entainctl.
+	MigrateModeCheckOnly = "check-only"
+	// MigrateModeOff neither runs nor checks the migrations on start.
+	MigrateModeOff = "off"
+)
+
+// Migrate represents a configuration of the database migrations on start.
+type Migrate struct {
+	// Mode is one of auto, check-only and off.
+	Mode string
+}
+
+// Admin represents a configuration of the admin api for support staff.
+type Admin struct {
+	// Token is the bearer token of the admin api, the api refuses every request while it is empty.
+	Token string
+}
+
 // Config is the configuration for the application.
 type Config struct {
-	Logger logger
-	DB     DB
+	Logger      logger
+	DB          DB
+	PostProcess PostProcess
+	Limits      Limits
+	Registry    Registry
+	Bets        Bets
+	Batch       Batch
+	Import      Import
+	Admin       Admin
+	Migrate     Migrate
 }
 
 // New returns a new Config.
@@ -35,6 +107,21 @@ func New() *Config {
 	confer.SetEnvPrefix("entain")
 	confer.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
+	confer.SetDefault("postprocess.enabled", true)
+	confer.SetDefault("postprocess.interval", "1s")
+	confer.SetDefault("postprocess.batch_size", 10)
+	confer.SetDefault("postprocess.jitter", "0s")
+	confer.SetDefault("postprocess.lock_key", 7340001)
+	confer.SetDefault("limits.cool_off", "24h")
+	confer.SetDefault("registry.ttl", "1m")
+	confer.SetDefault("bets.ttl", "30m")
+	confer.SetDefault("bets.enabled", true)
+	confer.SetDefault("bets.interval", "10s")
+	confer.SetDefault("bets.batch_size", 10)
+	confer.SetDefault("batch.max_size", 100)
+	confer.SetDefault("import.progress_every", 1000)
+	confer.SetDefault("migrate.mode", MigrateModeAuto)
+
 	config := &Config{
 		Logger: logger{
 			Level:    confer.GetString("log.level"),
@@ -47,7 +134,51 @@ func New() *Config {
 			Password: confer.GetString("db.password"),
 			Name:     confer.GetString("db.name"),
 		},
+		PostProcess: PostProcess{
+			Enabled:   confer.GetBool("postprocess.enabled"),
+			Interval:  parseInterval(confer.GetString("postprocess.interval")),
+			BatchSize: confer.GetInt("postprocess.batch_size"),
+			Jitter:    confer.GetDuration("postprocess.jitter"),
+			LockKey:   confer.GetInt64("postprocess.lock_key"),
+		},
+		Limits: Limits{
+			CoolOff: confer.GetDuration("limits.cool_off"),
+		},
+		Registry: Registry{
+			TTL: confer.GetDuration("registry.ttl"),
+		},
+		Bets: Bets{
+			TTL:       confer.GetDuration("bets.ttl"),
+			Enabled:   confer.GetBool("bets.enabled"),
+			Interval:  parseInterval(confer.GetString("bets.interval")),
+			BatchSize: confer.GetInt("bets.batch_size"),
+		},
+		Batch: Batch{
+			MaxSize: confer.GetInt("batch.max_size"),
+		},
+		Import: Import{
+			ProgressEvery: confer.GetInt("import.progress_every"),
+		},
+		Admin: Admin{
+			Token: confer.GetString("admin.token"),
+		},
+		Migrate: Migrate{
+			Mode: confer.GetString("migrate.mode"),
+		},
 	}
 
 	return config
 }
+
+// parseInterval parses a duration such as "30s" or its cron-like form "@every 30s".
+// It returns zero for a malformed value.
+func parseInterval(value string) time.Duration {
+	value = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "@every"))
+
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+
+	return interval
+}
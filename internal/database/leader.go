@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+)
+
+// Leader elects a single instance of the service by a session level postgres advisory lock.
+// The lock is held on a dedicated connection, so postgres releases it as soon as that connection dies
+// and another instance takes over on its next attempt.
+type Leader struct {
+	postgres *Postgres
+	key      int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewLeader creates a new Leader competing for the advisory lock with the given key.
+func NewLeader(postgres *Postgres, key int64) *Leader {
+	return &Leader{
+		postgres: postgres,
+		key:      key,
+	}
+}
+
+// IsLeader reports whether this instance holds the lock, trying to acquire it when it does not.
+func (l *Leader) IsLeader(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		_, err := l.conn.ExecContext(ctx, "SELECT 1;")
+		if err == nil {
+			return true, nil
+		}
+
+		// The session is gone together with the lock, the connection must not be reused.
+		l.discard()
+	}
+
+	conn, err := l.postgres.Connection.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get a connection for leader election: %w", err)
+	}
+
+	var acquired bool
+
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1);", l.key).Scan(&acquired)
+	if err != nil {
+		conn.Close()
+
+		return false, fmt.Errorf("failed to execute try advisory lock query: %w", err)
+	}
+
+	if !acquired {
+		return false, conn.Close()
+	}
+
+	l.conn = conn
+
+	return true, nil
+}
+
+// Resign releases the lock if this instance holds it.
+func (l *Leader) Resign(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1);", l.key)
+	if err != nil {
+		l.discard()
+
+		return fmt.Errorf("failed to execute advisory unlock query: %w", err)
+	}
+
+	err = l.conn.Close()
+	l.conn = nil
+
+	if err != nil {
+		return fmt.Errorf("failed to close the leader connection: %w", err)
+	}
+
+	return nil
+}
+
+// discard closes the held connection without returning it to the pool.
+func (l *Leader) discard() {
+	_ = l.conn.Raw(func(interface{}) error {
+		return driver.ErrBadConn
+	})
+	_ = l.conn.Close()
+
+	l.conn = nil
+}
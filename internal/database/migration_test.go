@@ -0,0 +1,78 @@
+package database_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ttagiyeva/entain/internal/database"
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/util"
+)
+
+type migrationTestSuite struct {
+	suite.Suite
+	db  *database.Postgres
+	ctx context.Context
+}
+
+func TestMigrationTestSuite(t *testing.T) {
+	suite.Run(t, &migrationTestSuite{})
+}
+
+func (m *migrationTestSuite) SetupSuite() {
+	m.ctx = context.Background()
+	m.db = util.CreateTestContainer(m.ctx, &m.Suite)
+}
+
+func (m *migrationTestSuite) TestMigrationStatus() {
+	latest, err := database.LatestMigration()
+	m.Require().NoError(err)
+
+	// Nothing has been migrated yet, not even the table of the version exists.
+	status, err := m.db.MigrationStatus()
+	m.Require().NoError(err)
+	m.Equal(&model.MigrationStatus{Latest: latest}, status)
+
+	m.Require().NoError(m.db.MigrateUp())
+
+	// The health endpoint reads the status from concurrent requests.
+	var wg sync.WaitGroup
+
+	statuses := make([]*model.MigrationStatus, 20)
+	errs := make([]error, len(statuses))
+
+	for i := range statuses {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			statuses[i], errs[i] = m.db.MigrationStatus()
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i := range statuses {
+		m.Require().NoError(errs[i])
+		m.Equal(&model.MigrationStatus{Version: latest, Latest: latest}, statuses[i])
+	}
+
+	_, err = m.db.Connection.ExecContext(m.ctx, `UPDATE schema_migrations SET dirty = true`)
+	m.Require().NoError(err)
+
+	status, err = m.db.MigrationStatus()
+	m.Require().NoError(err)
+	m.Equal(&model.MigrationStatus{Version: latest, Latest: latest, Dirty: true}, status)
+
+	m.Require().NoError(m.db.MigrateForce(int(latest)))
+	m.Require().NoError(m.db.MigrateDown())
+
+	// Reverting every migration leaves the table without a version.
+	status, err = m.db.MigrationStatus()
+	m.Require().NoError(err)
+	m.Equal(&model.MigrationStatus{Latest: latest}, status)
+}
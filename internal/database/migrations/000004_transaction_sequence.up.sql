@@ -0,0 +1,17 @@
+BEGIN;
+
+    ALTER TABLE transactions ADD COLUMN seq BIGSERIAL;
+
+    UPDATE transactions t
+    SET seq = ordered.rn
+    FROM (
+        SELECT id, ROW_NUMBER() OVER (ORDER BY created_at, id) AS rn
+        FROM transactions
+    ) ordered
+    WHERE t.id = ordered.id;
+
+    SELECT setval(pg_get_serial_sequence('transactions', 'seq'), COALESCE(MAX(seq), 0) + 1, false) FROM transactions;
+
+    ALTER TABLE transactions ADD CONSTRAINT unique_transaction_seq UNIQUE (seq);
+
+COMMIT;
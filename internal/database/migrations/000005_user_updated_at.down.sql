@@ -0,0 +1,5 @@
+BEGIN;
+
+    ALTER TABLE users DROP COLUMN updated_at;
+
+COMMIT;
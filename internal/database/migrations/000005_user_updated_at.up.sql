@@ -0,0 +1,7 @@
+BEGIN;
+
+    ALTER TABLE users ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+    UPDATE users SET updated_at = COALESCE(created_at, CURRENT_TIMESTAMP);
+
+COMMIT;
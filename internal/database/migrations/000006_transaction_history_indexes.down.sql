@@ -0,0 +1,6 @@
+BEGIN;
+
+    DROP INDEX IF EXISTS transactions_user_created_at_idx;
+    DROP INDEX IF EXISTS transactions_user_seq_idx;
+
+COMMIT;
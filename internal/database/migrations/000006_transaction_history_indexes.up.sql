@@ -0,0 +1,6 @@
+BEGIN;
+
+    CREATE INDEX IF NOT EXISTS transactions_user_seq_idx ON transactions (user_id, seq DESC);
+    CREATE INDEX IF NOT EXISTS transactions_user_created_at_idx ON transactions (user_id, created_at);
+
+COMMIT;
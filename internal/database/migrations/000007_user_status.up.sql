@@ -0,0 +1,6 @@
+BEGIN;
+
+    ALTER TABLE users ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+        CONSTRAINT users_status_check CHECK (status IN ('active', 'suspended', 'closed'));
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+    CREATE TABLE IF NOT EXISTS
+        ledger_entries (
+            id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+            seq BIGSERIAL NOT NULL CONSTRAINT unique_ledger_entry_seq UNIQUE,
+            user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+            transaction_id UUID REFERENCES transactions (id) ON DELETE CASCADE,
+            reason VARCHAR(16) NOT NULL
+                CONSTRAINT ledger_entries_reason_check CHECK (reason IN ('transaction', 'cancellation', 'adjustment')),
+            amount NUMERIC(18,2) NOT NULL,
+            balance_before NUMERIC(18,2) NOT NULL,
+            balance_after NUMERIC(18,2) NOT NULL,
+            created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            CONSTRAINT ledger_entries_balance_check CHECK (balance_after = balance_before + amount)
+        );
+
+    CREATE INDEX IF NOT EXISTS ledger_entries_user_seq_idx ON ledger_entries (user_id, seq);
+
+    -- Balances which existed before the ledger are opened by an adjustment entry, so they add up.
+    INSERT INTO ledger_entries (user_id, reason, amount, balance_before, balance_after)
+    SELECT id, 'adjustment', balance, 0, balance
+    FROM users
+    WHERE balance <> 0;
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+    ALTER TABLE users ADD COLUMN balance NUMERIC(18,2) NOT NULL DEFAULT 0 CONSTRAINT users_balance_check CHECK (balance >= 0);
+
+    -- Only euro balances have a place to go back to.
+    UPDATE users u
+    SET balance = w.balance
+    FROM wallets w
+    WHERE w.user_id = u.id AND w.currency = 'EUR';
+
+    ALTER TABLE ledger_entries DROP COLUMN currency;
+    ALTER TABLE transactions DROP COLUMN currency;
+
+    DROP TABLE IF EXISTS wallets;
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+    CREATE TABLE IF NOT EXISTS
+        wallets (
+            user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+            currency VARCHAR(3) NOT NULL,
+            balance NUMERIC(18,2) NOT NULL DEFAULT 0 CONSTRAINT wallets_balance_check CHECK (balance >= 0),
+            created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            PRIMARY KEY (user_id, currency)
+        );
+
+    -- Balances of the single currency era are kept in euro.
+    INSERT INTO wallets (user_id, currency, balance, created_at, updated_at)
+    SELECT id, 'EUR', balance, COALESCE(created_at, NOW()), updated_at
+    FROM users;
+
+    ALTER TABLE transactions ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'EUR';
+    ALTER TABLE transactions ALTER COLUMN currency DROP DEFAULT;
+
+    ALTER TABLE ledger_entries ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'EUR';
+    ALTER TABLE ledger_entries ALTER COLUMN currency DROP DEFAULT;
+
+    ALTER TABLE users DROP COLUMN balance;
+
+COMMIT;
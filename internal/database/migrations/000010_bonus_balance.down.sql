@@ -0,0 +1,20 @@
+BEGIN;
+
+    -- Bonus funds do not exist without this migration, converted money stays as an adjustment of real money.
+    DELETE FROM ledger_entries WHERE bucket = 'bonus';
+    UPDATE ledger_entries SET reason = 'adjustment' WHERE reason IN ('bonus', 'conversion');
+
+    ALTER TABLE ledger_entries
+        DROP CONSTRAINT ledger_entries_reason_check,
+        ADD CONSTRAINT ledger_entries_reason_check
+            CHECK (reason IN ('transaction', 'cancellation', 'adjustment'));
+
+    ALTER TABLE ledger_entries DROP COLUMN bucket;
+    ALTER TABLE transactions DROP COLUMN bonus_amount;
+
+    ALTER TABLE wallets
+        DROP COLUMN bonus_balance,
+        DROP COLUMN wagering_required,
+        DROP COLUMN wagered;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+    ALTER TABLE wallets
+        ADD COLUMN bonus_balance NUMERIC(18,2) NOT NULL DEFAULT 0
+            CONSTRAINT wallets_bonus_balance_check CHECK (bonus_balance >= 0),
+        ADD COLUMN wagering_required NUMERIC(18,2) NOT NULL DEFAULT 0
+            CONSTRAINT wallets_wagering_required_check CHECK (wagering_required >= 0),
+        ADD COLUMN wagered NUMERIC(18,2) NOT NULL DEFAULT 0
+            CONSTRAINT wallets_wagered_check CHECK (wagered >= 0);
+
+    ALTER TABLE transactions ADD COLUMN bonus_amount NUMERIC(18,2) NOT NULL DEFAULT 0
+        CONSTRAINT transactions_bonus_amount_check CHECK (bonus_amount >= 0 AND bonus_amount <= amount);
+
+    ALTER TABLE ledger_entries ADD COLUMN bucket VARCHAR(8) NOT NULL DEFAULT 'real'
+        CONSTRAINT ledger_entries_bucket_check CHECK (bucket IN ('real', 'bonus'));
+
+    ALTER TABLE ledger_entries
+        DROP CONSTRAINT ledger_entries_reason_check,
+        ADD CONSTRAINT ledger_entries_reason_check
+            CHECK (reason IN ('transaction', 'cancellation', 'adjustment', 'bonus', 'conversion'));
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+    DROP INDEX IF EXISTS transactions_user_currency_created_at_idx;
+    DROP TABLE IF EXISTS limits;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+    CREATE TABLE IF NOT EXISTS
+        limits (
+            user_id UUID NOT NULL,
+            currency VARCHAR(3) NOT NULL,
+            kind VARCHAR(8) NOT NULL CONSTRAINT limits_kind_check CHECK (kind IN ('loss', 'deposit')),
+            period VARCHAR(8) NOT NULL CONSTRAINT limits_period_check CHECK (period IN ('day', 'week', 'month')),
+            amount NUMERIC(18,2) NOT NULL CONSTRAINT limits_amount_check CHECK (amount >= 0),
+            -- A raised limit only applies from pending_from on, a lowered one applies at once.
+            pending_amount NUMERIC(18,2) CONSTRAINT limits_pending_amount_check CHECK (pending_amount >= 0),
+            pending_from TIMESTAMP WITH TIME ZONE,
+            created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            PRIMARY KEY (user_id, currency, kind, period),
+            CONSTRAINT limits_wallet_fkey FOREIGN KEY (user_id, currency) REFERENCES wallets (user_id, currency) ON DELETE CASCADE,
+            CONSTRAINT limits_pending_check CHECK ((pending_amount IS NULL) = (pending_from IS NULL))
+        );
+
+    CREATE INDEX IF NOT EXISTS transactions_user_currency_created_at_idx ON transactions (user_id, currency, created_at);
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+    -- Rows are never updated or deleted, the table is the audit trail of the exclusions of a user.
+    CREATE TABLE IF NOT EXISTS
+        exclusions (
+            id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+            user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+            period VARCHAR(16) NOT NULL CONSTRAINT exclusions_period_check CHECK (period IN ('24h', '7d', 'permanent')),
+            started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            -- A permanent exclusion never ends.
+            ends_at TIMESTAMP WITH TIME ZONE,
+            CONSTRAINT exclusions_ends_at_check CHECK ((period = 'permanent') = (ends_at IS NULL))
+        );
+
+    CREATE INDEX IF NOT EXISTS exclusions_user_started_at_idx ON exclusions (user_id, started_at);
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+    -- Registered values the enums cannot hold fall back to the enum value with the same effect on the balance,
+    -- transactions which did not move the balance have no such value and are dropped.
+    DELETE FROM transactions t USING states s WHERE s.name = t.state AND s.direction = 'none';
+
+    UPDATE transactions t
+    SET state = CASE s.direction WHEN 'credit' THEN 'win' ELSE 'lost' END
+    FROM states s
+    WHERE s.name = t.state AND t.state NOT IN ('win', 'lost');
+
+    UPDATE transactions SET source_type = 'server' WHERE source_type NOT IN ('game', 'server', 'payment');
+
+    CREATE TYPE source_type AS ENUM ('game', 'server', 'payment');
+    CREATE TYPE state AS ENUM ('win', 'lost');
+
+    ALTER TABLE transactions
+        DROP CONSTRAINT transactions_source_type_fkey,
+        DROP CONSTRAINT transactions_state_fkey,
+        ALTER COLUMN source_type TYPE source_type USING source_type::source_type,
+        ALTER COLUMN state TYPE state USING state::state;
+
+    DROP TABLE IF EXISTS states;
+    DROP TABLE IF EXISTS source_types;
+
+COMMIT;
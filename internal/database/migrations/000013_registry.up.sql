@@ -0,0 +1,30 @@
+BEGIN;
+
+    CREATE TABLE IF NOT EXISTS
+        source_types (
+            name VARCHAR(32) PRIMARY KEY,
+            created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );
+
+    -- The direction of a state is how a transaction in it moves the wallet,
+    -- it must not be changed once transactions in the state exist.
+    CREATE TABLE IF NOT EXISTS
+        states (
+            name VARCHAR(32) PRIMARY KEY,
+            direction VARCHAR(8) NOT NULL CONSTRAINT states_direction_check CHECK (direction IN ('credit', 'debit', 'none')),
+            created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );
+
+    INSERT INTO source_types (name) VALUES ('game'), ('server'), ('payment');
+    INSERT INTO states (name, direction) VALUES ('win', 'credit'), ('lost', 'debit');
+
+    ALTER TABLE transactions
+        ALTER COLUMN source_type TYPE VARCHAR(32) USING source_type::text,
+        ALTER COLUMN state TYPE VARCHAR(32) USING state::text,
+        ADD CONSTRAINT transactions_source_type_fkey FOREIGN KEY (source_type) REFERENCES source_types (name),
+        ADD CONSTRAINT transactions_state_fkey FOREIGN KEY (state) REFERENCES states (name);
+
+    DROP TYPE source_type;
+    DROP TYPE state;
+
+COMMIT;
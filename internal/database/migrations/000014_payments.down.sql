@@ -0,0 +1,17 @@
+BEGIN;
+
+    DROP INDEX IF EXISTS transactions_pending_payments_idx;
+
+    -- Payments which never moved money are dropped, the others are kept as payment wins and losses.
+    DELETE FROM transactions WHERE state IN ('deposit_pending', 'deposit_failed', 'withdrawal_pending', 'withdrawal_failed');
+    UPDATE transactions SET state = 'win' WHERE state = 'deposit_confirmed';
+    UPDATE transactions SET state = 'lost' WHERE state = 'withdrawal_confirmed';
+
+    DELETE FROM states WHERE name IN (
+        'deposit_pending', 'deposit_confirmed', 'deposit_failed',
+        'withdrawal_pending', 'withdrawal_confirmed', 'withdrawal_failed'
+    );
+
+    ALTER TABLE wallets DROP COLUMN reserved;
+
+COMMIT;
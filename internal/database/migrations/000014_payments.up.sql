@@ -0,0 +1,19 @@
+BEGIN;
+
+    -- Funds of pending withdrawals stay in the balance but cannot be spent.
+    ALTER TABLE wallets
+        ADD COLUMN reserved NUMERIC(18,2) NOT NULL DEFAULT 0
+            CONSTRAINT wallets_reserved_check CHECK (reserved >= 0 AND reserved <= balance);
+
+    INSERT INTO states (name, direction) VALUES
+        ('deposit_pending', 'none'),
+        ('deposit_confirmed', 'credit'),
+        ('deposit_failed', 'none'),
+        ('withdrawal_pending', 'none'),
+        ('withdrawal_confirmed', 'debit'),
+        ('withdrawal_failed', 'none');
+
+    CREATE INDEX IF NOT EXISTS transactions_pending_payments_idx ON transactions (created_at)
+        WHERE state IN ('deposit_pending', 'withdrawal_pending');
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+    -- Stakes of open bets are no longer held once the bets are gone.
+    UPDATE wallets w
+    SET reserved = w.reserved - b.stake
+    FROM (
+        SELECT user_id, currency, SUM(stake) AS stake
+        FROM bets
+        WHERE state = 'placed'
+        GROUP BY user_id, currency
+    ) b
+    WHERE w.user_id = b.user_id AND w.currency = b.currency;
+
+    DROP TABLE IF EXISTS bets;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+    CREATE TABLE IF NOT EXISTS
+        bets (
+            id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+            user_id UUID NOT NULL,
+            currency VARCHAR(3) NOT NULL,
+            -- Settlement transactions use the bet_id with a "bet:" prefix as their transaction_id.
+            bet_id VARCHAR(96) NOT NULL CONSTRAINT unique_bet_id UNIQUE,
+            stake NUMERIC(18,2) NOT NULL CONSTRAINT bets_stake_check CHECK (stake > 0),
+            state VARCHAR(16) NOT NULL DEFAULT 'placed'
+                CONSTRAINT bets_state_check CHECK (state IN ('placed', 'won', 'lost', 'void', 'expired')),
+            win_amount NUMERIC(18,2) NOT NULL DEFAULT 0 CONSTRAINT bets_win_amount_check CHECK (win_amount >= 0),
+            -- The transaction the bet was settled with, a void or expired bet and a win of nothing have none.
+            transaction_id UUID REFERENCES transactions (id) ON DELETE SET NULL,
+            created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+            settled_at TIMESTAMP WITH TIME ZONE,
+            CONSTRAINT bets_wallet_fkey FOREIGN KEY (user_id, currency) REFERENCES wallets (user_id, currency) ON DELETE CASCADE
+        );
+
+    CREATE INDEX IF NOT EXISTS bets_placed_expires_at_idx ON bets (expires_at) WHERE state = 'placed';
+
+COMMIT;
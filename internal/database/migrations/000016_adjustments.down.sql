@@ -0,0 +1,15 @@
+BEGIN;
+
+    ALTER TABLE transactions
+        DROP CONSTRAINT IF EXISTS transactions_adjustment_check,
+        DROP COLUMN IF EXISTS operator_id,
+        DROP COLUMN IF EXISTS reason;
+
+    -- Adjustments are kept as server wins and losses, so the balances still add up.
+    UPDATE transactions SET source_type = 'server', state = 'win' WHERE state = 'adjustment_credit';
+    UPDATE transactions SET source_type = 'server', state = 'lost' WHERE state = 'adjustment_debit';
+
+    DELETE FROM states WHERE name IN ('adjustment_credit', 'adjustment_debit');
+    DELETE FROM source_types WHERE name = 'adjustment';
+
+COMMIT;
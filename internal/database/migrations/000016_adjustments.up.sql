@@ -0,0 +1,13 @@
+BEGIN;
+
+    INSERT INTO source_types (name) VALUES ('adjustment');
+    INSERT INTO states (name, direction) VALUES ('adjustment_credit', 'credit'), ('adjustment_debit', 'debit');
+
+    -- Manual adjustments of support staff must tell who made them and why, other transactions have neither.
+    ALTER TABLE transactions
+        ADD COLUMN operator_id VARCHAR(64),
+        ADD COLUMN reason VARCHAR(500),
+        ADD CONSTRAINT transactions_adjustment_check
+            CHECK ((source_type = 'adjustment') = (operator_id IS NOT NULL AND reason IS NOT NULL));
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+    DROP TABLE IF EXISTS transfers;
+
+    -- Transfers are kept as server wins and losses, so the balances still add up.
+    UPDATE transactions SET source_type = 'server', state = 'lost' WHERE state = 'transfer_out';
+    UPDATE transactions SET source_type = 'server', state = 'win' WHERE state = 'transfer_in';
+
+    DELETE FROM states WHERE name IN ('transfer_out', 'transfer_in');
+    DELETE FROM source_types WHERE name = 'transfer';
+
+COMMIT;
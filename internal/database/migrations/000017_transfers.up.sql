@@ -0,0 +1,21 @@
+BEGIN;
+
+    INSERT INTO source_types (name) VALUES ('transfer');
+    INSERT INTO states (name, direction) VALUES ('transfer_out', 'debit'), ('transfer_in', 'credit');
+
+    -- A transfer is recorded as a debiting transaction of the sender and a crediting one of the receiver.
+    CREATE TABLE IF NOT EXISTS
+        transfers (
+            id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+            idempotency_key VARCHAR(64) NOT NULL CONSTRAINT unique_idempotency_key UNIQUE,
+            from_user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+            to_user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+            amount NUMERIC(18,2) NOT NULL CONSTRAINT transfers_amount_check CHECK (amount > 0),
+            currency VARCHAR(3) NOT NULL,
+            debit_transaction_id UUID NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+            credit_transaction_id UUID NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+            created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            CONSTRAINT transfers_users_check CHECK (from_user_id <> to_user_id)
+        );
+
+COMMIT;
@@ -4,18 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/ttagiyeva/entain/internal/config"
+	"github.com/ttagiyeva/entain/internal/model"
 )
 
 //go:embed migrations/*.sql
-var fs embed.FS
+var migrations embed.FS
 
 type Postgres struct {
 	Connection *sqlx.DB
@@ -48,7 +52,7 @@ func (p *Postgres) Connect(ctx context.Context, config *config.Config) error {
 
 	p.Connection = conn
 
-	d, err := iofs.New(fs, "migrations")
+	d, err := iofs.New(migrations, "migrations")
 	if err != nil {
 		return err
 	}
@@ -81,6 +85,95 @@ func (p *Postgres) MigrateDown() error {
 	return p.m.Down()
 }
 
+// MigrateForce sets the migration version without running any migration and clears the dirty flag,
+// it is the way out of a migration which failed halfway once the database has been fixed by hand.
+func (p *Postgres) MigrateForce(version int) error {
+	return p.m.Force(version)
+}
+
+// MigrationStatus returns the migration version of the database and the latest migration of the service.
+// The version is read through the connection pool, as the migrate instance is not safe for concurrent use.
+func (p *Postgres) MigrationStatus() (*model.MigrationStatus, error) {
+	latest, err := LatestMigration()
+	if err != nil {
+		return nil, err
+	}
+
+	var version int64
+
+	var dirty bool
+
+	err = p.Connection.QueryRow(`SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		var pqError *pq.Error
+
+		// No migration has been applied until migrate creates the table and records a version in it.
+		if errors.Is(err, sql.ErrNoRows) || errors.As(err, &pqError) && pqError.Code == "42P01" {
+			return &model.MigrationStatus{Latest: latest}, nil
+		}
+
+		return nil, fmt.Errorf("failed to get migration version: %w", err)
+	}
+
+	return &model.MigrationStatus{Version: uint(max(version, 0)), Latest: latest, Dirty: dirty}, nil
+}
+
+// Migrate brings the schema of the database up to date according to the migrate mode, it returns
+// an error if the service cannot serve with the schema.
+func (p *Postgres) Migrate(mode string) error {
+	switch mode {
+	case config.MigrateModeOff:
+		return nil
+	case config.MigrateModeAuto:
+		err := p.MigrateUp()
+		if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return fmt.Errorf("failed to migrate up: %w", err)
+		}
+	case config.MigrateModeCheckOnly:
+	default:
+		return fmt.Errorf("unknown migrate mode %q", mode)
+	}
+
+	status, err := p.MigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	return status.Check()
+}
+
+// LatestMigration returns the version of the latest migration embedded in the service.
+func LatestMigration() (uint, error) {
+	d, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	defer d.Close()
+
+	version, err := d.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	for {
+		next, err := d.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+
+		version = next
+	}
+}
+
+// Close closes the connection to the database.
+func (p *Postgres) Close() error {
+	return p.Connection.Close()
+}
+
 // Ping verifies a connection to the database is still alive, establishing a connection if necessary.
 func (p *Postgres) Ping() error {
 	return p.Connection.Ping()
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestMigration(t *testing.T) {
+	entries, err := fs.ReadDir(migrations, "migrations")
+	require.NoError(t, err)
+
+	// The version of a migration is the number its file name starts with.
+	var expected uint
+	for _, e := range entries {
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		require.True(t, ok, e.Name())
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		require.NoError(t, err, e.Name())
+
+		expected = max(expected, uint(version))
+	}
+
+	require.NotZero(t, expected)
+
+	latest, err := LatestMigration()
+	require.NoError(t, err)
+	require.Equal(t, expected, latest)
+}
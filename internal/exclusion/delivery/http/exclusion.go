@@ -0,0 +1,85 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ttagiyeva/entain/internal/exclusion"
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Handler is a structure which manages exclusion http handlers.
+type Handler struct {
+	log     *slog.Logger
+	usecase exclusion.Usecase
+}
+
+// NewHandler creates a new exclusion http handler.
+func NewHandler(log *slog.Logger, u exclusion.Usecase) *Handler {
+	return &Handler{
+		log:     log,
+		usecase: u,
+	}
+}
+
+// StartExclusion excludes the user from playing for the requested period.
+func (h *Handler) StartExclusion(ctx echo.Context) error {
+	req := &model.StartExclusion{}
+
+	err := ctx.Bind(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: model.ErrorBadRequest,
+		})
+	}
+
+	req.UserID = ctx.Param("id")
+
+	err = model.NewValidator().Struct(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Value of the Period field must be one of '24h 7d permanent'",
+		})
+	}
+
+	e, err := h.usecase.StartExclusion(ctx.Request().Context(), req)
+	if err != nil {
+		h.log.With("body", req).Error("failed to start exclusion", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusCreated, e)
+}
+
+// GetExclusions returns every exclusion the user has started.
+func (h *Handler) GetExclusions(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	exclusions, err := h.usecase.GetExclusions(ctx.Request().Context(), id)
+	if err != nil {
+		h.log.With("id", id).Error("failed to get exclusions", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusOK, exclusions)
+}
+
+func getError(err error) model.Error {
+	switch {
+	case errors.Is(err, model.ErrorUserNotFound):
+		return model.Error{Code: http.StatusNotFound, Message: model.ErrorUserNotFound.Error()}
+	default:
+		return model.Error{Code: http.StatusInternalServerError, Message: model.ErrorInternalServerError.Error()}
+	}
+}
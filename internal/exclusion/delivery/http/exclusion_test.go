@@ -0,0 +1,121 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ttagiyeva/entain/internal/exclusion/mocks"
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// TestExclusionHandler_StartExclusion tests the exclusion handler start exclusion method.
+func TestExclusionHandler_StartExclusion(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(exclusionUsecase *mocks.MockExclusionUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name: "OK",
+			body: []byte(`{"period":"7d"}`),
+			buildStubs: func(exclusionUsecase *mocks.MockExclusionUsecase) {
+				exclusionUsecase.EXPECT().StartExclusion(gomock.Any(), &model.StartExclusion{UserID: "1", Period: "7d"}).
+					Return(&model.Exclusion{ID: "1", Period: "7d"}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:       "Invalid request body",
+			body:       []byte(`{"period":7}`),
+			buildStubs: func(exclusionUsecase *mocks.MockExclusionUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: model.ErrorBadRequest,
+			},
+		},
+		{
+			name:       "Invalid period",
+			body:       []byte(`{"period":"1h"}`),
+			buildStubs: func(exclusionUsecase *mocks.MockExclusionUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the Period field must be one of '24h 7d permanent'",
+			},
+		},
+		{
+			name: "User not found",
+			body: []byte(`{"period":"permanent"}`),
+			buildStubs: func(exclusionUsecase *mocks.MockExclusionUsecase) {
+				exclusionUsecase.EXPECT().StartExclusion(gomock.Any(), gomock.Any()).Return(nil, model.ErrorUserNotFound)
+			},
+			expectedError: getError(model.ErrorUserNotFound),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			exclusionUsecase := mocks.NewMockExclusionUsecase(ctrl)
+			tc.buildStubs(exclusionUsecase)
+
+			handler := NewHandler(slog.Default(), exclusionUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/users/1/exclusions", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			err := handler.StartExclusion(c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+// TestExclusionHandler_GetExclusions tests the exclusion handler get exclusions method.
+func TestExclusionHandler_GetExclusions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exclusionUsecase := mocks.NewMockExclusionUsecase(ctrl)
+	exclusionUsecase.EXPECT().GetExclusions(gomock.Any(), "1").Return([]*model.Exclusion{{ID: "1", Period: "permanent"}}, nil)
+
+	handler := NewHandler(slog.Default(), exclusionUsecase)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users/1/exclusions", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	require.NoError(t, handler.GetExclusions(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[{"id":"1","period":"permanent","startedAt":"0001-01-01T00:00:00Z"}]`, rec.Body.String())
+}
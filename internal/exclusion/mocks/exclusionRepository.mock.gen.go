@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/ttagiyeva/entain/internal/model"
+)
+
+// MockExclusionRepository is a mock of Repository interface.
+type MockExclusionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExclusionRepositoryMockRecorder
+}
+
+// MockExclusionRepositoryMockRecorder is the mock recorder for MockExclusionRepository.
+type MockExclusionRepositoryMockRecorder struct {
+	mock *MockExclusionRepository
+}
+
+// NewMockExclusionRepository creates a new mock instance.
+func NewMockExclusionRepository(ctrl *gomock.Controller) *MockExclusionRepository {
+	mock := &MockExclusionRepository{ctrl: ctrl}
+	mock.recorder = &MockExclusionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExclusionRepository) EXPECT() *MockExclusionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateExclusion mocks base method.
+func (m *MockExclusionRepository) CreateExclusion(ctx context.Context, exclusion *model.ExclusionDao) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExclusion", ctx, exclusion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExclusion indicates an expected call of CreateExclusion.
+func (mr *MockExclusionRepositoryMockRecorder) CreateExclusion(ctx, exclusion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExclusion", reflect.TypeOf((*MockExclusionRepository)(nil).CreateExclusion), ctx, exclusion)
+}
+
+// GetActiveExclusion mocks base method.
+func (m *MockExclusionRepository) GetActiveExclusion(tx *sql.Tx, ctx context.Context, userID string) (*model.ExclusionDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveExclusion", tx, ctx, userID)
+	ret0, _ := ret[0].(*model.ExclusionDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveExclusion indicates an expected call of GetActiveExclusion.
+func (mr *MockExclusionRepositoryMockRecorder) GetActiveExclusion(tx, ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveExclusion", reflect.TypeOf((*MockExclusionRepository)(nil).GetActiveExclusion), tx, ctx, userID)
+}
+
+// GetExclusions mocks base method.
+func (m *MockExclusionRepository) GetExclusions(ctx context.Context, userID string) ([]*model.ExclusionDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExclusions", ctx, userID)
+	ret0, _ := ret[0].([]*model.ExclusionDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExclusions indicates an expected call of GetExclusions.
+func (mr *MockExclusionRepositoryMockRecorder) GetExclusions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExclusions", reflect.TypeOf((*MockExclusionRepository)(nil).GetExclusions), ctx, userID)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/ttagiyeva/entain/internal/model"
+)
+
+// MockExclusionUsecase is a mock of Usecase interface.
+type MockExclusionUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockExclusionUsecaseMockRecorder
+}
+
+// MockExclusionUsecaseMockRecorder is the mock recorder for MockExclusionUsecase.
+type MockExclusionUsecaseMockRecorder struct {
+	mock *MockExclusionUsecase
+}
+
+// NewMockExclusionUsecase creates a new mock instance.
+func NewMockExclusionUsecase(ctrl *gomock.Controller) *MockExclusionUsecase {
+	mock := &MockExclusionUsecase{ctrl: ctrl}
+	mock.recorder = &MockExclusionUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExclusionUsecase) EXPECT() *MockExclusionUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetExclusions mocks base method.
+func (m *MockExclusionUsecase) GetExclusions(ctx context.Context, userID string) ([]*model.Exclusion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExclusions", ctx, userID)
+	ret0, _ := ret[0].([]*model.Exclusion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExclusions indicates an expected call of GetExclusions.
+func (mr *MockExclusionUsecaseMockRecorder) GetExclusions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExclusions", reflect.TypeOf((*MockExclusionUsecase)(nil).GetExclusions), ctx, userID)
+}
+
+// StartExclusion mocks base method.
+func (m *MockExclusionUsecase) StartExclusion(ctx context.Context, req *model.StartExclusion) (*model.Exclusion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartExclusion", ctx, req)
+	ret0, _ := ret[0].(*model.Exclusion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartExclusion indicates an expected call of StartExclusion.
+func (mr *MockExclusionUsecaseMockRecorder) StartExclusion(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartExclusion", reflect.TypeOf((*MockExclusionUsecase)(nil).StartExclusion), ctx, req)
+}
@@ -0,0 +1,17 @@
+package exclusion
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+//go:generate mockgen -source ./repository.go -mock_names Repository=MockExclusionRepository -package mocks -destination mocks/exclusionRepository.mock.gen.go
+
+// Repository is a repository for self-exclusions.
+type Repository interface {
+	CreateExclusion(ctx context.Context, exclusion *model.ExclusionDao) error
+	GetExclusions(ctx context.Context, userID string) ([]*model.ExclusionDao, error)
+	GetActiveExclusion(tx *sql.Tx, ctx context.Context, userID string) (*model.ExclusionDao, error)
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Exclusion is the repository for self-exclusions.
+type Exclusion struct {
+	conn *sqlx.DB
+}
+
+// New returns a new Exclusion object.
+func New(conn *sqlx.DB) *Exclusion {
+	return &Exclusion{
+		conn: conn,
+	}
+}
+
+// CreateExclusion inserts an exclusion, exclusions are never changed once inserted.
+func (e *Exclusion) CreateExclusion(ctx context.Context, exclusion *model.ExclusionDao) error {
+	query := `
+		INSERT INTO exclusions (user_id, period, started_at, ends_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id;
+	`
+	err := e.conn.QueryRowContext(
+		ctx,
+		query,
+		exclusion.UserID,
+		exclusion.Period,
+		exclusion.StartedAt,
+		exclusion.EndsAt,
+	).Scan(&exclusion.ID)
+
+	if err != nil {
+		var pqError *pq.Error
+		if errors.As(err, &pqError) && pqError.Constraint == "exclusions_user_id_fkey" {
+			return fmt.Errorf("failed because user not found: %w", model.ErrorUserNotFound)
+		}
+
+		return fmt.Errorf("failed to execute insert exclusion query: %w", err)
+	}
+
+	return nil
+}
+
+// GetExclusions returns every exclusion a user has started, the latest first.
+func (e *Exclusion) GetExclusions(ctx context.Context, userID string) ([]*model.ExclusionDao, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			period,
+			started_at,
+			ends_at
+		FROM exclusions
+		WHERE user_id = $1
+		ORDER BY started_at DESC, id;
+	`
+	rows, err := e.conn.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get exclusions query: %w", err)
+	}
+
+	defer rows.Close()
+
+	exclusions := []*model.ExclusionDao{}
+
+	for rows.Next() {
+		exclusion := &model.ExclusionDao{}
+		err = rows.Scan(
+			&exclusion.ID,
+			&exclusion.UserID,
+			&exclusion.Period,
+			&exclusion.StartedAt,
+			&exclusion.EndsAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan exclusion row: %w", err)
+		}
+
+		exclusions = append(exclusions, exclusion)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate exclusion rows: %w", err)
+	}
+
+	return exclusions, nil
+}
+
+// GetActiveExclusion returns the exclusion of a user which is in effect and ends last.
+func (e *Exclusion) GetActiveExclusion(tx *sql.Tx, ctx context.Context, userID string) (*model.ExclusionDao, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			period,
+			started_at,
+			ends_at
+		FROM exclusions
+		WHERE user_id = $1 AND started_at <= NOW() AND (ends_at IS NULL OR ends_at > NOW())
+		ORDER BY ends_at DESC NULLS FIRST
+		LIMIT 1;
+	`
+	exclusion := &model.ExclusionDao{}
+
+	err := tx.QueryRowContext(ctx, query, userID).Scan(
+		&exclusion.ID,
+		&exclusion.UserID,
+		&exclusion.Period,
+		&exclusion.StartedAt,
+		&exclusion.EndsAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed because the user has no exclusion in effect: %w", model.ErrorExclusionNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to execute get active exclusion query: %w", err)
+	}
+
+	return exclusion, nil
+}
@@ -0,0 +1,107 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/ttagiyeva/entain/internal/database"
+	"github.com/ttagiyeva/entain/internal/exclusion/repository"
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/util"
+)
+
+const userID = "00000000-0000-0000-0000-000000000001"
+
+type exclusionRepoTestSuite struct {
+	suite.Suite
+	testcontainers.Container
+	db   *database.Postgres
+	repo *repository.Exclusion
+	ctx  context.Context
+}
+
+func TestExclusionRepoTestSuite(t *testing.T) {
+	suite.Run(t, &exclusionRepoTestSuite{})
+}
+
+func (e *exclusionRepoTestSuite) SetupSuite() {
+	e.ctx = context.Background()
+	e.db = util.CreateTestContainer(e.ctx, &e.Suite)
+	e.repo = repository.New(e.db.Connection)
+}
+
+func (e *exclusionRepoTestSuite) SetupTest() {
+	if err := e.db.MigrateUp(); err != nil || errors.Is(err, migrate.ErrNoChange) {
+		e.Require().NoError(err)
+	}
+}
+
+func (e *exclusionRepoTestSuite) TearDownTest() {
+	e.NoError(e.db.MigrateDown())
+}
+
+func (e *exclusionRepoTestSuite) TestCreateExclusion() {
+	err := e.repo.CreateExclusion(e.ctx, &model.ExclusionDao{
+		UserID:    faker.UUIDHyphenated(),
+		Period:    model.ExclusionPeriodPermanent,
+		StartedAt: time.Now(),
+	})
+	e.Equal(true, errors.Is(err, model.ErrorUserNotFound))
+
+	// A time-out must have an end.
+	err = e.repo.CreateExclusion(e.ctx, &model.ExclusionDao{
+		UserID:    userID,
+		Period:    model.ExclusionPeriodDay,
+		StartedAt: time.Now(),
+	})
+	e.Error(err)
+}
+
+func (e *exclusionRepoTestSuite) TestGetActiveExclusion() {
+	past := time.Now().Add(-48 * time.Hour)
+	e.Require().NoError(e.repo.CreateExclusion(e.ctx, &model.ExclusionDao{
+		UserID:    userID,
+		Period:    model.ExclusionPeriodDay,
+		StartedAt: past,
+		EndsAt:    model.ExclusionEnd(model.ExclusionPeriodDay, past),
+	}))
+
+	tx := e.db.Connection.MustBegin().Tx
+	_, err := e.repo.GetActiveExclusion(tx, e.ctx, userID)
+	e.Equal(true, errors.Is(err, model.ErrorExclusionNotFound))
+	e.NoError(tx.Rollback())
+
+	now := time.Now()
+	week := &model.ExclusionDao{
+		UserID:    userID,
+		Period:    model.ExclusionPeriodWeek,
+		StartedAt: now,
+		EndsAt:    model.ExclusionEnd(model.ExclusionPeriodWeek, now),
+	}
+	e.Require().NoError(e.repo.CreateExclusion(e.ctx, week))
+	e.Require().NoError(e.repo.CreateExclusion(e.ctx, &model.ExclusionDao{
+		UserID:    userID,
+		Period:    model.ExclusionPeriodDay,
+		StartedAt: now,
+		EndsAt:    model.ExclusionEnd(model.ExclusionPeriodDay, now),
+	}))
+
+	tx = e.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
+
+	active, err := e.repo.GetActiveExclusion(tx, e.ctx, userID)
+	e.NoError(err)
+	e.Equal(week.ID, active.ID)
+
+	exclusions, err := e.repo.GetExclusions(e.ctx, userID)
+	e.NoError(err)
+	e.Len(exclusions, 3)
+	e.Equal(model.ExclusionPeriodDay, exclusions[2].Period)
+}
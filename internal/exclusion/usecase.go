@@ -0,0 +1,15 @@
+package exclusion
+
+import (
+	"context"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Usecase is a usecase interface for self-exclusions.
+//
+//go:generate mockgen -source ./usecase.go -mock_names Usecase=MockExclusionUsecase -package mocks -destination mocks/exclusionUsecase.mock.gen.go
+type Usecase interface {
+	StartExclusion(ctx context.Context, req *model.StartExclusion) (*model.Exclusion, error)
+	GetExclusions(ctx context.Context, userID string) ([]*model.Exclusion, error)
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ttagiyeva/entain/internal/exclusion"
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/user"
+)
+
+// Exclusion is a structure which manages self-exclusion usecase.
+type Exclusion struct {
+	log           *slog.Logger
+	exclusionRepo exclusion.Repository
+	userRepo      user.Repository
+}
+
+// New creates a new exclusion usecase.
+func New(log *slog.Logger, e exclusion.Repository, u user.Repository) *Exclusion {
+	return &Exclusion{
+		log:           log,
+		exclusionRepo: e,
+		userRepo:      u,
+	}
+}
+
+// StartExclusion excludes a user from playing from now on for the requested period.
+// An exclusion cannot be ended early, a shorter one started meanwhile does not shorten a running one.
+func (e *Exclusion) StartExclusion(ctx context.Context, req *model.StartExclusion) (*model.Exclusion, error) {
+	now := time.Now()
+
+	exclusion := &model.ExclusionDao{
+		UserID:    req.UserID,
+		Period:    req.Period,
+		StartedAt: now,
+		EndsAt:    model.ExclusionEnd(req.Period, now),
+	}
+
+	err := e.exclusionRepo.CreateExclusion(ctx, exclusion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exclusion: %w", err)
+	}
+
+	return model.ExclusionDaoToExclusion(exclusion), nil
+}
+
+// GetExclusions returns the audit trail of the exclusions of a user, the latest first.
+func (e *Exclusion) GetExclusions(ctx context.Context, userID string) ([]*model.Exclusion, error) {
+	_, err := e.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	exclusions, err := e.exclusionRepo.GetExclusions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exclusions: %w", err)
+	}
+
+	resp := make([]*model.Exclusion, 0, len(exclusions))
+	for _, exclusion := range exclusions {
+		resp = append(resp, model.ExclusionDaoToExclusion(exclusion))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ttagiyeva/entain/internal/exclusion/mocks"
+	"github.com/ttagiyeva/entain/internal/model"
+	userMocks "github.com/ttagiyeva/entain/internal/user/mocks"
+)
+
+func TestStartExclusion(t *testing.T) {
+	userID := gofakeit.UUID()
+
+	testCases := []struct {
+		name          string
+		req           *model.StartExclusion
+		buildStubs    func(exclusionRepo *mocks.MockExclusionRepository)
+		checkResponse func(exclusion *model.Exclusion, err error)
+	}{
+		{
+			name: "Time-out",
+			req:  &model.StartExclusion{UserID: userID, Period: model.ExclusionPeriodWeek},
+			buildStubs: func(exclusionRepo *mocks.MockExclusionRepository) {
+				exclusionRepo.EXPECT().CreateExclusion(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.ExclusionDao) error {
+					e.ID = "1"
+
+					return nil
+				})
+			},
+			checkResponse: func(exclusion *model.Exclusion, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1", exclusion.ID)
+				require.Equal(t, model.ExclusionPeriodWeek, exclusion.Period)
+				require.Equal(t, exclusion.StartedAt.AddDate(0, 0, 7), *exclusion.EndsAt)
+			},
+		},
+		{
+			name: "Permanent",
+			req:  &model.StartExclusion{UserID: userID, Period: model.ExclusionPeriodPermanent},
+			buildStubs: func(exclusionRepo *mocks.MockExclusionRepository) {
+				exclusionRepo.EXPECT().CreateExclusion(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			checkResponse: func(exclusion *model.Exclusion, err error) {
+				require.NoError(t, err)
+				require.WithinDuration(t, time.Now(), exclusion.StartedAt, time.Minute)
+				require.Nil(t, exclusion.EndsAt)
+			},
+		},
+		{
+			name: "User not found",
+			req:  &model.StartExclusion{UserID: userID, Period: model.ExclusionPeriodDay},
+			buildStubs: func(exclusionRepo *mocks.MockExclusionRepository) {
+				exclusionRepo.EXPECT().CreateExclusion(gomock.Any(), gomock.Any()).Return(model.ErrorUserNotFound)
+			},
+			checkResponse: func(exclusion *model.Exclusion, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserNotFound))
+				require.Nil(t, exclusion)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			exclusionRepo := mocks.NewMockExclusionRepository(ctrl)
+			tc.buildStubs(exclusionRepo)
+
+			usecase := New(nil, exclusionRepo, nil)
+			exclusion, err := usecase.StartExclusion(context.Background(), tc.req)
+
+			tc.checkResponse(exclusion, err)
+		})
+	}
+}
+
+func TestGetExclusions(t *testing.T) {
+	userID := gofakeit.UUID()
+	endsAt := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(exclusionRepo *mocks.MockExclusionRepository, userRepo *userMocks.MockUserRepository)
+		checkResponse func(exclusions []*model.Exclusion, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(exclusionRepo *mocks.MockExclusionRepository, userRepo *userMocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), userID).Return(&model.UserDao{ID: userID}, nil)
+				exclusionRepo.EXPECT().GetExclusions(gomock.Any(), userID).Return([]*model.ExclusionDao{
+					{ID: "2", UserID: userID, Period: model.ExclusionPeriodPermanent},
+					{ID: "1", UserID: userID, Period: model.ExclusionPeriodDay, EndsAt: &endsAt},
+				}, nil)
+			},
+			checkResponse: func(exclusions []*model.Exclusion, err error) {
+				require.NoError(t, err)
+				require.Len(t, exclusions, 2)
+				require.Equal(t, "2", exclusions[0].ID)
+				require.Equal(t, &endsAt, exclusions[1].EndsAt)
+			},
+		},
+		{
+			name: "User not found",
+			buildStubs: func(exclusionRepo *mocks.MockExclusionRepository, userRepo *userMocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), userID).Return(nil, model.ErrorUserNotFound)
+			},
+			checkResponse: func(exclusions []*model.Exclusion, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserNotFound))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			exclusionRepo := mocks.NewMockExclusionRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			tc.buildStubs(exclusionRepo, userRepo)
+
+			usecase := New(nil, exclusionRepo, userRepo)
+			exclusions, err := usecase.GetExclusions(context.Background(), userID)
+
+			tc.checkResponse(exclusions, err)
+		})
+	}
+}
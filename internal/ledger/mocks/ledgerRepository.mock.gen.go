@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/ttagiyeva/entain/internal/model"
+)
+
+// MockLedgerRepository is a mock of Repository interface.
+type MockLedgerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerRepositoryMockRecorder
+}
+
+// MockLedgerRepositoryMockRecorder is the mock recorder for MockLedgerRepository.
+type MockLedgerRepositoryMockRecorder struct {
+	mock *MockLedgerRepository
+}
+
+// NewMockLedgerRepository creates a new mock instance.
+func NewMockLedgerRepository(ctrl *gomock.Controller) *MockLedgerRepository {
+	mock := &MockLedgerRepository{ctrl: ctrl}
+	mock.recorder = &MockLedgerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerRepository) EXPECT() *MockLedgerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateEntry mocks base method.
+func (m *MockLedgerRepository) CreateEntry(tx *sql.Tx, ctx context.Context, entry *model.LedgerEntryDao) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", tx, ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockLedgerRepositoryMockRecorder) CreateEntry(tx, ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockLedgerRepository)(nil).CreateEntry), tx, ctx, entry)
+}
+
+// GetMismatches mocks base method.
+func (m *MockLedgerRepository) GetMismatches(ctx context.Context) ([]*model.LedgerMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMismatches", ctx)
+	ret0, _ := ret[0].([]*model.LedgerMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMismatches indicates an expected call of GetMismatches.
+func (mr *MockLedgerRepositoryMockRecorder) GetMismatches(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMismatches", reflect.TypeOf((*MockLedgerRepository)(nil).GetMismatches), ctx)
+}
+
+// GetUserEntries mocks base method.
+func (m *MockLedgerRepository) GetUserEntries(ctx context.Context, userID string) ([]*model.LedgerEntryDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserEntries", ctx, userID)
+	ret0, _ := ret[0].([]*model.LedgerEntryDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserEntries indicates an expected call of GetUserEntries.
+func (mr *MockLedgerRepositoryMockRecorder) GetUserEntries(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserEntries", reflect.TypeOf((*MockLedgerRepository)(nil).GetUserEntries), ctx, userID)
+}
@@ -0,0 +1,17 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+//go:generate mockgen -source ./repository.go -mock_names Repository=MockLedgerRepository -package mocks -destination mocks/ledgerRepository.mock.gen.go
+
+// Repository is a repository for ledger entries
+type Repository interface {
+	CreateEntry(tx *sql.Tx, ctx context.Context, entry *model.LedgerEntryDao) error
+	GetUserEntries(ctx context.Context, userID string) ([]*model.LedgerEntryDao, error)
+	GetMismatches(ctx context.Context) ([]*model.LedgerMismatch, error)
+}
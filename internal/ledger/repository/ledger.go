@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Ledger is the repository for ledger entries.
+type Ledger struct {
+	conn *sqlx.DB
+}
+
+// New returns a new Ledger object.
+func New(conn *sqlx.DB) *Ledger {
+	return &Ledger{
+		conn: conn,
+	}
+}
+
+// CreateEntry inserts a ledger entry in the given db tx, which must be the one of the balance change it records.
+func (l *Ledger) CreateEntry(tx *sql.Tx, ctx context.Context, entry *model.LedgerEntryDao) error {
+	query := `
+		INSERT INTO ledger_entries (user_id, transaction_id, reason, currency, bucket, amount, balance_before, balance_after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, seq, created_at;
+	`
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		entry.UserID,
+		entry.TransactionID,
+		entry.Reason,
+		entry.Currency,
+		entry.Bucket,
+		entry.Amount,
+		entry.BalanceBefore,
+		entry.BalanceAfter,
+	).Scan(&entry.ID, &entry.Seq, &entry.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to execute insert ledger entry query: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserEntries returns the ledger entries of a user in the order they were written.
+func (l *Ledger) GetUserEntries(ctx context.Context, userID string) ([]*model.LedgerEntryDao, error) {
+	query := `
+		SELECT
+			id,
+			seq,
+			user_id,
+			transaction_id,
+			reason,
+			currency,
+			bucket,
+			amount,
+			balance_before,
+			balance_after,
+			created_at
+		FROM ledger_entries
+		WHERE user_id = $1
+		ORDER BY seq;
+	`
+	rows, err := l.conn.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get user ledger entries query: %w", err)
+	}
+
+	defer rows.Close()
+
+	entries := []*model.LedgerEntryDao{}
+
+	for rows.Next() {
+		entry := &model.LedgerEntryDao{}
+		err = rows.Scan(
+			&entry.ID,
+			&entry.Seq,
+			&entry.UserID,
+			&entry.TransactionID,
+			&entry.Reason,
+			&entry.Currency,
+			&entry.Bucket,
+			&entry.Amount,
+			&entry.BalanceBefore,
+			&entry.BalanceAfter,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry row: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate ledger entry rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetMismatches is the consistency check of the ledger, it returns the wallets whose real or bonus balance
+// is not equal to the sum of their ledger entries in the bucket.
+func (l *Ledger) GetMismatches(ctx context.Context) ([]*model.LedgerMismatch, error) {
+	query := `
+		SELECT
+			w.user_id,
+			w.currency,
+			w.balance,
+			COALESCE(SUM(l.amount) FILTER (WHERE l.bucket = 'real'), 0) AS ledger_balance,
+			w.bonus_balance,
+			COALESCE(SUM(l.amount) FILTER (WHERE l.bucket = 'bonus'), 0) AS ledger_bonus_balance
+		FROM wallets w
+		LEFT JOIN ledger_entries l ON l.user_id = w.user_id AND l.currency = w.currency
+		GROUP BY w.user_id, w.currency, w.balance, w.bonus_balance
+		HAVING w.balance <> COALESCE(SUM(l.amount) FILTER (WHERE l.bucket = 'real'), 0)
+			OR w.bonus_balance <> COALESCE(SUM(l.amount) FILTER (WHERE l.bucket = 'bonus'), 0)
+		ORDER BY w.user_id, w.currency;
+	`
+	rows, err := l.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get ledger mismatches query: %w", err)
+	}
+
+	defer rows.Close()
+
+	mismatches := []*model.LedgerMismatch{}
+
+	for rows.Next() {
+		mismatch := &model.LedgerMismatch{}
+		err = rows.Scan(
+			&mismatch.UserID,
+			&mismatch.Currency,
+			&mismatch.Balance,
+			&mismatch.LedgerBalance,
+			&mismatch.BonusBalance,
+			&mismatch.LedgerBonusBalance,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ledger mismatch row: %w", err)
+		}
+
+		mismatches = append(mismatches, mismatch)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate ledger mismatch rows: %w", err)
+	}
+
+	return mismatches, nil
+}
@@ -0,0 +1,120 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/ttagiyeva/entain/internal/database"
+	"github.com/ttagiyeva/entain/internal/ledger/repository"
+	"github.com/ttagiyeva/entain/internal/model"
+	userRepo "github.com/ttagiyeva/entain/internal/user/repository"
+	"github.com/ttagiyeva/entain/internal/util"
+)
+
+type ledgerRepoTestSuite struct {
+	suite.Suite
+	testcontainers.Container
+	db   *database.Postgres
+	repo *repository.Ledger
+	ctx  context.Context
+}
+
+func TestLedgerRepoTestSuite(t *testing.T) {
+	suite.Run(t, &ledgerRepoTestSuite{})
+}
+
+func (l *ledgerRepoTestSuite) SetupSuite() {
+	l.ctx = context.Background()
+	l.db = util.CreateTestContainer(l.ctx, &l.Suite)
+	l.repo = repository.New(l.db.Connection)
+}
+
+func (l *ledgerRepoTestSuite) SetupTest() {
+	if err := l.db.MigrateUp(); err != nil || errors.Is(err, migrate.ErrNoChange) {
+		l.Require().NoError(err)
+	}
+}
+
+func (l *ledgerRepoTestSuite) TearDownTest() {
+	l.NoError(l.db.MigrateDown())
+}
+
+func (l *ledgerRepoTestSuite) TestCreateEntry() {
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	users := userRepo.New(l.db.Connection)
+	tx := l.db.Connection.MustBegin().Tx
+
+	wallet, err := users.GetWalletForUpdate(tx, l.ctx, userID, "EUR")
+	l.NoError(err)
+	l.NoError(users.UpdateWalletBalance(tx, l.ctx, wallet, &model.WalletMovement{Real: 1015}))
+
+	entry := &model.LedgerEntryDao{
+		UserID:        userID,
+		Reason:        model.LedgerReasonAdjustment,
+		Currency:      wallet.Currency,
+		Bucket:        model.LedgerBucketReal,
+		Amount:        1015,
+		BalanceBefore: 0,
+		BalanceAfter:  wallet.Balance,
+	}
+
+	l.NoError(l.repo.CreateEntry(tx, l.ctx, entry))
+	l.NotEmpty(entry.ID)
+	l.NoError(tx.Commit())
+
+	entries, err := l.repo.GetUserEntries(l.ctx, userID)
+	l.NoError(err)
+	l.Len(entries, 1)
+	l.Equal(entry.ID, entries[0].ID)
+	l.Nil(entries[0].TransactionID)
+	l.Equal("EUR", entries[0].Currency)
+	l.Equal(model.Money(1015), entries[0].BalanceAfter)
+
+	// An entry whose balances do not add up is refused by the database.
+	tx = l.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
+
+	l.Error(l.repo.CreateEntry(tx, l.ctx, &model.LedgerEntryDao{
+		UserID:        userID,
+		Reason:        model.LedgerReasonAdjustment,
+		Currency:      "EUR",
+		Bucket:        model.LedgerBucketReal,
+		Amount:        100,
+		BalanceBefore: 1015,
+		BalanceAfter:  1015,
+	}))
+}
+
+func (l *ledgerRepoTestSuite) TestGetMismatches() {
+	mismatches, err := l.repo.GetMismatches(l.ctx)
+	l.NoError(err)
+	l.Empty(mismatches)
+
+	// A balance changed without a ledger entry is reported.
+	_, err = l.db.Connection.ExecContext(l.ctx, `UPDATE wallets SET balance = 5 WHERE user_id = '00000000-0000-0000-0000-000000000001'`)
+	l.Require().NoError(err)
+
+	mismatches, err = l.repo.GetMismatches(l.ctx)
+	l.NoError(err)
+	l.Len(mismatches, 1)
+	l.Equal("00000000-0000-0000-0000-000000000001", mismatches[0].UserID)
+	l.Equal("EUR", mismatches[0].Currency)
+	l.Equal(model.Money(500), mismatches[0].Balance)
+	l.Equal(model.Money(0), mismatches[0].LedgerBalance)
+
+	// So is a bonus balance changed without a ledger entry.
+	_, err = l.db.Connection.ExecContext(l.ctx, `UPDATE wallets SET balance = 0, bonus_balance = 7 WHERE user_id = '00000000-0000-0000-0000-000000000001'`)
+	l.Require().NoError(err)
+
+	mismatches, err = l.repo.GetMismatches(l.ctx)
+	l.NoError(err)
+	l.Len(mismatches, 1)
+	l.Equal(model.Money(700), mismatches[0].BonusBalance)
+	l.Equal(model.Money(0), mismatches[0].LedgerBonusBalance)
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator"
+	"github.com/labstack/echo/v4"
+
+	"github.com/ttagiyeva/entain/internal/limit"
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Handler is a structure which manages limit http handlers.
+type Handler struct {
+	log     *slog.Logger
+	usecase limit.Usecase
+}
+
+// NewHandler creates a new limit http handler.
+func NewHandler(log *slog.Logger, u limit.Usecase) *Handler {
+	return &Handler{
+		log:     log,
+		usecase: u,
+	}
+}
+
+// GetLimits returns the responsible gambling limits of the user.
+func (h *Handler) GetLimits(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	limits, err := h.usecase.GetLimits(ctx.Request().Context(), id)
+	if err != nil {
+		h.log.With("id", id).Error("failed to get limits", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusOK, limits)
+}
+
+// SetLimit sets, raises or lowers a responsible gambling limit of the user.
+func (h *Handler) SetLimit(ctx echo.Context) error {
+	req := &model.SetLimit{}
+
+	err := ctx.Bind(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: model.ErrorBadRequest,
+		})
+	}
+
+	req.UserID = ctx.Param("id")
+
+	err = model.NewValidator().Struct(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, h.validatorError(err))
+	}
+
+	l, err := h.usecase.SetLimit(ctx.Request().Context(), req)
+	if err != nil {
+		h.log.With("body", req).Error("failed to set limit", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusOK, l)
+}
+
+func (h *Handler) validatorError(err error) model.Error {
+	if _, ok := err.(*validator.InvalidValidationError); ok {
+		h.log.Error("failed to assert validation error", "error", err)
+
+		return model.Error{Code: http.StatusInternalServerError, Message: "Internal Server Error"}
+	}
+
+	var sb strings.Builder
+
+	for i, err := range err.(validator.ValidationErrors) {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+
+		switch err.Tag() {
+		case "required":
+			sb.WriteString(fmt.Sprintf("%s field is required", err.Field()))
+		case "oneof":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must be one of '%s'", err.Field(), err.Param()))
+		case "gte":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must be at least %s", err.Field(), err.Param()))
+		case "iso4217":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must be an ISO 4217 currency code", err.Field()))
+		}
+	}
+
+	return model.Error{Code: http.StatusBadRequest, Message: sb.String()}
+}
+
+func getError(err error) model.Error {
+	switch {
+	case errors.Is(err, model.ErrorUserNotFound):
+		return model.Error{Code: http.StatusNotFound, Message: model.ErrorUserNotFound.Error()}
+	case errors.Is(err, model.ErrorWalletNotFound):
+		return model.Error{Code: http.StatusUnprocessableEntity, Message: model.ErrorWalletNotFound.Error()}
+	default:
+		return model.Error{Code: http.StatusInternalServerError, Message: model.ErrorInternalServerError.Error()}
+	}
+}
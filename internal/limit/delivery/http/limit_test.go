@@ -0,0 +1,194 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ttagiyeva/entain/internal/limit/mocks"
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// TestLimitHandler_GetLimits tests the limit handler get limits method.
+func TestLimitHandler_GetLimits(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	pendingAmount := "100.00"
+	pendingFrom := updatedAt.Add(24 * time.Hour)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(limitUsecase *mocks.MockLimitUsecase)
+		expectedCode  int
+		expectedBody  string
+		expectedError model.Error
+	}{
+		{
+			name: "OK",
+			buildStubs: func(limitUsecase *mocks.MockLimitUsecase) {
+				limitUsecase.EXPECT().GetLimits(gomock.Any(), "1").Return([]*model.Limit{
+					{Currency: "EUR", Kind: "loss", Period: "day", Amount: "50.00", PendingAmount: &pendingAmount, PendingFrom: &pendingFrom, UpdatedAt: updatedAt},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"currency":"EUR","kind":"loss","period":"day","amount":"50.00",` +
+				`"pendingAmount":"100.00","pendingFrom":"2024-05-02T10:00:00Z","updatedAt":"2024-05-01T10:00:00Z"}]`,
+		},
+		{
+			name: "User not found",
+			buildStubs: func(limitUsecase *mocks.MockLimitUsecase) {
+				limitUsecase.EXPECT().GetLimits(gomock.Any(), "1").Return(nil, model.ErrorUserNotFound)
+			},
+			expectedError: getError(model.ErrorUserNotFound),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			limitUsecase := mocks.NewMockLimitUsecase(ctrl)
+			tc.buildStubs(limitUsecase)
+
+			handler := NewHandler(slog.Default(), limitUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/users/1/limits", nil)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			err := handler.GetLimits(c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+				require.JSONEq(t, tc.expectedBody, rec.Body.String())
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+// TestLimitHandler_SetLimit tests the limit handler set limit method.
+func TestLimitHandler_SetLimit(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(limitUsecase *mocks.MockLimitUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name: "OK",
+			body: []byte(`{"currency":"EUR","kind":"loss","period":"week","amount":100}`),
+			buildStubs: func(limitUsecase *mocks.MockLimitUsecase) {
+				limitUsecase.EXPECT().SetLimit(gomock.Any(), &model.SetLimit{
+					UserID:   "1",
+					Currency: "EUR",
+					Kind:     "loss",
+					Period:   "week",
+					Amount:   10000,
+				}).Return(&model.Limit{Currency: "EUR", Kind: "loss", Period: "week", Amount: "100.00"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid request body",
+			body:       []byte(`{"currency":"EUR","kind":"loss","period":"week","amount":"100"}`),
+			buildStubs: func(limitUsecase *mocks.MockLimitUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: model.ErrorBadRequest,
+			},
+		},
+		{
+			name:       "Invalid kind",
+			body:       []byte(`{"currency":"EUR","kind":"stake","period":"week","amount":100}`),
+			buildStubs: func(limitUsecase *mocks.MockLimitUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the Kind field must be one of 'loss deposit'",
+			},
+		},
+		{
+			name:       "Negative amount",
+			body:       []byte(`{"currency":"EUR","kind":"loss","period":"day","amount":-1}`),
+			buildStubs: func(limitUsecase *mocks.MockLimitUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the Amount field must be at least 0",
+			},
+		},
+		{
+			name: "Wallet not found",
+			body: []byte(`{"currency":"USD","kind":"loss","period":"day","amount":100}`),
+			buildStubs: func(limitUsecase *mocks.MockLimitUsecase) {
+				limitUsecase.EXPECT().SetLimit(gomock.Any(), gomock.Any()).Return(nil, model.ErrorWalletNotFound)
+			},
+			expectedError: getError(model.ErrorWalletNotFound),
+		},
+		{
+			name: "Internal server error",
+			body: []byte(`{"currency":"EUR","kind":"deposit","period":"month","amount":100}`),
+			buildStubs: func(limitUsecase *mocks.MockLimitUsecase) {
+				limitUsecase.EXPECT().SetLimit(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("unexpected error"))
+			},
+			expectedError: getError(fmt.Errorf("unexpected error")),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			limitUsecase := mocks.NewMockLimitUsecase(ctrl)
+			tc.buildStubs(limitUsecase)
+
+			handler := NewHandler(slog.Default(), limitUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/users/1/limits", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			err := handler.SetLimit(c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
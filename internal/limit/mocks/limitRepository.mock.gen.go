@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/ttagiyeva/entain/internal/model"
+)
+
+// MockLimitRepository is a mock of Repository interface.
+type MockLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimitRepositoryMockRecorder
+}
+
+// MockLimitRepositoryMockRecorder is the mock recorder for MockLimitRepository.
+type MockLimitRepositoryMockRecorder struct {
+	mock *MockLimitRepository
+}
+
+// NewMockLimitRepository creates a new mock instance.
+func NewMockLimitRepository(ctrl *gomock.Controller) *MockLimitRepository {
+	mock := &MockLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimitRepository) EXPECT() *MockLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetLimitForUpdate mocks base method.
+func (m *MockLimitRepository) GetLimitForUpdate(tx *sql.Tx, ctx context.Context, userID, currency, kind, period string) (*model.LimitDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimitForUpdate", tx, ctx, userID, currency, kind, period)
+	ret0, _ := ret[0].(*model.LimitDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimitForUpdate indicates an expected call of GetLimitForUpdate.
+func (mr *MockLimitRepositoryMockRecorder) GetLimitForUpdate(tx, ctx, userID, currency, kind, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitForUpdate", reflect.TypeOf((*MockLimitRepository)(nil).GetLimitForUpdate), tx, ctx, userID, currency, kind, period)
+}
+
+// GetLimits mocks base method.
+func (m *MockLimitRepository) GetLimits(ctx context.Context, userID string) ([]*model.LimitDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimits", ctx, userID)
+	ret0, _ := ret[0].([]*model.LimitDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimits indicates an expected call of GetLimits.
+func (mr *MockLimitRepositoryMockRecorder) GetLimits(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimits", reflect.TypeOf((*MockLimitRepository)(nil).GetLimits), ctx, userID)
+}
+
+// GetLimitsByKind mocks base method.
+func (m *MockLimitRepository) GetLimitsByKind(tx *sql.Tx, ctx context.Context, userID, currency, kind string) ([]*model.LimitDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimitsByKind", tx, ctx, userID, currency, kind)
+	ret0, _ := ret[0].([]*model.LimitDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimitsByKind indicates an expected call of GetLimitsByKind.
+func (mr *MockLimitRepositoryMockRecorder) GetLimitsByKind(tx, ctx, userID, currency, kind interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitsByKind", reflect.TypeOf((*MockLimitRepository)(nil).GetLimitsByKind), tx, ctx, userID, currency, kind)
+}
+
+// GetUsage mocks base method.
+func (m *MockLimitRepository) GetUsage(tx *sql.Tx, ctx context.Context, userID, currency, kind string, since time.Time) (model.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage", tx, ctx, userID, currency, kind, since)
+	ret0, _ := ret[0].(model.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockLimitRepositoryMockRecorder) GetUsage(tx, ctx, userID, currency, kind, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockLimitRepository)(nil).GetUsage), tx, ctx, userID, currency, kind, since)
+}
+
+// SaveLimit mocks base method.
+func (m *MockLimitRepository) SaveLimit(tx *sql.Tx, ctx context.Context, limit *model.LimitDao) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLimit", tx, ctx, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLimit indicates an expected call of SaveLimit.
+func (mr *MockLimitRepositoryMockRecorder) SaveLimit(tx, ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLimit", reflect.TypeOf((*MockLimitRepository)(nil).SaveLimit), tx, ctx, limit)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/ttagiyeva/entain/internal/model"
+)
+
+// MockLimitUsecase is a mock of Usecase interface.
+type MockLimitUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimitUsecaseMockRecorder
+}
+
+// MockLimitUsecaseMockRecorder is the mock recorder for MockLimitUsecase.
+type MockLimitUsecaseMockRecorder struct {
+	mock *MockLimitUsecase
+}
+
+// NewMockLimitUsecase creates a new mock instance.
+func NewMockLimitUsecase(ctrl *gomock.Controller) *MockLimitUsecase {
+	mock := &MockLimitUsecase{ctrl: ctrl}
+	mock.recorder = &MockLimitUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimitUsecase) EXPECT() *MockLimitUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetLimits mocks base method.
+func (m *MockLimitUsecase) GetLimits(ctx context.Context, userID string) ([]*model.Limit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimits", ctx, userID)
+	ret0, _ := ret[0].([]*model.Limit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimits indicates an expected call of GetLimits.
+func (mr *MockLimitUsecaseMockRecorder) GetLimits(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimits", reflect.TypeOf((*MockLimitUsecase)(nil).GetLimits), ctx, userID)
+}
+
+// SetLimit mocks base method.
+func (m *MockLimitUsecase) SetLimit(ctx context.Context, limit *model.SetLimit) (*model.Limit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimit", ctx, limit)
+	ret0, _ := ret[0].(*model.Limit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLimit indicates an expected call of SetLimit.
+func (mr *MockLimitUsecaseMockRecorder) SetLimit(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockLimitUsecase)(nil).SetLimit), ctx, limit)
+}
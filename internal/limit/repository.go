@@ -0,0 +1,20 @@
+package limit
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+//go:generate mockgen -source ./repository.go -mock_names Repository=MockLimitRepository -package mocks -destination mocks/limitRepository.mock.gen.go
+
+// Repository is a repository for responsible gambling limits.
+type Repository interface {
+	GetLimits(ctx context.Context, userID string) ([]*model.LimitDao, error)
+	GetLimitsByKind(tx *sql.Tx, ctx context.Context, userID, currency, kind string) ([]*model.LimitDao, error)
+	GetLimitForUpdate(tx *sql.Tx, ctx context.Context, userID, currency, kind, period string) (*model.LimitDao, error)
+	SaveLimit(tx *sql.Tx, ctx context.Context, limit *model.LimitDao) error
+	GetUsage(tx *sql.Tx, ctx context.Context, userID, currency, kind string, since time.Time) (model.Money, error)
+}
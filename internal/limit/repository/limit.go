@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Limit is the repository for responsible gambling limits.
+type Limit struct {
+	conn *sqlx.DB
+}
+
+// New returns a new Limit object.
+func New(conn *sqlx.DB) *Limit {
+	return &Limit{
+		conn: conn,
+	}
+}
+
+// GetLimits returns the limits of a user ordered by currency, kind and period.
+func (l *Limit) GetLimits(ctx context.Context, userID string) ([]*model.LimitDao, error) {
+	query := `
+		SELECT
+			user_id,
+			currency,
+			kind,
+			period,
+			amount,
+			pending_amount,
+			pending_from,
+			created_at,
+			updated_at
+		FROM limits
+		WHERE user_id = $1
+		ORDER BY currency, kind, period;
+	`
+	rows, err := l.conn.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get limits query: %w", err)
+	}
+
+	return scanLimits(rows)
+}
+
+// GetLimitsByKind returns the limits of a kind a user has in a currency.
+func (l *Limit) GetLimitsByKind(tx *sql.Tx, ctx context.Context, userID, currency, kind string) ([]*model.LimitDao, error) {
+	query := `
+		SELECT
+			user_id,
+			currency,
+			kind,
+			period,
+			amount,
+			pending_amount,
+			pending_from,
+			created_at,
+			updated_at
+		FROM limits
+		WHERE user_id = $1 AND currency = $2 AND kind = $3;
+	`
+	rows, err := tx.QueryContext(ctx, query, userID, currency, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get limits by kind query: %w", err)
+	}
+
+	return scanLimits(rows)
+}
+
+// GetLimitForUpdate returns a limit of a user and locks the row until the given db tx ends.
+func (l *Limit) GetLimitForUpdate(tx *sql.Tx, ctx context.Context, userID, currency, kind, period string) (*model.LimitDao, error) {
+	query := `
+		SELECT
+			user_id,
+			currency,
+			kind,
+			period,
+			amount,
+			pending_amount,
+			pending_from,
+			created_at,
+			updated_at
+		FROM limits
+		WHERE user_id = $1 AND currency = $2 AND kind = $3 AND period = $4 FOR UPDATE;
+	`
+	limit := &model.LimitDao{}
+
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		userID,
+		currency,
+		kind,
+		period,
+	).Scan(
+		&limit.UserID,
+		&limit.Currency,
+		&limit.Kind,
+		&limit.Period,
+		&limit.Amount,
+		&limit.PendingAmount,
+		&limit.PendingFrom,
+		&limit.CreatedAt,
+		&limit.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed because the user has no %s %s limit: %w", period, kind, model.ErrorLimitNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to execute get limit for update query: %w", err)
+	}
+
+	return limit, nil
+}
+
+// SaveLimit inserts the limit or overwrites the amounts of the existing one.
+func (l *Limit) SaveLimit(tx *sql.Tx, ctx context.Context, limit *model.LimitDao) error {
+	query := `
+		INSERT INTO limits (user_id, currency, kind, period, amount, pending_amount, pending_from)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, currency, kind, period) DO UPDATE
+		SET amount = EXCLUDED.amount,
+			pending_amount = EXCLUDED.pending_amount,
+			pending_from = EXCLUDED.pending_from,
+			updated_at = NOW()
+		RETURNING created_at, updated_at;
+	`
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		limit.UserID,
+		limit.Currency,
+		limit.Kind,
+		limit.Period,
+		limit.Amount,
+		limit.PendingAmount,
+		limit.PendingFrom,
+	).Scan(&limit.CreatedAt, &limit.UpdatedAt)
+
+	if err != nil {
+		var pqError *pq.Error
+		if errors.As(err, &pqError) && pqError.Constraint == "limits_wallet_fkey" {
+			return fmt.Errorf("failed because the user has no %s wallet: %w", limit.Currency, model.ErrorWalletNotFound)
+		}
+
+		return fmt.Errorf("failed to execute save limit query: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsage returns what a user has used of the limits of a kind in a currency since the given time.
+// The usage of a loss limit is the net debit of the uncancelled gambling transactions, see model.GamingSourceTypes, plus the stakes of the open bets,
+// which could all be lost, whenever they were placed. A settled bet counts by its settlement transaction instead.
+// The usage of a deposit limit is the sum of the uncancelled credited and pending deposits.
+func (l *Limit) GetUsage(tx *sql.Tx, ctx context.Context, userID, currency, kind string, since time.Time) (model.Money, error) {
+	query := `
+		SELECT COALESCE(SUM(CASE s.direction WHEN 'debit' THEN t.amount WHEN 'credit' THEN -t.amount ELSE 0 END), 0)
+			+ (
+				SELECT COALESCE(SUM(b.stake), 0)
+				FROM bets b
+				WHERE b.user_id = $1 AND b.currency = $2 AND b.state = 'placed'
+			)
+		FROM transactions t
+		JOIN states s ON s.name = t.state
+		WHERE t.user_id = $1 AND t.currency = $2 AND t.created_at >= $3 AND NOT t.cancelled AND t.source_type = ANY($4);
+	`
+	args := []any{userID, currency, since, pq.Array(model.GamingSourceTypes)}
+
+	if kind == model.LimitKindDeposit {
+		query = `
+			SELECT COALESCE(SUM(t.amount), 0)
+			FROM transactions t
+			JOIN states s ON s.name = t.state
+			WHERE t.user_id = $1 AND t.currency = $2 AND t.created_at >= $3 AND NOT t.cancelled
+				AND t.source_type = 'payment' AND (s.direction = 'credit' OR t.state = 'deposit_pending');
+		`
+		args = args[:3]
+	}
+
+	var usage model.Money
+
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&usage)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute get %s usage query: %w", kind, err)
+	}
+
+	return usage, nil
+}
+
+func scanLimits(rows *sql.Rows) ([]*model.LimitDao, error) {
+	defer rows.Close()
+
+	limits := []*model.LimitDao{}
+
+	for rows.Next() {
+		limit := &model.LimitDao{}
+		err := rows.Scan(
+			&limit.UserID,
+			&limit.Currency,
+			&limit.Kind,
+			&limit.Period,
+			&limit.Amount,
+			&limit.PendingAmount,
+			&limit.PendingFrom,
+			&limit.CreatedAt,
+			&limit.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan limit row: %w", err)
+		}
+
+		limits = append(limits, limit)
+	}
+
+	err := rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate limit rows: %w", err)
+	}
+
+	return limits, nil
+}
@@ -0,0 +1,126 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/ttagiyeva/entain/internal/database"
+	"github.com/ttagiyeva/entain/internal/limit/repository"
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/util"
+)
+
+const userID = "00000000-0000-0000-0000-000000000001"
+
+type limitRepoTestSuite struct {
+	suite.Suite
+	testcontainers.Container
+	db   *database.Postgres
+	repo *repository.Limit
+	ctx  context.Context
+}
+
+func TestLimitRepoTestSuite(t *testing.T) {
+	suite.Run(t, &limitRepoTestSuite{})
+}
+
+func (l *limitRepoTestSuite) SetupSuite() {
+	l.ctx = context.Background()
+	l.db = util.CreateTestContainer(l.ctx, &l.Suite)
+	l.repo = repository.New(l.db.Connection)
+}
+
+func (l *limitRepoTestSuite) SetupTest() {
+	if err := l.db.MigrateUp(); err != nil || errors.Is(err, migrate.ErrNoChange) {
+		l.Require().NoError(err)
+	}
+}
+
+func (l *limitRepoTestSuite) TearDownTest() {
+	l.NoError(l.db.MigrateDown())
+}
+
+func (l *limitRepoTestSuite) TestSaveLimit() {
+	tx := l.db.Connection.MustBegin().Tx
+
+	_, err := l.repo.GetLimitForUpdate(tx, l.ctx, userID, "EUR", model.LimitKindLoss, model.LimitPeriodDay)
+	l.Equal(true, errors.Is(err, model.ErrorLimitNotFound))
+
+	limit := &model.LimitDao{
+		UserID:   userID,
+		Currency: "EUR",
+		Kind:     model.LimitKindLoss,
+		Period:   model.LimitPeriodDay,
+		Amount:   1000,
+	}
+	l.NoError(l.repo.SaveLimit(tx, l.ctx, limit))
+
+	limit.Change(2000, time.Now(), time.Hour)
+	l.NoError(l.repo.SaveLimit(tx, l.ctx, limit))
+	l.NoError(tx.Commit())
+
+	limits, err := l.repo.GetLimits(l.ctx, userID)
+	l.NoError(err)
+	l.Len(limits, 1)
+	l.Equal(model.Money(1000), limits[0].Amount)
+	l.Equal(model.Money(2000), *limits[0].PendingAmount)
+	l.NotNil(limits[0].PendingFrom)
+
+	tx = l.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
+
+	limits, err = l.repo.GetLimitsByKind(tx, l.ctx, userID, "EUR", model.LimitKindDeposit)
+	l.NoError(err)
+	l.Empty(limits)
+
+	err = l.repo.SaveLimit(tx, l.ctx, &model.LimitDao{
+		UserID:   userID,
+		Currency: "USD",
+		Kind:     model.LimitKindLoss,
+		Period:   model.LimitPeriodDay,
+		Amount:   1000,
+	})
+	l.Equal(true, errors.Is(err, model.ErrorWalletNotFound))
+}
+
+func (l *limitRepoTestSuite) TestGetUsage() {
+	_, err := l.db.Connection.ExecContext(l.ctx, `
+		INSERT INTO transactions (user_id, transaction_id, source_type, state, amount, currency, cancelled, created_at)
+		VALUES
+			($1, '1', 'game', 'lost', 30, 'EUR', FALSE, NOW()),
+			($1, '2', 'game', 'win', 10, 'EUR', FALSE, NOW()),
+			($1, '3', 'server', 'lost', 5, 'EUR', TRUE, NOW()),
+			($1, '4', 'game', 'lost', 50, 'EUR', FALSE, NOW() - INTERVAL '2 days'),
+			($1, '5', 'payment', 'win', 100, 'EUR', FALSE, NOW()),
+			($1, '6', 'payment', 'lost', 40, 'EUR', FALSE, NOW()),
+			($1, '7', 'adjustment', 'adjustment_credit', 20, 'EUR', FALSE, NOW()),
+			($1, '8', 'adjustment', 'adjustment_debit', 15, 'EUR', FALSE, NOW()),
+			($1, '9', 'transfer', 'transfer_out', 25, 'EUR', FALSE, NOW()),
+			($1, '10', 'transfer', 'transfer_in', 5, 'EUR', FALSE, NOW());
+	`, userID)
+	l.Require().NoError(err)
+
+	tx := l.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
+
+	since := time.Now().Add(-24 * time.Hour)
+
+	// Adjustments and transfers are not gambling, they leave the loss usage as is.
+	loss, err := l.repo.GetUsage(tx, l.ctx, userID, "EUR", model.LimitKindLoss, since)
+	l.NoError(err)
+	l.Equal(model.Money(2000), loss)
+
+	deposit, err := l.repo.GetUsage(tx, l.ctx, userID, "EUR", model.LimitKindDeposit, since)
+	l.NoError(err)
+	l.Equal(model.Money(10000), deposit)
+
+	loss, err = l.repo.GetUsage(tx, l.ctx, userID, "USD", model.LimitKindLoss, since)
+	l.NoError(err)
+	l.Equal(model.Money(0), loss)
+}
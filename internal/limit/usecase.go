@@ -0,0 +1,15 @@
+package limit
+
+import (
+	"context"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Usecase is a usecase interface for responsible gambling limits.
+//
+//go:generate mockgen -source ./usecase.go -mock_names Usecase=MockLimitUsecase -package mocks -destination mocks/limitUsecase.mock.gen.go
+type Usecase interface {
+	GetLimits(ctx context.Context, userID string) ([]*model.Limit, error)
+	SetLimit(ctx context.Context, limit *model.SetLimit) (*model.Limit, error)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ttagiyeva/entain/internal/config"
+	"github.com/ttagiyeva/entain/internal/limit"
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/transaction"
+	"github.com/ttagiyeva/entain/internal/user"
+)
+
+// Limit is a structure which manages responsible gambling limits usecase.
+type Limit struct {
+	log       *slog.Logger
+	conf      config.Limits
+	limitRepo limit.Repository
+	userRepo  user.Repository
+	db        transaction.Database
+}
+
+// New creates a new limit usecase.
+func New(log *slog.Logger, conf *config.Config, l limit.Repository, u user.Repository, d transaction.Database) *Limit {
+	return &Limit{
+		log:       log,
+		conf:      conf.Limits,
+		limitRepo: l,
+		userRepo:  u,
+		db:        d,
+	}
+}
+
+// GetLimits returns the limits of a user, pending changes which are due are shown as applied.
+func (l *Limit) GetLimits(ctx context.Context, userID string) ([]*model.Limit, error) {
+	_, err := l.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	limits, err := l.limitRepo.GetLimits(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get limits: %w", err)
+	}
+
+	now := time.Now()
+	resp := make([]*model.Limit, 0, len(limits))
+
+	for _, limit := range limits {
+		limit.Settle(now)
+		resp = append(resp, model.LimitDaoToLimit(limit))
+	}
+
+	return resp, nil
+}
+
+// SetLimit sets a new limit or changes an existing one. Lowering a limit applies at once,
+// raising it applies only once the configured cool-off is over.
+func (l *Limit) SetLimit(ctx context.Context, req *model.SetLimit) (*model.Limit, error) {
+	tx, err := l.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	limit, err := l.limitRepo.GetLimitForUpdate(tx, ctx, req.UserID, req.Currency, req.Kind, req.Period)
+
+	switch {
+	case errors.Is(err, model.ErrorLimitNotFound):
+		// A first limit only restricts the user, it applies at once.
+		limit = &model.LimitDao{
+			UserID:   req.UserID,
+			Currency: req.Currency,
+			Kind:     req.Kind,
+			Period:   req.Period,
+			Amount:   req.Amount,
+		}
+	case err != nil:
+		return nil, l.rollback(tx, fmt.Errorf("failed to get limit: %w", err))
+	default:
+		limit.Change(req.Amount, time.Now(), l.conf.CoolOff)
+	}
+
+	err = l.limitRepo.SaveLimit(tx, ctx, limit)
+	if err != nil {
+		return nil, l.rollback(tx, fmt.Errorf("failed to save limit: %w", err))
+	}
+
+	err = l.db.Commit(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return model.LimitDaoToLimit(limit), nil
+}
+
+func (l *Limit) rollback(tx *sql.Tx, err error) error {
+	errTx := l.db.Rollback(tx)
+	if errTx != nil {
+		return fmt.Errorf("failed to rollback the db tx: %w %w", errTx, err)
+	}
+
+	return err
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ttagiyeva/entain/internal/config"
+	"github.com/ttagiyeva/entain/internal/limit/mocks"
+	"github.com/ttagiyeva/entain/internal/model"
+	trMocks "github.com/ttagiyeva/entain/internal/transaction/mocks"
+	userMocks "github.com/ttagiyeva/entain/internal/user/mocks"
+)
+
+func TestGetLimits(t *testing.T) {
+	userID := gofakeit.UUID()
+	past := time.Now().Add(-time.Hour)
+	pending := model.Money(5000)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(limitRepo *mocks.MockLimitRepository, userRepo *userMocks.MockUserRepository)
+		checkResponse func(limits []*model.Limit, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(limitRepo *mocks.MockLimitRepository, userRepo *userMocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), userID).Return(&model.UserDao{ID: userID}, nil)
+				limitRepo.EXPECT().GetLimits(gomock.Any(), userID).Return([]*model.LimitDao{
+					{UserID: userID, Currency: "EUR", Kind: model.LimitKindLoss, Period: model.LimitPeriodDay, Amount: 1000, PendingAmount: &pending, PendingFrom: &past},
+				}, nil)
+			},
+			checkResponse: func(limits []*model.Limit, err error) {
+				require.NoError(t, err)
+				require.Len(t, limits, 1)
+				require.Equal(t, "50.00", limits[0].Amount)
+				require.Nil(t, limits[0].PendingAmount)
+			},
+		},
+		{
+			name: "User not found",
+			buildStubs: func(limitRepo *mocks.MockLimitRepository, userRepo *userMocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), userID).Return(nil, model.ErrorUserNotFound)
+			},
+			checkResponse: func(limits []*model.Limit, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserNotFound))
+				require.Nil(t, limits)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			limitRepo := mocks.NewMockLimitRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+
+			tc.buildStubs(limitRepo, userRepo)
+
+			usecase := New(nil, &config.Config{}, limitRepo, userRepo, nil)
+			limits, err := usecase.GetLimits(context.Background(), userID)
+
+			tc.checkResponse(limits, err)
+		})
+	}
+}
+
+func TestSetLimit(t *testing.T) {
+	tx := &sql.Tx{}
+	dummyErr := errors.New("dummy error")
+
+	req := &model.SetLimit{
+		UserID:   gofakeit.UUID(),
+		Currency: "EUR",
+		Kind:     model.LimitKindLoss,
+		Period:   model.LimitPeriodWeek,
+		Amount:   2000,
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(limitRepo *mocks.MockLimitRepository, db *trMocks.MockDatabase)
+		checkResponse func(limit *model.Limit, err error)
+	}{
+		{
+			name: "New limit",
+			buildStubs: func(limitRepo *mocks.MockLimitRepository, db *trMocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				limitRepo.EXPECT().GetLimitForUpdate(tx, gomock.Any(), req.UserID, req.Currency, req.Kind, req.Period).Return(nil, model.ErrorLimitNotFound)
+				limitRepo.EXPECT().SaveLimit(tx, gomock.Any(), gomock.Any()).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(limit *model.Limit, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "20.00", limit.Amount)
+				require.Nil(t, limit.PendingAmount)
+			},
+		},
+		{
+			name: "Raised limit",
+			buildStubs: func(limitRepo *mocks.MockLimitRepository, db *trMocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				limitRepo.EXPECT().GetLimitForUpdate(tx, gomock.Any(), req.UserID, req.Currency, req.Kind, req.Period).Return(&model.LimitDao{
+					UserID:   req.UserID,
+					Currency: req.Currency,
+					Kind:     req.Kind,
+					Period:   req.Period,
+					Amount:   1000,
+				}, nil)
+				limitRepo.EXPECT().SaveLimit(tx, gomock.Any(), gomock.Any()).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(limit *model.Limit, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "10.00", limit.Amount)
+				require.Equal(t, "20.00", *limit.PendingAmount)
+				require.WithinDuration(t, time.Now().Add(24*time.Hour), *limit.PendingFrom, time.Minute)
+			},
+		},
+		{
+			name: "Lowered limit",
+			buildStubs: func(limitRepo *mocks.MockLimitRepository, db *trMocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				limitRepo.EXPECT().GetLimitForUpdate(tx, gomock.Any(), req.UserID, req.Currency, req.Kind, req.Period).Return(&model.LimitDao{
+					UserID:   req.UserID,
+					Currency: req.Currency,
+					Kind:     req.Kind,
+					Period:   req.Period,
+					Amount:   5000,
+				}, nil)
+				limitRepo.EXPECT().SaveLimit(tx, gomock.Any(), gomock.Any()).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(limit *model.Limit, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "20.00", limit.Amount)
+				require.Nil(t, limit.PendingAmount)
+			},
+		},
+		{
+			name: "Wallet not found",
+			buildStubs: func(limitRepo *mocks.MockLimitRepository, db *trMocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				limitRepo.EXPECT().GetLimitForUpdate(tx, gomock.Any(), req.UserID, req.Currency, req.Kind, req.Period).Return(nil, model.ErrorLimitNotFound)
+				limitRepo.EXPECT().SaveLimit(tx, gomock.Any(), gomock.Any()).Return(model.ErrorWalletNotFound)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(limit *model.Limit, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorWalletNotFound))
+				require.Nil(t, limit)
+			},
+		},
+		{
+			name: "GetLimitForUpdate error",
+			buildStubs: func(limitRepo *mocks.MockLimitRepository, db *trMocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				limitRepo.EXPECT().GetLimitForUpdate(tx, gomock.Any(), req.UserID, req.Currency, req.Kind, req.Period).Return(nil, dummyErr)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(limit *model.Limit, err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+		{
+			name: "Commit error",
+			buildStubs: func(limitRepo *mocks.MockLimitRepository, db *trMocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				limitRepo.EXPECT().GetLimitForUpdate(tx, gomock.Any(), req.UserID, req.Currency, req.Kind, req.Period).Return(nil, model.ErrorLimitNotFound)
+				limitRepo.EXPECT().SaveLimit(tx, gomock.Any(), gomock.Any()).Return(nil)
+				db.EXPECT().Commit(tx).Return(dummyErr)
+			},
+			checkResponse: func(limit *model.Limit, err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			limitRepo := mocks.NewMockLimitRepository(ctrl)
+			db := trMocks.NewMockDatabase(ctrl)
+
+			tc.buildStubs(limitRepo, db)
+
+			usecase := New(nil, &config.Config{Limits: config.Limits{CoolOff: 24 * time.Hour}}, limitRepo, nil, db)
+			limit, err := usecase.SetLimit(context.Background(), req)
+
+			tc.checkResponse(limit, err)
+		})
+	}
+}
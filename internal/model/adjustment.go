@@ -0,0 +1,40 @@
+package model
+
+const (
+	// StateAdjustmentCredit and StateAdjustmentDebit are the states of the manual adjustments of support staff.
+	StateAdjustmentCredit = "adjustment_credit"
+	StateAdjustmentDebit  = "adjustment_debit"
+)
+
+// Adjustment is the request of support staff to credit or debit the wallet of a user by hand.
+type Adjustment struct {
+	TransactionID string `json:"transactionId" validate:"required,transaction_id"`
+	Direction     string `json:"direction" validate:"required,oneof=credit debit"`
+	Amount        Money  `json:"amount" validate:"required,gt=0"`
+	Currency      string `json:"currency" validate:"required,iso4217"`
+	Reason        string `json:"reason" validate:"required,max=500"`
+	OperatorID    string `json:"operatorId" validate:"required,max=64"`
+	UserID        string `validate:"required"`
+}
+
+// Transaction returns the transaction of the adjustment.
+func (a *Adjustment) Transaction() *Transaction {
+	state := StateAdjustmentCredit
+	if a.Direction == DirectionDebit {
+		state = StateAdjustmentDebit
+	}
+
+	return &Transaction{
+		TransactionID: a.TransactionID,
+		State:         state,
+		Amount:        a.Amount,
+		Currency:      a.Currency,
+		UserID:        a.UserID,
+		SourceType:    SourceTypeAdjustment,
+	}
+}
+
+// IsAdjustment reports whether the transaction is a manual adjustment, which only the adjustment flow makes.
+func (t *Transaction) IsAdjustment() bool {
+	return t.SourceType == SourceTypeAdjustment || t.State == StateAdjustmentCredit || t.State == StateAdjustmentDebit
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdjustmentTransaction(t *testing.T) {
+	a := &Adjustment{TransactionID: "1", Direction: DirectionDebit, Amount: 100, Currency: "EUR", Reason: "duplicate win", OperatorID: "agent-7", UserID: "2"}
+
+	require.Equal(t, &Transaction{
+		TransactionID: "1",
+		State:         StateAdjustmentDebit,
+		Amount:        100,
+		Currency:      "EUR",
+		UserID:        "2",
+		SourceType:    SourceTypeAdjustment,
+	}, a.Transaction())
+
+	a.Direction = DirectionCredit
+	require.Equal(t, StateAdjustmentCredit, a.Transaction().State)
+	require.Equal(t, true, a.Transaction().IsAdjustment())
+}
+
+func TestIsAdjustment(t *testing.T) {
+	require.Equal(t, false, (&Transaction{SourceType: SourceTypeGame, State: StateWin}).IsAdjustment())
+	require.Equal(t, true, (&Transaction{SourceType: SourceTypeAdjustment, State: StateWin}).IsAdjustment())
+	require.Equal(t, true, (&Transaction{SourceType: SourceTypeGame, State: StateAdjustmentCredit}).IsAdjustment())
+}
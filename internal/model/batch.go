@@ -0,0 +1,63 @@
+package model
+
+const (
+	// BatchStatusProcessed is the outcome of a transaction which has been applied.
+	BatchStatusProcessed = "processed"
+	// BatchStatusDuplicate is the outcome of a retried transaction, which has been processed before.
+	BatchStatusDuplicate = "duplicate"
+	// BatchStatusFailed is the outcome of a refused transaction, the error of the result tells why.
+	BatchStatusFailed = "failed"
+	// BatchStatusSkipped is the outcome of the transactions of an atomic batch which another one has failed.
+	BatchStatusSkipped = "skipped"
+)
+
+// Batch is the request of a game server to process many transactions at once. An atomic batch is processed
+// in a single db tx, so either every transaction of it is applied or none.
+type Batch struct {
+	Atomic       bool           `json:"atomic"`
+	Transactions []*Transaction `json:"transactions"`
+}
+
+// BatchResult is the outcome of a transaction of a batch. Err is the reason of a failure,
+// the http handler describes it in Error.
+type BatchResult struct {
+	TransactionID string `json:"transactionId"`
+	Status        string `json:"status"`
+	Error         *Error `json:"error,omitempty"`
+	Err           error  `json:"-"`
+}
+
+// BatchResponse is the representation of the results of a batch, in the order of its transactions.
+type BatchResponse struct {
+	Results []*BatchResult `json:"results"`
+}
+
+// NewBatchResult returns the result of a transaction processed on its own.
+func NewBatchResult(tr *Transaction, replayed bool, err error) *BatchResult {
+	result := &BatchResult{TransactionID: tr.TransactionID, Status: BatchStatusProcessed}
+
+	switch {
+	case err != nil:
+		result.Status = BatchStatusFailed
+		result.Err = err
+	case replayed:
+		result.Status = BatchStatusDuplicate
+	}
+
+	return result
+}
+
+// FailBatch returns the results of an atomic batch whose transaction at index failed with err,
+// none of the other transactions is applied.
+func FailBatch(trs []*Transaction, index int, err error) []*BatchResult {
+	results := make([]*BatchResult, len(trs))
+
+	for i, tr := range trs {
+		results[i] = &BatchResult{TransactionID: tr.TransactionID, Status: BatchStatusSkipped}
+	}
+
+	results[index].Status = BatchStatusFailed
+	results[index].Err = err
+
+	return results
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBatchResult(t *testing.T) {
+	tr := &Transaction{TransactionID: "1"}
+
+	require.Equal(t, &BatchResult{TransactionID: "1", Status: BatchStatusProcessed}, NewBatchResult(tr, false, nil))
+	require.Equal(t, &BatchResult{TransactionID: "1", Status: BatchStatusDuplicate}, NewBatchResult(tr, true, nil))
+	require.Equal(t, &BatchResult{TransactionID: "1", Status: BatchStatusFailed, Err: ErrorUserNotFound}, NewBatchResult(tr, false, ErrorUserNotFound))
+}
+
+func TestFailBatch(t *testing.T) {
+	results := FailBatch([]*Transaction{{TransactionID: "1"}, {TransactionID: "2"}, {TransactionID: "3"}}, 1, ErrorInsufficientBalance)
+
+	require.Equal(t, []*BatchResult{
+		{TransactionID: "1", Status: BatchStatusSkipped},
+		{TransactionID: "2", Status: BatchStatusFailed, Err: ErrorInsufficientBalance},
+		{TransactionID: "3", Status: BatchStatusSkipped},
+	}, results)
+}
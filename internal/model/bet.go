@@ -0,0 +1,99 @@
+package model
+
+import "time"
+
+const (
+	// BetStatePlaced is the state of a bet whose stake is reserved until it is settled or voided.
+	BetStatePlaced  = "placed"
+	BetStateWon     = "won"
+	BetStateLost    = "lost"
+	BetStateVoid    = "void"
+	BetStateExpired = "expired"
+
+	// BetOutcomeWin and BetOutcomeLoss are the outcomes a bet is settled with.
+	BetOutcomeWin  = "win"
+	BetOutcomeLoss = "loss"
+
+	// BetTransactionIDPrefix starts the transactionIds of the transactions bets are settled with.
+	BetTransactionIDPrefix = "bet:"
+)
+
+// BetDao is the domain object for bets table.
+type BetDao struct {
+	ID            string     `db:"id"`
+	UserID        string     `db:"user_id"`
+	Currency      string     `db:"currency"`
+	BetID         string     `db:"bet_id"`
+	Stake         Money      `db:"stake"`
+	State         string     `db:"state"`
+	WinAmount     Money      `db:"win_amount"`
+	TransactionID *string    `db:"transaction_id"`
+	CreatedAt     time.Time  `db:"created_at"`
+	ExpiresAt     time.Time  `db:"expires_at"`
+	SettledAt     *time.Time `db:"settled_at"`
+}
+
+// Bet is the representation of a bet.
+type Bet struct {
+	ID            string     `json:"id"`
+	UserID        string     `json:"userId"`
+	Currency      string     `json:"currency"`
+	BetID         string     `json:"betId"`
+	Stake         Money      `json:"stake"`
+	State         string     `json:"state"`
+	WinAmount     Money      `json:"winAmount"`
+	TransactionID *string    `json:"transactionId"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	ExpiresAt     time.Time  `json:"expiresAt"`
+	SettledAt     *time.Time `json:"settledAt"`
+}
+
+// PlaceBet is the request of a game server to place a bet, BetID is the id of the bet in the game server.
+type PlaceBet struct {
+	BetID    string `json:"betId" validate:"required,max=96"`
+	Stake    Money  `json:"stake" validate:"required,gt=0"`
+	Currency string `json:"currency" validate:"required,iso4217"`
+	UserID   string `validate:"required"`
+}
+
+// SettleBet is the request to settle a placed bet. Amount is what a winning bet wins on top of its stake,
+// it is ignored for a lost bet.
+type SettleBet struct {
+	ID      string `validate:"required"`
+	Outcome string `json:"outcome" validate:"required,oneof=win loss"`
+	Amount  Money  `json:"amount" validate:"gte=0"`
+}
+
+// IsReplayOf reports whether the stored bet has the same payload as the given request,
+// i.e. the request is a retry of an already placed bet.
+func (b *BetDao) IsReplayOf(req *PlaceBet) bool {
+	return b.UserID == req.UserID &&
+		b.Stake == req.Stake &&
+		b.Currency == req.Currency
+}
+
+// Transaction returns the game transaction a placed bet is settled with, or nil if the settlement
+// does not change the balance beyond releasing the stake.
+func (b *BetDao) Transaction(req *SettleBet) *Transaction {
+	tr := &Transaction{
+		TransactionID: BetTransactionIDPrefix + b.BetID,
+		State:         StateLost,
+		Amount:        b.Stake,
+		Currency:      b.Currency,
+		UserID:        b.UserID,
+		SourceType:    SourceTypeGame,
+	}
+
+	if req.Outcome == BetOutcomeLoss {
+		return tr
+	}
+
+	if req.Amount == 0 {
+		return nil
+	}
+
+	tr.State = StateWin
+	tr.Amount = req.Amount
+
+	return tr
+}
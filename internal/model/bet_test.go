@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetTransaction(t *testing.T) {
+	b := &BetDao{ID: "1", UserID: "2", Currency: "EUR", BetID: "round-1", Stake: 100}
+
+	require.Equal(t, &Transaction{
+		TransactionID: "bet:round-1",
+		State:         StateLost,
+		Amount:        100,
+		Currency:      "EUR",
+		UserID:        "2",
+		SourceType:    SourceTypeGame,
+	}, b.Transaction(&SettleBet{Outcome: BetOutcomeLoss, Amount: 500}))
+
+	tr := b.Transaction(&SettleBet{Outcome: BetOutcomeWin, Amount: 250})
+	require.Equal(t, StateWin, tr.State)
+	require.Equal(t, Money(250), tr.Amount)
+
+	// A win of nothing only gives the stake back.
+	require.Nil(t, b.Transaction(&SettleBet{Outcome: BetOutcomeWin}))
+
+	// A given transactionId cannot take the transactionId of a settlement.
+	require.Equal(t, true, IsReservedTransactionID(tr.TransactionID))
+	require.Error(t, NewValidator().Var(tr.TransactionID, "transaction_id"))
+}
+
+func TestBetIsReplayOf(t *testing.T) {
+	b := &BetDao{UserID: "2", Currency: "EUR", BetID: "round-1", Stake: 100}
+
+	require.Equal(t, true, b.IsReplayOf(&PlaceBet{BetID: "round-1", Stake: 100, Currency: "EUR", UserID: "2"}))
+	require.Equal(t, false, b.IsReplayOf(&PlaceBet{BetID: "round-1", Stake: 200, Currency: "EUR", UserID: "2"}))
+	require.Equal(t, false, b.IsReplayOf(&PlaceBet{BetID: "round-1", Stake: 100, Currency: "EUR", UserID: "3"}))
+}
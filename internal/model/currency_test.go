@@ -0,0 +1,23 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsCurrency(t *testing.T) {
+	require.Equal(t, true, IsCurrency("EUR"))
+	require.Equal(t, true, IsCurrency("USD"))
+	require.Equal(t, false, IsCurrency("eur"))
+	require.Equal(t, false, IsCurrency("EURO"))
+	require.Equal(t, false, IsCurrency(""))
+}
+
+func TestValidateCurrency(t *testing.T) {
+	sv := NewValidator()
+
+	require.NoError(t, sv.Struct(&OpenWallet{Currency: "EUR"}))
+	require.Error(t, sv.Struct(&OpenWallet{Currency: "XYZ"}))
+	require.Error(t, sv.Struct(&OpenWallet{}))
+}
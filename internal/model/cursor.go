@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+)
+
+// EncodeCursor encodes the sequence number of the last returned transaction into an opaque page cursor.
+func EncodeCursor(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(seq, 10)))
+}
+
+// DecodeCursor decodes a page cursor created by EncodeCursor, an empty cursor means the first page.
+func DecodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode cursor: %w", ErrorInvalidCursor)
+	}
+
+	seq, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil || seq <= 0 {
+		return 0, fmt.Errorf("failed to parse cursor: %w", ErrorInvalidCursor)
+	}
+
+	return seq, nil
+}
@@ -12,10 +12,68 @@ var (
 	ErrorInternalServerError = errors.New("internal server error")
 	// ErrorUserNotFound will throw if the requested user is not found
 	ErrorUserNotFound = errors.New("user not found")
+	// ErrorUserSuspended will throw if the user is suspended and cannot make transactions
+	ErrorUserSuspended = errors.New("user is suspended")
+	// ErrorUserClosed will throw if the wallet of the user is closed
+	ErrorUserClosed = errors.New("user is closed")
+	// ErrorUserSelfExcluded will throw if the user has excluded themselves from playing for now
+	ErrorUserSelfExcluded = errors.New("user is self-excluded")
+	// ErrorExclusionNotFound will throw if the user has no exclusion in effect
+	ErrorExclusionNotFound = errors.New("exclusion not found")
+	// ErrorUserStatusConflict will throw if the requested status change is not allowed from the current status
+	ErrorUserStatusConflict = errors.New("user status does not allow the operation")
+	// ErrorUserHasFunds will throw if a user to close still has money in a wallet or an open bet
+	ErrorUserHasFunds = errors.New("user still has funds or open bets")
+	// ErrorWalletNotFound will throw if the user has no wallet in the requested currency
+	ErrorWalletNotFound = errors.New("wallet not found for the currency")
+	// ErrorWalletAlreadyExists will throw if the user already has a wallet in the requested currency
+	ErrorWalletAlreadyExists = errors.New("wallet already exists for the currency")
 	// ErrorInsufficientBalance will throw if the request cannot be processed due to insufficient balance
 	ErrorInsufficientBalance = errors.New("insufficient balance error")
 	// ErrorTransactionAlreadyExists will throw if the given transactionId param has already been processed
 	ErrorTransactionAlreadyExists = errors.New("transactionID already exists")
+	// ErrorTransactionNotFound will throw if the requested transaction is not found
+	ErrorTransactionNotFound = errors.New("transaction not found")
+	// ErrorCancellationInsufficientBalance will throw if reversing a cancelled win would make the balance of the user negative
+	ErrorCancellationInsufficientBalance = errors.New("insufficient balance to reverse the transaction")
+	// ErrorLossLimitExceeded will throw if a loss would take the user over one of their loss limits
+	ErrorLossLimitExceeded = errors.New("loss limit exceeded")
+	// ErrorDepositLimitExceeded will throw if a deposit would take the user over one of their deposit limits
+	ErrorDepositLimitExceeded = errors.New("deposit limit exceeded")
+	// ErrorLimitNotFound will throw if the user has no limit of the requested kind and period
+	ErrorLimitNotFound = errors.New("limit not found")
+	// ErrorUnknownState will throw if the state or source type of a transaction is not in the registry
+	ErrorUnknownState = errors.New("unknown transaction state or source type")
+	// ErrorPaymentNotPending will throw if a payment is confirmed or failed when it is not pending anymore
+	ErrorPaymentNotPending = errors.New("payment is not pending")
+	// ErrorPaymentState will throw if a transaction is processed in a state which only the payment flow sets
+	ErrorPaymentState = errors.New("state is set by the payment flow only")
+	// ErrorAdjustmentState will throw if a transaction is processed as an adjustment, which only support staff make
+	ErrorAdjustmentState = errors.New("adjustments are made by support staff only")
+	// ErrorTransferNotFound will throw if the requested transfer is not found
+	ErrorTransferNotFound = errors.New("transfer not found")
+	// ErrorTransferAlreadyExists will throw if the given idempotency key has already been used by another transfer
+	ErrorTransferAlreadyExists = errors.New("idempotency key already used by another transfer")
+	// ErrorTransferState will throw if a transaction is processed or cancelled as a leg of a transfer
+	ErrorTransferState = errors.New("transfers are made and kept by the transfer flow only")
+	// ErrorUnauthorized will throw if the request has no valid admin token
+	ErrorUnauthorized = errors.New("unauthorized")
+	// ErrorBetNotFound will throw if the requested bet is not found
+	ErrorBetNotFound = errors.New("bet not found")
+	// ErrorBetAlreadyExists will throw if the given betId param has already been placed with another payload
+	ErrorBetAlreadyExists = errors.New("betID already exists")
+	// ErrorBetNotPlaced will throw if a bet is settled or voided when it is not open anymore
+	ErrorBetNotPlaced = errors.New("bet is not open")
+	// ErrorInvalidCursor will throw if the given page cursor is malformed
+	ErrorInvalidCursor = errors.New("invalid cursor")
+	// ErrorInvalidMoney will throw if a monetary value cannot be represented with two decimal places
+	ErrorInvalidMoney = errors.New("invalid money value")
+	// ErrorInvalidLine will throw if a line of an import is not a json transaction
+	ErrorInvalidLine = errors.New("line is not a valid json transaction")
+	// ErrorMigrationDirty will throw if the last migration of the database failed halfway
+	ErrorMigrationDirty = errors.New("database schema is dirty")
+	// ErrorMigrationBehind will throw if the database misses migrations the service needs
+	ErrorMigrationBehind = errors.New("database schema is behind")
 )
 
 type Error struct {
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+const (
+	// ExclusionPeriodDay is the period of a 24 hours time-out.
+	ExclusionPeriodDay = "24h"
+	// ExclusionPeriodWeek is the period of a 7 days time-out.
+	ExclusionPeriodWeek = "7d"
+	// ExclusionPeriodPermanent is the period of an exclusion which never ends.
+	ExclusionPeriodPermanent = "permanent"
+)
+
+// ExclusionDao is the domain object for exclusions table.
+type ExclusionDao struct {
+	ID        string     `db:"id"`
+	UserID    string     `db:"user_id"`
+	Period    string     `db:"period"`
+	StartedAt time.Time  `db:"started_at"`
+	EndsAt    *time.Time `db:"ends_at"`
+}
+
+// Exclusion is the representation of an exclusion.
+type Exclusion struct {
+	ID        string     `json:"id"`
+	Period    string     `json:"period"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndsAt    *time.Time `json:"endsAt,omitempty"`
+}
+
+// StartExclusion is the request of a user to exclude themselves from playing.
+type StartExclusion struct {
+	UserID string `validate:"required"`
+	Period string `json:"period" validate:"required,oneof=24h 7d permanent"`
+}
+
+// ExclusionEnd returns the end of an exclusion of the given period started at the given time,
+// nil for a permanent exclusion.
+func ExclusionEnd(period string, start time.Time) *time.Time {
+	var end time.Time
+
+	switch period {
+	case ExclusionPeriodDay:
+		end = start.Add(24 * time.Hour)
+	case ExclusionPeriodWeek:
+		end = start.AddDate(0, 0, 7)
+	default:
+		return nil
+	}
+
+	return &end
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclusionEnd(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	require.Equal(t, time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), *ExclusionEnd(ExclusionPeriodDay, start))
+	require.Equal(t, time.Date(2024, 5, 8, 10, 0, 0, 0, time.UTC), *ExclusionEnd(ExclusionPeriodWeek, start))
+	require.Nil(t, ExclusionEnd(ExclusionPeriodPermanent, start))
+}
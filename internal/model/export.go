@@ -0,0 +1,72 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	// ExportFormatNDJSON and ExportFormatCSV are the formats transactions are exported in.
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+)
+
+// ExportFilter selects the transactions of an export, every field but Format is optional.
+type ExportFilter struct {
+	UserID string `validate:"omitempty,uuid"`
+	From   *time.Time
+	To     *time.Time
+	Format string `validate:"required,oneof=ndjson csv"`
+}
+
+// TransactionCSVHeader is the header row of the csv export, in the order of TransactionView.CSVRecord.
+var TransactionCSVHeader = []string{
+	"id",
+	"transactionId",
+	"userId",
+	"sourceType",
+	"state",
+	"amount",
+	"currency",
+	"bonusAmount",
+	"createdAt",
+	"cancelled",
+	"cancelledAt",
+	"operatorId",
+	"reason",
+}
+
+// CSVRecord returns the transaction as a row of the csv export, a missing value is an empty field.
+func (t *TransactionView) CSVRecord() []string {
+	return []string{
+		t.ID,
+		t.TransactionID,
+		t.UserID,
+		t.SourceType,
+		t.State,
+		t.Amount.String(),
+		t.Currency,
+		t.BonusAmount.String(),
+		t.CreatedAt.UTC().Format(time.RFC3339Nano),
+		strconv.FormatBool(t.Cancelled),
+		formatOptionalTime(t.CancelledAt),
+		optionalString(t.OperatorID),
+		optionalString(t.Reason),
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func optionalString(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
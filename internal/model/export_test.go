@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVRecord(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 600, time.FixedZone("CET", 3600))
+	operator, reason := "agent-7", "missing win, round 42"
+
+	tr := &TransactionView{
+		ID:            "1",
+		TransactionID: "a",
+		UserID:        "u",
+		SourceType:    SourceTypeAdjustment,
+		State:         StateAdjustmentCredit,
+		Amount:        1050,
+		Currency:      "EUR",
+		CreatedAt:     createdAt,
+		OperatorID:    &operator,
+		Reason:        &reason,
+	}
+
+	record := tr.CSVRecord()
+	require.Len(t, record, len(TransactionCSVHeader))
+	require.Equal(t, []string{
+		"1", "a", "u", "adjustment", "adjustment_credit", "10.50", "EUR", "0.00",
+		"2024-01-02T02:04:05.0000006Z", "false", "", "agent-7", "missing win, round 42",
+	}, record)
+}
@@ -0,0 +1,21 @@
+package model
+
+// ImportProgress is the progress of an import of newline-delimited json transactions. Lines counts the lines
+// read so far, blank ones included, so a failed line can be found in the input by its number.
+type ImportProgress struct {
+	Lines      int    `json:"lines"`
+	Processed  int    `json:"processed"`
+	Duplicates int    `json:"duplicates"`
+	Failed     int    `json:"failed"`
+	Done       bool   `json:"done"`
+	Error      *Error `json:"error,omitempty"`
+}
+
+// ImportFailure is a line of an import which could not be processed. Err is the reason of the failure,
+// the http handler describes it in Error.
+type ImportFailure struct {
+	Line          int    `json:"line"`
+	TransactionID string `json:"transactionId,omitempty"`
+	Error         *Error `json:"error,omitempty"`
+	Err           error  `json:"-"`
+}
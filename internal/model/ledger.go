@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+const (
+	// LedgerReasonTransaction is the reason of a balance change made by a processed transaction.
+	LedgerReasonTransaction = "transaction"
+	// LedgerReasonCancellation is the reason of a balance change made by a cancelled transaction.
+	LedgerReasonCancellation = "cancellation"
+	// LedgerReasonAdjustment is the reason of a balance change made outside of transactions.
+	LedgerReasonAdjustment = "adjustment"
+	// LedgerReasonBonus is the reason of a granted bonus.
+	LedgerReasonBonus = "bonus"
+	// LedgerReasonConversion is the reason of a bonus turned into real money once its wagering is met.
+	LedgerReasonConversion = "conversion"
+
+	// LedgerBucketReal is the bucket of the real money of a wallet.
+	LedgerBucketReal = "real"
+	// LedgerBucketBonus is the bucket of the bonus funds of a wallet.
+	LedgerBucketBonus = "bonus"
+)
+
+// LedgerEntryDao is the domain object for ledger_entries table.
+type LedgerEntryDao struct {
+	ID            string    `db:"id"`
+	Seq           int64     `db:"seq"`
+	UserID        string    `db:"user_id"`
+	TransactionID *string   `db:"transaction_id"`
+	Reason        string    `db:"reason"`
+	Currency      string    `db:"currency"`
+	Bucket        string    `db:"bucket"`
+	Amount        Money     `db:"amount"`
+	BalanceBefore Money     `db:"balance_before"`
+	BalanceAfter  Money     `db:"balance_after"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// LedgerMismatch is a wallet whose real or bonus balance differs from the sum of the ledger entries of the bucket.
+type LedgerMismatch struct {
+	UserID             string `db:"user_id" json:"userId"`
+	Currency           string `db:"currency" json:"currency"`
+	Balance            Money  `db:"balance" json:"balance"`
+	LedgerBalance      Money  `db:"ledger_balance" json:"ledgerBalance"`
+	BonusBalance       Money  `db:"bonus_balance" json:"bonusBalance"`
+	LedgerBonusBalance Money  `db:"ledger_bonus_balance" json:"ledgerBonusBalance"`
+}
@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+const (
+	// LimitKindLoss is the kind of a limit on the net loss of a user in game transactions.
+	LimitKindLoss = "loss"
+	// LimitKindDeposit is the kind of a limit on the money a user pays in.
+	LimitKindDeposit = "deposit"
+
+	// LimitPeriodDay is the period of a limit which starts over at midnight UTC.
+	LimitPeriodDay = "day"
+	// LimitPeriodWeek is the period of a limit which starts over on Monday midnight UTC.
+	LimitPeriodWeek = "week"
+	// LimitPeriodMonth is the period of a limit which starts over on the first day of the month UTC.
+	LimitPeriodMonth = "month"
+)
+
+// LimitDao is the domain object for limits table, a user has at most one limit of a kind and period per currency.
+type LimitDao struct {
+	UserID        string     `db:"user_id"`
+	Currency      string     `db:"currency"`
+	Kind          string     `db:"kind"`
+	Period        string     `db:"period"`
+	Amount        Money      `db:"amount"`
+	PendingAmount *Money     `db:"pending_amount"`
+	PendingFrom   *time.Time `db:"pending_from"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
+
+// Limit is the representation of a limit.
+type Limit struct {
+	Currency      string     `json:"currency"`
+	Kind          string     `json:"kind"`
+	Period        string     `json:"period"`
+	Amount        string     `json:"amount"`
+	PendingAmount *string    `json:"pendingAmount,omitempty"`
+	PendingFrom   *time.Time `json:"pendingFrom,omitempty"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// SetLimit is the request to set, raise or lower a limit.
+type SetLimit struct {
+	UserID   string `validate:"required"`
+	Currency string `json:"currency" validate:"required,iso4217"`
+	Kind     string `json:"kind" validate:"required,oneof=loss deposit"`
+	Period   string `json:"period" validate:"required,oneof=day week month"`
+	Amount   Money  `json:"amount" validate:"gte=0"`
+}
+
+// Settle makes a pending change of the limit which is due at the given time the current amount.
+func (l *LimitDao) Settle(now time.Time) {
+	if l.PendingAmount == nil || now.Before(*l.PendingFrom) {
+		return
+	}
+
+	l.Amount = *l.PendingAmount
+	l.PendingAmount = nil
+	l.PendingFrom = nil
+}
+
+// Change sets the limit to the given amount. A lower limit applies at once and drops a pending raise,
+// a higher one is kept pending until the cool-off is over.
+func (l *LimitDao) Change(amount Money, now time.Time, coolOff time.Duration) {
+	l.Settle(now)
+
+	if amount <= l.Amount {
+		l.Amount = amount
+		l.PendingAmount = nil
+		l.PendingFrom = nil
+
+		return
+	}
+
+	from := now.Add(coolOff)
+	l.PendingAmount = &amount
+	l.PendingFrom = &from
+}
+
+// PeriodStart returns the start of the current period of the limit.
+func (l *LimitDao) PeriodStart(now time.Time) time.Time {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch l.Period {
+	case LimitPeriodWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case LimitPeriodMonth:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitChange(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	coolOff := 24 * time.Hour
+
+	limit := &LimitDao{Amount: 1000}
+
+	// Lowering applies at once.
+	limit.Change(500, now, coolOff)
+	require.Equal(t, Money(500), limit.Amount)
+	require.Nil(t, limit.PendingAmount)
+
+	// Raising waits for the cool-off.
+	limit.Change(2000, now, coolOff)
+	require.Equal(t, Money(500), limit.Amount)
+	require.Equal(t, Money(2000), *limit.PendingAmount)
+	require.Equal(t, now.Add(coolOff), *limit.PendingFrom)
+
+	limit.Settle(now.Add(time.Hour))
+	require.Equal(t, Money(500), limit.Amount)
+
+	// Lowering drops the pending raise.
+	limit.Change(400, now, coolOff)
+	require.Equal(t, Money(400), limit.Amount)
+	require.Nil(t, limit.PendingAmount)
+	require.Nil(t, limit.PendingFrom)
+
+	limit.Change(800, now, coolOff)
+	limit.Settle(now.Add(coolOff))
+	require.Equal(t, Money(800), limit.Amount)
+	require.Nil(t, limit.PendingAmount)
+}
+
+func TestLimitPeriodStart(t *testing.T) {
+	// 2024-05-01 is a Wednesday.
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	require.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), (&LimitDao{Period: LimitPeriodDay}).PeriodStart(now))
+	require.Equal(t, time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC), (&LimitDao{Period: LimitPeriodWeek}).PeriodStart(now))
+	require.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), (&LimitDao{Period: LimitPeriodMonth}).PeriodStart(now))
+
+	sunday := time.Date(2024, 5, 5, 23, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC), (&LimitDao{Period: LimitPeriodWeek}).PeriodStart(sunday))
+}
+
+func TestLimitKind(t *testing.T) {
+	require.Equal(t, LimitKindLoss, (&Transaction{SourceType: "game"}).LimitKind(DirectionDebit))
+	require.Equal(t, LimitKindDeposit, (&Transaction{SourceType: "payment"}).LimitKind(DirectionCredit))
+	require.Equal(t, "", (&Transaction{SourceType: "game"}).LimitKind(DirectionCredit))
+	require.Equal(t, "", (&Transaction{SourceType: "payment"}).LimitKind(DirectionDebit))
+	require.Equal(t, "", (&Transaction{SourceType: "game"}).LimitKind(DirectionNone))
+}
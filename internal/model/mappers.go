@@ -8,5 +8,123 @@ func TransactionToTransactionDao(t *Transaction) *TransactionDao {
 		SourceType:    t.SourceType,
 		State:         t.State,
 		Amount:        t.Amount,
+		Currency:      t.Currency,
+	}
+}
+
+// TransactionDaoToTransactionView converts a transaction dao to its representation.
+func TransactionDaoToTransactionView(t *TransactionDao) *TransactionView {
+	return &TransactionView{
+		ID:            t.ID,
+		TransactionID: t.TransactionID,
+		UserID:        t.UserID,
+		SourceType:    t.SourceType,
+		State:         t.State,
+		Amount:        t.Amount,
+		Currency:      t.Currency,
+		BonusAmount:   t.BonusAmount,
+		CreatedAt:     t.CreatedAt,
+		Cancelled:     t.Cancelled,
+		CancelledAt:   t.CancelledAt,
+		OperatorID:    t.OperatorID,
+		Reason:        t.Reason,
+	}
+}
+
+// UserDaoToUser converts a user dao to its representation.
+func UserDaoToUser(u *UserDao) *User {
+	return &User{
+		ID:        u.ID,
+		Status:    u.Status,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+// WalletDaoToWallet converts a wallet dao to its representation.
+func WalletDaoToWallet(w *WalletDao) *Wallet {
+	return &Wallet{
+		Currency:         w.Currency,
+		Balance:          w.Balance.String(),
+		Reserved:         w.Reserved.String(),
+		BonusBalance:     w.BonusBalance.String(),
+		WageringRequired: w.WageringRequired.String(),
+		Wagered:          w.Wagered.String(),
+		UpdatedAt:        w.UpdatedAt,
+	}
+}
+
+// WalletDaosToBalance converts the wallets of a user to the balance of the user.
+func WalletDaosToBalance(userID string, wallets []*WalletDao) *Balance {
+	balance := &Balance{
+		UserID:  userID,
+		Wallets: make([]*Wallet, 0, len(wallets)),
+	}
+
+	for _, w := range wallets {
+		balance.Wallets = append(balance.Wallets, WalletDaoToWallet(w))
+	}
+
+	return balance
+}
+
+// LimitDaoToLimit converts a limit dao to its representation.
+func LimitDaoToLimit(l *LimitDao) *Limit {
+	limit := &Limit{
+		Currency:    l.Currency,
+		Kind:        l.Kind,
+		Period:      l.Period,
+		Amount:      l.Amount.String(),
+		PendingFrom: l.PendingFrom,
+		UpdatedAt:   l.UpdatedAt,
+	}
+
+	if l.PendingAmount != nil {
+		pending := l.PendingAmount.String()
+		limit.PendingAmount = &pending
+	}
+
+	return limit
+}
+
+// ExclusionDaoToExclusion converts an exclusion dao to its representation.
+func ExclusionDaoToExclusion(e *ExclusionDao) *Exclusion {
+	return &Exclusion{
+		ID:        e.ID,
+		Period:    e.Period,
+		StartedAt: e.StartedAt,
+		EndsAt:    e.EndsAt,
+	}
+}
+
+// BetDaoToBet converts a bet dao to its representation.
+func BetDaoToBet(b *BetDao) *Bet {
+	return &Bet{
+		ID:            b.ID,
+		UserID:        b.UserID,
+		Currency:      b.Currency,
+		BetID:         b.BetID,
+		Stake:         b.Stake,
+		State:         b.State,
+		WinAmount:     b.WinAmount,
+		TransactionID: b.TransactionID,
+		CreatedAt:     b.CreatedAt,
+		ExpiresAt:     b.ExpiresAt,
+		SettledAt:     b.SettledAt,
+	}
+}
+
+// TransferDaoToTransferView converts a transfer dao to its representation.
+func TransferDaoToTransferView(t *TransferDao) *TransferView {
+	return &TransferView{
+		ID:                  t.ID,
+		IdempotencyKey:      t.IdempotencyKey,
+		FromUserID:          t.FromUserID,
+		ToUserID:            t.ToUserID,
+		Amount:              t.Amount,
+		Currency:            t.Currency,
+		DebitTransactionID:  t.DebitTransactionID,
+		CreditTransactionID: t.CreditTransactionID,
+		CreatedAt:           t.CreatedAt,
 	}
 }
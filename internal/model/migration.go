@@ -0,0 +1,25 @@
+package model
+
+import "fmt"
+
+// MigrationStatus is the migration version of the database and the latest migration embedded in the service.
+type MigrationStatus struct {
+	Version uint `json:"version"`
+	Latest  uint `json:"latest"`
+	Dirty   bool `json:"dirty"`
+}
+
+// Check returns an error if the service cannot serve with the schema of the database, i.e. the last
+// migration failed halfway or migrations are missing. A newer schema is accepted, so a rollback
+// of the service does not require reverting the migrations.
+func (s *MigrationStatus) Check() error {
+	if s.Dirty {
+		return fmt.Errorf("version %d: %w", s.Version, ErrorMigrationDirty)
+	}
+
+	if s.Version < s.Latest {
+		return fmt.Errorf("version %d of %d: %w", s.Version, s.Latest, ErrorMigrationBehind)
+	}
+
+	return nil
+}
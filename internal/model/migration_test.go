@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationStatus_Check(t *testing.T) {
+	tests := []struct {
+		name   string
+		status MigrationStatus
+		err    error
+	}{
+		{name: "Current", status: MigrationStatus{Version: 17, Latest: 17}},
+		{name: "Newer", status: MigrationStatus{Version: 18, Latest: 17}},
+		{name: "Behind", status: MigrationStatus{Version: 16, Latest: 17}, err: ErrorMigrationBehind},
+		{name: "Nothing applied", status: MigrationStatus{Latest: 17}, err: ErrorMigrationBehind},
+		{name: "Dirty", status: MigrationStatus{Version: 17, Latest: 17, Dirty: true}, err: ErrorMigrationDirty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.status.Check()
+			if tt.err == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is a fixed-point monetary amount stored as a number of cents.
+// It matches the NUMERIC(18,2) columns of the database without any rounding.
+type Money int64
+
+// ParseMoney parses a decimal string such as "10", "10.1" or "-10.15" into Money.
+// Values with more than two decimal places, e.g. "10.155", are rejected.
+func ParseMoney(s string) (Money, error) {
+	str := strings.TrimSpace(s)
+	if str == "" {
+		return 0, fmt.Errorf("empty value: %w", ErrorInvalidMoney)
+	}
+
+	negative := false
+
+	switch str[0] {
+	case '-':
+		negative = true
+		str = str[1:]
+	case '+':
+		str = str[1:]
+	}
+
+	whole, frac, _ := strings.Cut(str, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("malformed value %q: %w", s, ErrorInvalidMoney)
+	}
+
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("value %q has more than two decimal places: %w", s, ErrorInvalidMoney)
+	}
+
+	frac += strings.Repeat("0", 2-len(frac))
+
+	cents, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value %q is out of range: %w", s, ErrorInvalidMoney)
+	}
+
+	if negative {
+		cents = -cents
+	}
+
+	return Money(cents), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the decimal representation with exactly two decimal places.
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimal places.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number into Money without going through a float.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	if str == "null" {
+		return nil
+	}
+
+	if strings.ContainsAny(str, "\"eE") {
+		return fmt.Errorf("amount must be a plain JSON number: %w", ErrorInvalidMoney)
+	}
+
+	v, err := ParseMoney(str)
+	if err != nil {
+		return err
+	}
+
+	*m = v
+
+	return nil
+}
+
+// Scan implements sql.Scanner for NUMERIC columns.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+
+		return nil
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = Money(v * 100)
+
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money: %w", src, ErrorInvalidMoney)
+	}
+}
+
+func (m *Money) scanString(s string) error {
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+
+	*m = v
+
+	return nil
+}
+
+// Value implements driver.Valuer, the amount is sent as a decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
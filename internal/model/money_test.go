@@ -0,0 +1,80 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected Money
+		err      error
+	}{
+		{name: "Integer", input: "10", expected: 1000},
+		{name: "One decimal", input: "10.1", expected: 1010},
+		{name: "Two decimals", input: "10.15", expected: 1015},
+		{name: "Negative", input: "-0.05", expected: -5},
+		{name: "Three decimals", input: "10.155", err: ErrorInvalidMoney},
+		{name: "Trailing zero beyond scale", input: "10.150", err: ErrorInvalidMoney},
+		{name: "Exponent", input: "1e2", err: ErrorInvalidMoney},
+		{name: "Empty", input: "", err: ErrorInvalidMoney},
+		{name: "Missing whole part", input: ".5", err: ErrorInvalidMoney},
+		{name: "Overflow", input: "99999999999999999999", err: ErrorInvalidMoney},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ParseMoney(tc.input)
+			if tc.err != nil {
+				require.Equal(t, true, errors.Is(err, tc.err))
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	require.Equal(t, "0.00", Money(0).String())
+	require.Equal(t, "10.05", Money(1005).String())
+	require.Equal(t, "-0.10", Money(-10).String())
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var tr Transaction
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":10.15}`), &tr))
+	require.Equal(t, Money(1015), tr.Amount)
+
+	require.Error(t, json.Unmarshal([]byte(`{"amount":10.155}`), &tr))
+	require.Error(t, json.Unmarshal([]byte(`{"amount":"10.15"}`), &tr))
+
+	data, err := json.Marshal(struct {
+		Amount Money `json:"amount"`
+	}{Amount: 1015})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":10.15}`, string(data))
+}
+
+func TestMoneyScanValue(t *testing.T) {
+	var m Money
+
+	require.NoError(t, m.Scan([]byte("123.40")))
+	require.Equal(t, Money(12340), m)
+
+	require.NoError(t, m.Scan(nil))
+	require.Equal(t, Money(0), m)
+
+	v, err := Money(12340).Value()
+	require.NoError(t, err)
+	require.Equal(t, "123.40", v)
+}
@@ -0,0 +1,76 @@
+package model
+
+const (
+	// PaymentKindDeposit is the kind of a payment which brings money into the wallet.
+	PaymentKindDeposit = "deposit"
+	// PaymentKindWithdrawal is the kind of a payment which takes money out of the wallet.
+	PaymentKindWithdrawal = "withdrawal"
+
+	// States of the payment lifecycle, a payment starts pending and is then either confirmed or failed.
+	StateDepositPending      = "deposit_pending"
+	StateDepositConfirmed    = "deposit_confirmed"
+	StateDepositFailed       = "deposit_failed"
+	StateWithdrawalPending   = "withdrawal_pending"
+	StateWithdrawalConfirmed = "withdrawal_confirmed"
+	StateWithdrawalFailed    = "withdrawal_failed"
+)
+
+// Payment is the request to start a deposit or a withdrawal, it stays pending until the payment provider
+// confirms or fails it.
+type Payment struct {
+	TransactionID string `json:"transactionId" validate:"required,transaction_id"`
+	Amount        Money  `json:"amount" validate:"required,gt=0"`
+	Currency      string `json:"currency" validate:"required,iso4217"`
+	UserID        string `validate:"required"`
+	Kind          string `validate:"required,oneof=deposit withdrawal"`
+}
+
+// Transaction returns the pending transaction of the payment.
+func (p *Payment) Transaction() *Transaction {
+	state := StateDepositPending
+	if p.Kind == PaymentKindWithdrawal {
+		state = StateWithdrawalPending
+	}
+
+	return &Transaction{
+		TransactionID: p.TransactionID,
+		State:         state,
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		UserID:        p.UserID,
+		SourceType:    SourceTypePayment,
+	}
+}
+
+// IsPaymentState reports whether the state belongs to the lifecycle of payments,
+// such states are set by the payment flow only.
+func IsPaymentState(state string) bool {
+	switch state {
+	case StateDepositPending, StateDepositConfirmed, StateDepositFailed,
+		StateWithdrawalPending, StateWithdrawalConfirmed, StateWithdrawalFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// CompletedState returns the state a pending payment moves to when it is confirmed or failed,
+// or an empty string if the transaction is not a pending payment.
+func (t *TransactionDao) CompletedState(confirmed bool) string {
+	if t.Cancelled {
+		return ""
+	}
+
+	switch {
+	case t.State == StateDepositPending && confirmed:
+		return StateDepositConfirmed
+	case t.State == StateDepositPending:
+		return StateDepositFailed
+	case t.State == StateWithdrawalPending && confirmed:
+		return StateWithdrawalConfirmed
+	case t.State == StateWithdrawalPending:
+		return StateWithdrawalFailed
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentTransaction(t *testing.T) {
+	p := &Payment{TransactionID: "1", Amount: 100, Currency: "EUR", UserID: "2", Kind: PaymentKindWithdrawal}
+
+	require.Equal(t, &Transaction{
+		TransactionID: "1",
+		State:         StateWithdrawalPending,
+		Amount:        100,
+		Currency:      "EUR",
+		UserID:        "2",
+		SourceType:    SourceTypePayment,
+	}, p.Transaction())
+
+	p.Kind = PaymentKindDeposit
+	require.Equal(t, StateDepositPending, p.Transaction().State)
+}
+
+func TestCompletedState(t *testing.T) {
+	testCases := []struct {
+		name      string
+		tr        *TransactionDao
+		confirmed bool
+		state     string
+	}{
+		{name: "Confirmed deposit", tr: &TransactionDao{State: StateDepositPending}, confirmed: true, state: StateDepositConfirmed},
+		{name: "Failed deposit", tr: &TransactionDao{State: StateDepositPending}, state: StateDepositFailed},
+		{name: "Confirmed withdrawal", tr: &TransactionDao{State: StateWithdrawalPending}, confirmed: true, state: StateWithdrawalConfirmed},
+		{name: "Failed withdrawal", tr: &TransactionDao{State: StateWithdrawalPending}, state: StateWithdrawalFailed},
+		{name: "Already confirmed", tr: &TransactionDao{State: StateDepositConfirmed}, confirmed: true},
+		{name: "Cancelled", tr: &TransactionDao{State: StateWithdrawalPending, Cancelled: true}, confirmed: true},
+		{name: "Game transaction", tr: &TransactionDao{State: "win"}, confirmed: true},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.state, tc.tr.CompletedState(tc.confirmed))
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator"
+)
+
+const (
+	// DirectionCredit is the direction of a state whose transactions add to the wallet.
+	DirectionCredit = "credit"
+	// DirectionDebit is the direction of a state whose transactions take from the wallet.
+	DirectionDebit = "debit"
+	// DirectionNone is the direction of a state whose transactions leave the wallet as is.
+	DirectionNone = "none"
+
+	// SourceTypePayment is the source type of the transactions of the payment provider.
+	SourceTypePayment = "payment"
+	// SourceTypeGame is the source type of the transactions of the game servers.
+	SourceTypeGame = "game"
+	// SourceTypeServer is the source type of the transactions of the platform itself, e.g. imported ones.
+	SourceTypeServer = "server"
+	// SourceTypeAdjustment is the source type of the manual balance adjustments of support staff.
+	SourceTypeAdjustment = "adjustment"
+	// SourceTypeTransfer is the source type of the two transactions of a transfer between users.
+	SourceTypeTransfer = "transfer"
+
+	// StateWin and StateLost are the states of game outcomes, they are registered by the initial migrations.
+	StateWin  = "win"
+	StateLost = "lost"
+)
+
+// GamingSourceTypes are the source types of gambling transactions, only they are cancelled by the post-processing.
+// Payments, adjustments and transfers move money in and out of the wallet and are never cancelled that way.
+var GamingSourceTypes = []string{SourceTypeGame, SourceTypeServer}
+
+// SourceTypeDao is the domain object for source_types table.
+type SourceTypeDao struct {
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// StateDao is the domain object for states table.
+type StateDao struct {
+	Name      string    `db:"name"`
+	Direction string    `db:"direction"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Registry is a snapshot of the registered source types and states of transactions.
+type Registry struct {
+	sourceTypes []string
+	states      []string
+	directions  map[string]string
+}
+
+// NewRegistry returns the registry of the given source types and states, their order is kept.
+func NewRegistry(sourceTypes []*SourceTypeDao, states []*StateDao) *Registry {
+	r := &Registry{
+		sourceTypes: make([]string, 0, len(sourceTypes)),
+		states:      make([]string, 0, len(states)),
+		directions:  make(map[string]string, len(states)),
+	}
+
+	for _, s := range sourceTypes {
+		r.sourceTypes = append(r.sourceTypes, s.Name)
+	}
+
+	for _, s := range states {
+		r.states = append(r.states, s.Name)
+		r.directions[s.Name] = s.Direction
+	}
+
+	return r
+}
+
+// SourceTypes returns the names of the registered source types.
+func (r *Registry) SourceTypes() []string {
+	return r.sourceTypes
+}
+
+// States returns the names of the registered states.
+func (r *Registry) States() []string {
+	return r.states
+}
+
+// IsSourceType reports whether the source type is registered.
+func (r *Registry) IsSourceType(name string) bool {
+	for _, s := range r.sourceTypes {
+		if s == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Direction returns the direction of a registered state.
+func (r *Registry) Direction(state string) (string, bool) {
+	d, ok := r.directions[state]
+
+	return d, ok
+}
+
+// Validator returns the validator of NewValidator, which also knows the "source_type" and "state" tags
+// of the values in the registry.
+func (r *Registry) Validator() *validator.Validate {
+	sv := NewValidator()
+
+	_ = sv.RegisterValidation("source_type", func(fl validator.FieldLevel) bool {
+		return r.IsSourceType(fl.Field().String())
+	})
+
+	_ = sv.RegisterValidation("state", func(fl validator.FieldLevel) bool {
+		_, ok := r.Direction(fl.Field().String())
+
+		return ok
+	})
+
+	return sv
+}
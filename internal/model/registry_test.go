@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(
+		[]*SourceTypeDao{{Name: "casino"}, {Name: "game"}},
+		[]*StateDao{{Name: "lost", Direction: DirectionDebit}, {Name: "refund", Direction: DirectionCredit}},
+	)
+
+	require.Equal(t, []string{"casino", "game"}, r.SourceTypes())
+	require.Equal(t, []string{"lost", "refund"}, r.States())
+
+	direction, ok := r.Direction("refund")
+	require.Equal(t, true, ok)
+	require.Equal(t, DirectionCredit, direction)
+
+	_, ok = r.Direction("win")
+	require.Equal(t, false, ok)
+
+	sv := r.Validator()
+
+	require.NoError(t, sv.Struct(&Transaction{
+		UserID:        "1",
+		TransactionID: "1",
+		SourceType:    "casino",
+		State:         "refund",
+		Amount:        1,
+		Currency:      "EUR",
+	}))
+
+	require.Error(t, sv.Struct(&Transaction{
+		UserID:        "1",
+		TransactionID: "1",
+		SourceType:    "server",
+		State:         "win",
+		Amount:        1,
+		Currency:      "EUR",
+	}))
+}
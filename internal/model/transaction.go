@@ -1,24 +1,107 @@
 package model
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Transaction struct {
-	TransactionID string  `json:"transactionId" validate:"required"`
-	State         string  `json:"state" validate:"required,oneof=win lost"`
-	Amount        float32 `json:"amount" validate:"required,gt=0"`
-	UserID        string  `validate:"required"`
-	SourceType    string  `validate:"required,oneof=game server payment"`
+	TransactionID string `json:"transactionId" validate:"required,transaction_id"`
+	State         string `json:"state" validate:"required,state"`
+	Amount        Money  `json:"amount" validate:"required,gt=0"`
+	Currency      string `json:"currency" validate:"required,iso4217"`
+	UserID        string `json:"userId" validate:"required"`
+	SourceType    string `json:"sourceType" validate:"required,source_type"`
 }
 
 // TransactionDao is the domain object for transactions table.
 type TransactionDao struct {
-	ID            string    `db:"id"`
-	UserID        string    `db:"user_id"`
-	TransactionID string    `db:"transaction_id"`
-	SourceType    string    `db:"source_type"`
-	State         string    `db:"state"`
-	Amount        float32   `db:"amount"`
-	CreatedAt     time.Time `db:"created_at"`
-	Cancelled     bool      `db:"cancelled"`
-	CancelledAt   time.Time `db:"cancelled_at"`
+	ID            string     `db:"id"`
+	Seq           int64      `db:"seq"`
+	UserID        string     `db:"user_id"`
+	TransactionID string     `db:"transaction_id"`
+	SourceType    string     `db:"source_type"`
+	State         string     `db:"state"`
+	Amount        Money      `db:"amount"`
+	Currency      string     `db:"currency"`
+	BonusAmount   Money      `db:"bonus_amount"`
+	CreatedAt     time.Time  `db:"created_at"`
+	Cancelled     bool       `db:"cancelled"`
+	CancelledAt   *time.Time `db:"cancelled_at"`
+	OperatorID    *string    `db:"operator_id"`
+	Reason        *string    `db:"reason"`
+}
+
+// TransactionFilter holds the filters and the page of the transaction history of a user.
+type TransactionFilter struct {
+	UserID     string `validate:"required"`
+	State      string `validate:"omitempty,state"`
+	SourceType string `validate:"omitempty,source_type"`
+	Cancelled  *bool
+	From       *time.Time
+	To         *time.Time
+	Cursor     int64 `validate:"gte=0"`
+	Limit      int   `validate:"min=1,max=100"`
+}
+
+// TransactionView is the representation of a stored transaction.
+type TransactionView struct {
+	ID            string     `json:"id"`
+	TransactionID string     `json:"transactionId"`
+	UserID        string     `json:"userId"`
+	SourceType    string     `json:"sourceType"`
+	State         string     `json:"state"`
+	Amount        Money      `json:"amount"`
+	Currency      string     `json:"currency"`
+	BonusAmount   Money      `json:"bonusAmount"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	Cancelled     bool       `json:"cancelled"`
+	CancelledAt   *time.Time `json:"cancelledAt"`
+	OperatorID    *string    `json:"operatorId,omitempty"`
+	Reason        *string    `json:"reason,omitempty"`
+}
+
+// TransactionPage is a page of the transaction history, NextCursor is empty on the last page.
+type TransactionPage struct {
+	Transactions []*TransactionView `json:"transactions"`
+	NextCursor   string             `json:"nextCursor,omitempty"`
+}
+
+// IsReplayOf reports whether the stored transaction has the same payload as the given request,
+// i.e. the request is a retry of an already processed transaction.
+func (t *TransactionDao) IsReplayOf(tr *Transaction) bool {
+	return t.UserID == tr.UserID &&
+		t.State == tr.State &&
+		t.Amount == tr.Amount &&
+		t.Currency == tr.Currency &&
+		t.SourceType == tr.SourceType
+}
+
+// LimitKind returns the kind of the limits the transaction counts towards, or an empty string.
+// direction is the one of the state of the transaction. A debiting transaction which does not come from payments
+// is a loss and a crediting payment transaction is a deposit.
+func (t *Transaction) LimitKind(direction string) string {
+	switch {
+	case direction == DirectionDebit && t.SourceType != SourceTypePayment:
+		return LimitKindLoss
+	case direction == DirectionCredit && t.SourceType == SourceTypePayment:
+		return LimitKindDeposit
+	default:
+		return ""
+	}
+}
+
+// ReservedTransactionIDPrefixes are the prefixes of the transactionIds the service derives for its own
+// transactions. A given transactionId must not start with one, so it never collides with a derived one.
+var ReservedTransactionIDPrefixes = []string{TransferTransactionIDPrefix, BetTransactionIDPrefix}
+
+// IsReservedTransactionID reports whether id starts with a prefix reserved for derived transactionIds.
+func IsReservedTransactionID(id string) bool {
+	for _, prefix := range ReservedTransactionIDPrefixes {
+		if strings.HasPrefix(id, prefix) {
+			return true
+		}
+	}
+
+	return false
 }
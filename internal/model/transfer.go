@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+const (
+	// StateTransferOut and StateTransferIn are the states of the debiting and the crediting leg of a transfer.
+	StateTransferOut = "transfer_out"
+	StateTransferIn  = "transfer_in"
+
+	// TransferTransactionIDPrefix starts the transactionIds of the legs of a transfer.
+	TransferTransactionIDPrefix = "transfer:"
+)
+
+// Transfer is the request to move real money from the wallet of a user to the wallet of another one
+// in the same currency. IdempotencyKey identifies the transfer, a retry with the same key is not moved again.
+type Transfer struct {
+	IdempotencyKey string `validate:"required,max=64"`
+	FromUserID     string `json:"fromUserId" validate:"required"`
+	ToUserID       string `json:"toUserId" validate:"required,nefield=FromUserID"`
+	Amount         Money  `json:"amount" validate:"required,gt=0"`
+	Currency       string `json:"currency" validate:"required,iso4217"`
+}
+
+// TransferDao is the domain object for transfers table.
+type TransferDao struct {
+	ID                  string    `db:"id"`
+	IdempotencyKey      string    `db:"idempotency_key"`
+	FromUserID          string    `db:"from_user_id"`
+	ToUserID            string    `db:"to_user_id"`
+	Amount              Money     `db:"amount"`
+	Currency            string    `db:"currency"`
+	DebitTransactionID  string    `db:"debit_transaction_id"`
+	CreditTransactionID string    `db:"credit_transaction_id"`
+	CreatedAt           time.Time `db:"created_at"`
+}
+
+// TransferView is the representation of a transfer.
+type TransferView struct {
+	ID                  string    `json:"id"`
+	IdempotencyKey      string    `json:"idempotencyKey"`
+	FromUserID          string    `json:"fromUserId"`
+	ToUserID            string    `json:"toUserId"`
+	Amount              Money     `json:"amount"`
+	Currency            string    `json:"currency"`
+	DebitTransactionID  string    `json:"debitTransactionId"`
+	CreditTransactionID string    `json:"creditTransactionId"`
+	CreatedAt           time.Time `json:"createdAt"`
+}
+
+// Transactions returns the debiting transaction of the sender and the crediting transaction of the receiver,
+// their transactionIds are derived from the idempotency key.
+func (t *Transfer) Transactions() (*Transaction, *Transaction) {
+	debit := &Transaction{
+		TransactionID: TransferTransactionIDPrefix + t.IdempotencyKey + ":out",
+		State:         StateTransferOut,
+		Amount:        t.Amount,
+		Currency:      t.Currency,
+		UserID:        t.FromUserID,
+		SourceType:    SourceTypeTransfer,
+	}
+
+	credit := *debit
+	credit.TransactionID = TransferTransactionIDPrefix + t.IdempotencyKey + ":in"
+	credit.State = StateTransferIn
+	credit.UserID = t.ToUserID
+
+	return debit, &credit
+}
+
+// IsReplayOf reports whether the stored transfer has the same payload as the given request,
+// i.e. the request is a retry of an already made transfer.
+func (t *TransferDao) IsReplayOf(req *Transfer) bool {
+	return t.FromUserID == req.FromUserID &&
+		t.ToUserID == req.ToUserID &&
+		t.Amount == req.Amount &&
+		t.Currency == req.Currency
+}
+
+// IsTransfer reports whether the transaction is a leg of a transfer, which only the transfer flow makes.
+func (t *Transaction) IsTransfer() bool {
+	return t.SourceType == SourceTypeTransfer || t.State == StateTransferOut || t.State == StateTransferIn
+}
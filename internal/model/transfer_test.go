@@ -0,0 +1,61 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTransactions(t *testing.T) {
+	tf := &Transfer{IdempotencyKey: "t-1", FromUserID: "1", ToUserID: "2", Amount: 100, Currency: "EUR"}
+
+	debit, credit := tf.Transactions()
+
+	require.Equal(t, &Transaction{
+		TransactionID: "transfer:t-1:out",
+		State:         StateTransferOut,
+		Amount:        100,
+		Currency:      "EUR",
+		UserID:        "1",
+		SourceType:    SourceTypeTransfer,
+	}, debit)
+
+	require.Equal(t, &Transaction{
+		TransactionID: "transfer:t-1:in",
+		State:         StateTransferIn,
+		Amount:        100,
+		Currency:      "EUR",
+		UserID:        "2",
+		SourceType:    SourceTypeTransfer,
+	}, credit)
+
+	require.Equal(t, true, debit.IsTransfer())
+	require.Equal(t, false, (&Transaction{SourceType: SourceTypeGame, State: StateWin}).IsTransfer())
+	require.Equal(t, true, (&Transaction{SourceType: SourceTypeGame, State: StateTransferIn}).IsTransfer())
+}
+
+func TestTransferIsReplayOf(t *testing.T) {
+	tf := &Transfer{IdempotencyKey: "t-1", FromUserID: "1", ToUserID: "2", Amount: 100, Currency: "EUR"}
+	stored := &TransferDao{ID: "9", IdempotencyKey: "t-1", FromUserID: "1", ToUserID: "2", Amount: 100, Currency: "EUR"}
+
+	require.Equal(t, true, stored.IsReplayOf(tf))
+
+	tf.ToUserID = "3"
+	require.Equal(t, false, stored.IsReplayOf(tf))
+}
+
+func TestTransferTransactionIDsReserved(t *testing.T) {
+	tf := &Transfer{IdempotencyKey: "t-1", FromUserID: "1", ToUserID: "2", Amount: 100, Currency: "EUR"}
+	debit, credit := tf.Transactions()
+
+	require.Equal(t, true, IsReservedTransactionID(debit.TransactionID))
+	require.Equal(t, true, IsReservedTransactionID(credit.TransactionID))
+	require.Equal(t, false, IsReservedTransactionID("t-1"))
+	require.Equal(t, false, IsReservedTransactionID("my-transfer:1"))
+
+	// A given transactionId cannot take the transactionId of a transfer leg.
+	sv := NewValidator()
+
+	require.Error(t, sv.Var(credit.TransactionID, "transaction_id"))
+	require.NoError(t, sv.Var("t-1", "transaction_id"))
+}
@@ -1,7 +1,46 @@
 package model
 
+import "time"
+
+const (
+	// UserStatusActive is the status of a user who can make transactions.
+	UserStatusActive = "active"
+	// UserStatusSuspended is the status of a user whose transactions are temporarily refused.
+	UserStatusSuspended = "suspended"
+	// UserStatusClosed is the final status of a user whose wallet is closed.
+	UserStatusClosed = "closed"
+)
+
 // UserDao is the domain object for users table.
 type UserDao struct {
-	ID      string  `db:"id"`
-	Balance float32 `db:"balance"`
+	ID        string    `db:"id"`
+	Status    string    `db:"status"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// User is the representation of a user.
+type User struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Balance is the current balance of a user in every currency.
+type Balance struct {
+	UserID  string    `json:"userId"`
+	Wallets []*Wallet `json:"wallets"`
+}
+
+// StatusError returns the error which refuses transactions of the user, or nil for an active user.
+func (u *UserDao) StatusError() error {
+	switch u.Status {
+	case UserStatusSuspended:
+		return ErrorUserSuspended
+	case UserStatusClosed:
+		return ErrorUserClosed
+	default:
+		return nil
+	}
 }
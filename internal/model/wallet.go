@@ -0,0 +1,219 @@
+package model
+
+import "time"
+
+// WalletDao is the domain object for wallets table, a user holds one wallet per currency.
+// Besides real money a wallet holds bonus funds, which turn into real money
+// once the amount staked since the bonus was granted reaches WageringRequired.
+// Reserved is the part of the real balance held by pending withdrawals, it cannot be spent.
+type WalletDao struct {
+	UserID           string    `db:"user_id"`
+	Currency         string    `db:"currency"`
+	Balance          Money     `db:"balance"`
+	Reserved         Money     `db:"reserved"`
+	BonusBalance     Money     `db:"bonus_balance"`
+	WageringRequired Money     `db:"wagering_required"`
+	Wagered          Money     `db:"wagered"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+// WalletMovement is a change of a wallet, every field is a signed delta of the matching wallet field.
+type WalletMovement struct {
+	Real             Money
+	Reserved         Money
+	Bonus            Money
+	WageringRequired Money
+	Wagered          Money
+}
+
+// Wallet is the representation of a wallet.
+type Wallet struct {
+	Currency         string    `json:"currency"`
+	Balance          string    `json:"balance"`
+	Reserved         string    `json:"reserved"`
+	BonusBalance     string    `json:"bonusBalance"`
+	WageringRequired string    `json:"wageringRequired"`
+	Wagered          string    `json:"wagered"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// OpenWallet is the request to open a wallet in a new currency.
+type OpenWallet struct {
+	Currency string `json:"currency" validate:"required,iso4217"`
+}
+
+// Bonus is the request to grant bonus funds which must be wagered before they turn into real money.
+type Bonus struct {
+	UserID   string `validate:"required"`
+	Currency string `json:"currency" validate:"required,iso4217"`
+	Amount   Money  `json:"amount" validate:"required,gt=0"`
+	Wagering Money  `json:"wagering" validate:"required,gt=0"`
+}
+
+// Available returns the real money which can be spent or withdrawn.
+func (w *WalletDao) Available() Money {
+	return w.Balance - w.Reserved
+}
+
+// IsWagering reports whether the wallet has an outstanding wagering requirement.
+func (w *WalletDao) IsWagering() bool {
+	return w.WageringRequired > 0
+}
+
+// Settle returns the movement of a transaction whose state has the given direction.
+// A loss consumes available real money first and the bonus covers the rest, while wagering is outstanding
+// the whole lost amount counts towards it. A win is credited to the bonus while wagering is outstanding
+// and to real money otherwise.
+func (w *WalletDao) Settle(direction string, amount Money) (*WalletMovement, error) {
+	switch direction {
+	case DirectionCredit:
+		if w.IsWagering() {
+			return &WalletMovement{Bonus: amount}, nil
+		}
+
+		return &WalletMovement{Real: amount}, nil
+	case DirectionDebit:
+		if w.Available()+w.BonusBalance < amount {
+			return nil, ErrorInsufficientBalance
+		}
+
+		fromReal := min(amount, w.Available())
+		m := &WalletMovement{Real: -fromReal, Bonus: -(amount - fromReal)}
+
+		if w.IsWagering() {
+			m.Wagered = amount
+		}
+
+		return m, nil
+	default:
+		return &WalletMovement{}, nil
+	}
+}
+
+// Reverse returns the movement which reverses a transaction whose state has the given direction,
+// bonusAmount is the part of the amount which was settled against the bonus.
+// A refunded loss goes back to the buckets it came from, unless wagering is over, then it is real money.
+// A reversed bonus win is taken from the bonus and, if that has been converted meanwhile, from real money.
+func (w *WalletDao) Reverse(direction string, amount, bonusAmount Money) (*WalletMovement, error) {
+	m := &WalletMovement{}
+
+	switch direction {
+	case DirectionCredit:
+		bonus := min(bonusAmount, w.BonusBalance)
+		m.Bonus = -bonus
+		m.Real = -(amount - bonus)
+	case DirectionDebit:
+		if !w.IsWagering() {
+			m.Real = amount
+
+			break
+		}
+
+		m.Real = amount - bonusAmount
+		m.Bonus = bonusAmount
+		m.Wagered = -min(amount, w.Wagered)
+	}
+
+	if w.Available()+m.Real < 0 || w.BonusBalance+m.Bonus < 0 {
+		return nil, ErrorCancellationInsufficientBalance
+	}
+
+	return m, nil
+}
+
+// Reserve returns the movement which holds the amount of a pending withdrawal, only real money can be withdrawn.
+func (w *WalletDao) Reserve(amount Money) (*WalletMovement, error) {
+	if w.Available() < amount {
+		return nil, ErrorInsufficientBalance
+	}
+
+	return &WalletMovement{Reserved: amount}, nil
+}
+
+// CompletePayment returns the movement of a pending payment which moves to the given state.
+// A confirmed deposit is real money even while wagering is outstanding, a confirmed withdrawal
+// takes its reserved amount from the balance and a failed one releases it.
+func (w *WalletDao) CompletePayment(state string, amount Money) *WalletMovement {
+	switch state {
+	case StateDepositConfirmed:
+		return &WalletMovement{Real: amount}
+	case StateWithdrawalConfirmed:
+		return &WalletMovement{Real: -amount, Reserved: -amount}
+	case StateWithdrawalFailed:
+		return &WalletMovement{Reserved: -amount}
+	default:
+		return &WalletMovement{}
+	}
+}
+
+// MoveReal returns the movement of a manual adjustment or of a transfer leg in the given state,
+// they move real money only.
+func (w *WalletDao) MoveReal(state string, amount Money) (*WalletMovement, error) {
+	switch state {
+	case StateAdjustmentCredit, StateTransferIn:
+		return &WalletMovement{Real: amount}, nil
+	case StateAdjustmentDebit, StateTransferOut:
+		if w.Available() < amount {
+			return nil, ErrorInsufficientBalance
+		}
+
+		return &WalletMovement{Real: -amount}, nil
+	default:
+		return &WalletMovement{}, nil
+	}
+}
+
+// ReversePayment returns the movement which reverses a payment or an adjustment in the given state.
+// A cancelled pending withdrawal will never be confirmed, so its reservation is released.
+func (w *WalletDao) ReversePayment(state string, amount Money) (*WalletMovement, error) {
+	switch state {
+	case StateDepositConfirmed, StateAdjustmentCredit:
+		if w.Available() < amount {
+			return nil, ErrorCancellationInsufficientBalance
+		}
+
+		return &WalletMovement{Real: -amount}, nil
+	case StateWithdrawalConfirmed, StateAdjustmentDebit:
+		return &WalletMovement{Real: amount}, nil
+	case StateWithdrawalPending:
+		return &WalletMovement{Reserved: -amount}, nil
+	default:
+		return &WalletMovement{}, nil
+	}
+}
+
+// Release returns the movement which ends the wagering of the wallet, or nil while it goes on.
+// A met requirement converts the whole bonus to real money, a used up bonus just drops the requirement.
+func (w *WalletDao) Release() *WalletMovement {
+	if !w.IsWagering() {
+		return nil
+	}
+
+	if w.Wagered >= w.WageringRequired {
+		return &WalletMovement{
+			Real:             w.BonusBalance,
+			Bonus:            -w.BonusBalance,
+			WageringRequired: -w.WageringRequired,
+			Wagered:          -w.Wagered,
+		}
+	}
+
+	if w.BonusBalance == 0 {
+		return &WalletMovement{
+			WageringRequired: -w.WageringRequired,
+			Wagered:          -w.Wagered,
+		}
+	}
+
+	return nil
+}
+
+// BonusPart returns the part of the movement settled against the bonus, without sign.
+func (m *WalletMovement) BonusPart() Money {
+	if m.Bonus < 0 {
+		return -m.Bonus
+	}
+
+	return m.Bonus
+}
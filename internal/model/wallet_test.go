@@ -0,0 +1,224 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettle(t *testing.T) {
+	testCases := []struct {
+		name      string
+		wallet    *WalletDao
+		direction string
+		amount    Money
+		movement  *WalletMovement
+		err       error
+	}{
+		{
+			name:      "Win",
+			wallet:    &WalletDao{Balance: 100},
+			direction: DirectionCredit,
+			amount:    50,
+			movement:  &WalletMovement{Real: 50},
+		},
+		{
+			name:      "Win while wagering",
+			wallet:    &WalletDao{Balance: 100, BonusBalance: 100, WageringRequired: 500},
+			direction: DirectionCredit,
+			amount:    50,
+			movement:  &WalletMovement{Bonus: 50},
+		},
+		{
+			name:      "Loss",
+			wallet:    &WalletDao{Balance: 100},
+			direction: DirectionDebit,
+			amount:    50,
+			movement:  &WalletMovement{Real: -50},
+		},
+		{
+			name:      "Loss covered by the bonus",
+			wallet:    &WalletDao{Balance: 30, BonusBalance: 100, WageringRequired: 500},
+			direction: DirectionDebit,
+			amount:    50,
+			movement:  &WalletMovement{Real: -30, Bonus: -20, Wagered: 50},
+		},
+		{
+			name:      "Loss with reserved funds",
+			wallet:    &WalletDao{Balance: 100, Reserved: 80},
+			direction: DirectionDebit,
+			amount:    50,
+			err:       ErrorInsufficientBalance,
+		},
+		{
+			name:      "No balance change",
+			wallet:    &WalletDao{Balance: 100},
+			direction: DirectionNone,
+			amount:    50,
+			movement:  &WalletMovement{},
+		},
+		{
+			name:      "Insufficient balance",
+			wallet:    &WalletDao{Balance: 30, BonusBalance: 10, WageringRequired: 500},
+			direction: DirectionDebit,
+			amount:    50,
+			err:       ErrorInsufficientBalance,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := tc.wallet.Settle(tc.direction, tc.amount)
+			require.Equal(t, true, errors.Is(err, tc.err))
+			require.Equal(t, tc.movement, m)
+		})
+	}
+}
+
+func TestReverse(t *testing.T) {
+	testCases := []struct {
+		name        string
+		wallet      *WalletDao
+		direction   string
+		amount      Money
+		bonusAmount Money
+		movement    *WalletMovement
+		err         error
+	}{
+		{
+			name:      "Win",
+			wallet:    &WalletDao{Balance: 100},
+			direction: DirectionCredit,
+			amount:    50,
+			movement:  &WalletMovement{Real: -50},
+		},
+		{
+			name:        "Bonus win converted meanwhile",
+			wallet:      &WalletDao{Balance: 100, BonusBalance: 20},
+			direction:   DirectionCredit,
+			amount:      50,
+			bonusAmount: 50,
+			movement:    &WalletMovement{Real: -30, Bonus: -20},
+		},
+		{
+			name:      "Loss",
+			wallet:    &WalletDao{},
+			direction: DirectionDebit,
+			amount:    50,
+			movement:  &WalletMovement{Real: 50},
+		},
+		{
+			name:        "Loss while wagering",
+			wallet:      &WalletDao{BonusBalance: 10, WageringRequired: 500, Wagered: 80},
+			direction:   DirectionDebit,
+			amount:      50,
+			bonusAmount: 20,
+			movement:    &WalletMovement{Real: 30, Bonus: 20, Wagered: -50},
+		},
+		{
+			name:      "Won amount already spent",
+			wallet:    &WalletDao{Balance: 10},
+			direction: DirectionCredit,
+			amount:    50,
+			err:       ErrorCancellationInsufficientBalance,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := tc.wallet.Reverse(tc.direction, tc.amount, tc.bonusAmount)
+			require.Equal(t, true, errors.Is(err, tc.err))
+			require.Equal(t, tc.movement, m)
+		})
+	}
+}
+
+func TestReserve(t *testing.T) {
+	m, err := (&WalletDao{Balance: 100, Reserved: 30, BonusBalance: 100}).Reserve(70)
+	require.NoError(t, err)
+	require.Equal(t, &WalletMovement{Reserved: 70}, m)
+
+	// Bonus funds cannot be withdrawn.
+	_, err = (&WalletDao{Balance: 100, Reserved: 30, BonusBalance: 100}).Reserve(71)
+	require.Equal(t, true, errors.Is(err, ErrorInsufficientBalance))
+}
+
+func TestCompletePayment(t *testing.T) {
+	w := &WalletDao{Balance: 100, Reserved: 50, BonusBalance: 10, WageringRequired: 100}
+
+	require.Equal(t, &WalletMovement{Real: 50}, w.CompletePayment(StateDepositConfirmed, 50))
+	require.Equal(t, &WalletMovement{}, w.CompletePayment(StateDepositFailed, 50))
+	require.Equal(t, &WalletMovement{Real: -50, Reserved: -50}, w.CompletePayment(StateWithdrawalConfirmed, 50))
+	require.Equal(t, &WalletMovement{Reserved: -50}, w.CompletePayment(StateWithdrawalFailed, 50))
+}
+
+func TestMoveReal(t *testing.T) {
+	w := &WalletDao{Balance: 100, Reserved: 60, BonusBalance: 100, WageringRequired: 500}
+
+	// Credits are real money even while wagering is outstanding.
+	m, err := w.MoveReal(StateAdjustmentCredit, 50)
+	require.NoError(t, err)
+	require.Equal(t, &WalletMovement{Real: 50}, m)
+
+	m, err = w.MoveReal(StateAdjustmentDebit, 40)
+	require.NoError(t, err)
+	require.Equal(t, &WalletMovement{Real: -40}, m)
+
+	// Neither reserved funds nor the bonus can be debited.
+	_, err = w.MoveReal(StateAdjustmentDebit, 41)
+	require.Equal(t, true, errors.Is(err, ErrorInsufficientBalance))
+
+	m, err = w.MoveReal(StateTransferIn, 50)
+	require.NoError(t, err)
+	require.Equal(t, &WalletMovement{Real: 50}, m)
+
+	_, err = w.MoveReal(StateTransferOut, 41)
+	require.Equal(t, true, errors.Is(err, ErrorInsufficientBalance))
+}
+
+func TestReversePayment(t *testing.T) {
+	w := &WalletDao{Balance: 100, Reserved: 60, WageringRequired: 100, Wagered: 10}
+
+	m, err := w.ReversePayment(StateWithdrawalConfirmed, 50)
+	require.NoError(t, err)
+	require.Equal(t, &WalletMovement{Real: 50}, m)
+
+	m, err = w.ReversePayment(StateWithdrawalPending, 50)
+	require.NoError(t, err)
+	require.Equal(t, &WalletMovement{Reserved: -50}, m)
+
+	m, err = w.ReversePayment(StateDepositConfirmed, 40)
+	require.NoError(t, err)
+	require.Equal(t, &WalletMovement{Real: -40}, m)
+
+	// Reserved funds cannot pay for a cancelled deposit.
+	_, err = w.ReversePayment(StateDepositConfirmed, 50)
+	require.Equal(t, true, errors.Is(err, ErrorCancellationInsufficientBalance))
+
+	m, err = w.ReversePayment(StateAdjustmentDebit, 50)
+	require.NoError(t, err)
+	require.Equal(t, &WalletMovement{Real: 50}, m)
+
+	_, err = w.ReversePayment(StateAdjustmentCredit, 50)
+	require.Equal(t, true, errors.Is(err, ErrorCancellationInsufficientBalance))
+}
+
+func TestRelease(t *testing.T) {
+	require.Nil(t, (&WalletDao{Balance: 100}).Release())
+	require.Nil(t, (&WalletDao{BonusBalance: 100, WageringRequired: 500, Wagered: 499}).Release())
+
+	require.Equal(t, &WalletMovement{
+		Real:             100,
+		Bonus:            -100,
+		WageringRequired: -500,
+		Wagered:          -520,
+	}, (&WalletDao{BonusBalance: 100, WageringRequired: 500, Wagered: 520}).Release())
+
+	require.Equal(t, &WalletMovement{
+		WageringRequired: -500,
+		Wagered:          -80,
+	}, (&WalletDao{WageringRequired: 500, Wagered: 80}).Release())
+}
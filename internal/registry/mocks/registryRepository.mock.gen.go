@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/ttagiyeva/entain/internal/model"
+)
+
+// MockRegistryRepository is a mock of Repository interface.
+type MockRegistryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistryRepositoryMockRecorder
+}
+
+// MockRegistryRepositoryMockRecorder is the mock recorder for MockRegistryRepository.
+type MockRegistryRepositoryMockRecorder struct {
+	mock *MockRegistryRepository
+}
+
+// NewMockRegistryRepository creates a new mock instance.
+func NewMockRegistryRepository(ctrl *gomock.Controller) *MockRegistryRepository {
+	mock := &MockRegistryRepository{ctrl: ctrl}
+	mock.recorder = &MockRegistryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistryRepository) EXPECT() *MockRegistryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetSourceTypes mocks base method.
+func (m *MockRegistryRepository) GetSourceTypes(ctx context.Context) ([]*model.SourceTypeDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSourceTypes", ctx)
+	ret0, _ := ret[0].([]*model.SourceTypeDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSourceTypes indicates an expected call of GetSourceTypes.
+func (mr *MockRegistryRepositoryMockRecorder) GetSourceTypes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceTypes", reflect.TypeOf((*MockRegistryRepository)(nil).GetSourceTypes), ctx)
+}
+
+// GetStates mocks base method.
+func (m *MockRegistryRepository) GetStates(ctx context.Context) ([]*model.StateDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStates", ctx)
+	ret0, _ := ret[0].([]*model.StateDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStates indicates an expected call of GetStates.
+func (mr *MockRegistryRepositoryMockRecorder) GetStates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStates", reflect.TypeOf((*MockRegistryRepository)(nil).GetStates), ctx)
+}
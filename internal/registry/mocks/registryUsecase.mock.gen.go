@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/ttagiyeva/entain/internal/model"
+)
+
+// MockRegistryUsecase is a mock of Usecase interface.
+type MockRegistryUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistryUsecaseMockRecorder
+}
+
+// MockRegistryUsecaseMockRecorder is the mock recorder for MockRegistryUsecase.
+type MockRegistryUsecaseMockRecorder struct {
+	mock *MockRegistryUsecase
+}
+
+// NewMockRegistryUsecase creates a new mock instance.
+func NewMockRegistryUsecase(ctrl *gomock.Controller) *MockRegistryUsecase {
+	mock := &MockRegistryUsecase{ctrl: ctrl}
+	mock.recorder = &MockRegistryUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistryUsecase) EXPECT() *MockRegistryUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockRegistryUsecase) Get(ctx context.Context) (*model.Registry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx)
+	ret0, _ := ret[0].(*model.Registry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRegistryUsecaseMockRecorder) Get(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRegistryUsecase)(nil).Get), ctx)
+}
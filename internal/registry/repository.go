@@ -0,0 +1,15 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+//go:generate mockgen -source ./repository.go -mock_names Repository=MockRegistryRepository -package mocks -destination mocks/registryRepository.mock.gen.go
+
+// Repository is a repository for the registered source types and states of transactions.
+type Repository interface {
+	GetSourceTypes(ctx context.Context) ([]*model.SourceTypeDao, error)
+	GetStates(ctx context.Context) ([]*model.StateDao, error)
+}
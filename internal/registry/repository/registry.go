@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Registry is the repository for the registered source types and states.
+type Registry struct {
+	conn *sqlx.DB
+}
+
+// New returns a new Registry object.
+func New(conn *sqlx.DB) *Registry {
+	return &Registry{
+		conn: conn,
+	}
+}
+
+// GetSourceTypes returns the registered source types ordered by name.
+func (r *Registry) GetSourceTypes(ctx context.Context) ([]*model.SourceTypeDao, error) {
+	query := `
+		SELECT
+			name,
+			created_at
+		FROM source_types
+		ORDER BY name;
+	`
+	rows, err := r.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get source types query: %w", err)
+	}
+
+	defer rows.Close()
+
+	sourceTypes := []*model.SourceTypeDao{}
+
+	for rows.Next() {
+		sourceType := &model.SourceTypeDao{}
+		err = rows.Scan(&sourceType.Name, &sourceType.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan source type row: %w", err)
+		}
+
+		sourceTypes = append(sourceTypes, sourceType)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate source type rows: %w", err)
+	}
+
+	return sourceTypes, nil
+}
+
+// GetStates returns the registered states ordered by name.
+func (r *Registry) GetStates(ctx context.Context) ([]*model.StateDao, error) {
+	query := `
+		SELECT
+			name,
+			direction,
+			created_at
+		FROM states
+		ORDER BY name;
+	`
+	rows, err := r.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get states query: %w", err)
+	}
+
+	defer rows.Close()
+
+	states := []*model.StateDao{}
+
+	for rows.Next() {
+		state := &model.StateDao{}
+		err = rows.Scan(&state.Name, &state.Direction, &state.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan state row: %w", err)
+		}
+
+		states = append(states, state)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate state rows: %w", err)
+	}
+
+	return states, nil
+}
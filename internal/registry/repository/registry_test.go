@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/ttagiyeva/entain/internal/database"
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/registry/repository"
+	"github.com/ttagiyeva/entain/internal/util"
+)
+
+type registryRepoTestSuite struct {
+	suite.Suite
+	testcontainers.Container
+	db   *database.Postgres
+	repo *repository.Registry
+	ctx  context.Context
+}
+
+func TestRegistryRepoTestSuite(t *testing.T) {
+	suite.Run(t, &registryRepoTestSuite{})
+}
+
+func (r *registryRepoTestSuite) SetupSuite() {
+	r.ctx = context.Background()
+	r.db = util.CreateTestContainer(r.ctx, &r.Suite)
+	r.repo = repository.New(r.db.Connection)
+}
+
+func (r *registryRepoTestSuite) SetupTest() {
+	if err := r.db.MigrateUp(); err != nil || errors.Is(err, migrate.ErrNoChange) {
+		r.Require().NoError(err)
+	}
+}
+
+func (r *registryRepoTestSuite) TearDownTest() {
+	r.NoError(r.db.MigrateDown())
+}
+
+func (r *registryRepoTestSuite) TestGetSourceTypes() {
+	sourceTypes, err := r.repo.GetSourceTypes(r.ctx)
+	r.NoError(err)
+	r.Len(sourceTypes, 5)
+	r.Equal("adjustment", sourceTypes[0].Name)
+	r.Equal("game", sourceTypes[1].Name)
+	r.Equal("payment", sourceTypes[2].Name)
+	r.Equal("server", sourceTypes[3].Name)
+	r.Equal("transfer", sourceTypes[4].Name)
+}
+
+func (r *registryRepoTestSuite) TestGetStates() {
+	_, err := r.db.Connection.ExecContext(r.ctx, `INSERT INTO states (name, direction) VALUES ('refund', 'credit')`)
+	r.Require().NoError(err)
+
+	states, err := r.repo.GetStates(r.ctx)
+	r.NoError(err)
+
+	directions := map[string]string{}
+	for i, state := range states {
+		if i > 0 {
+			r.Less(states[i-1].Name, state.Name)
+		}
+
+		directions[state.Name] = state.Direction
+	}
+
+	r.Equal(model.DirectionDebit, directions["lost"])
+	r.Equal(model.DirectionCredit, directions["refund"])
+	r.Equal(model.DirectionCredit, directions["win"])
+
+	// A state must move the balance in a known direction.
+	_, err = r.db.Connection.ExecContext(r.ctx, `INSERT INTO states (name, direction) VALUES ('odd', 'sideways')`)
+	r.Error(err)
+}
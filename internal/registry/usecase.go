@@ -0,0 +1,14 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Usecase is a usecase interface for the registry of source types and states.
+//
+//go:generate mockgen -source ./usecase.go -mock_names Usecase=MockRegistryUsecase -package mocks -destination mocks/registryUsecase.mock.gen.go
+type Usecase interface {
+	Get(ctx context.Context) (*model.Registry, error)
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ttagiyeva/entain/internal/config"
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/registry"
+)
+
+// defaultTTL is used when the configured registry ttl is not valid.
+const defaultTTL = time.Minute
+
+// Registry is a structure which caches the registry of source types and states.
+type Registry struct {
+	log          *slog.Logger
+	ttl          time.Duration
+	registryRepo registry.Repository
+
+	mu       sync.Mutex
+	cached   *model.Registry
+	loadedAt time.Time
+}
+
+// New creates a new registry usecase.
+func New(log *slog.Logger, conf *config.Config, r registry.Repository) *Registry {
+	ttl := conf.Registry.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	return &Registry{
+		log:          log,
+		ttl:          ttl,
+		registryRepo: r,
+	}
+}
+
+// Get returns the registry, it is loaded from the database at most once per configured ttl.
+// If reloading fails, the previous registry is returned until the database is back.
+func (r *Registry) Get(ctx context.Context) (*model.Registry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cached != nil && time.Since(r.loadedAt) < r.ttl {
+		return r.cached, nil
+	}
+
+	reg, err := r.load(ctx)
+	if err != nil {
+		if r.cached == nil {
+			return nil, err
+		}
+
+		r.log.Error("failed to reload the registry, the previous one is used", "error", err)
+
+		return r.cached, nil
+	}
+
+	r.cached = reg
+	r.loadedAt = time.Now()
+
+	return reg, nil
+}
+
+func (r *Registry) load(ctx context.Context) (*model.Registry, error) {
+	sourceTypes, err := r.registryRepo.GetSourceTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source types: %w", err)
+	}
+
+	states, err := r.registryRepo.GetStates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get states: %w", err)
+	}
+
+	return model.NewRegistry(sourceTypes, states), nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ttagiyeva/entain/internal/config"
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/registry/mocks"
+)
+
+func TestGet(t *testing.T) {
+	sourceTypes := []*model.SourceTypeDao{{Name: "game"}}
+	states := []*model.StateDao{{Name: "win", Direction: model.DirectionCredit}}
+	dummyErr := errors.New("dummy error")
+
+	testCases := []struct {
+		name          string
+		ttl           time.Duration
+		buildStubs    func(registryRepo *mocks.MockRegistryRepository)
+		checkResponse func(r *Registry)
+	}{
+		{
+			name: "Cached within the ttl",
+			ttl:  time.Hour,
+			buildStubs: func(registryRepo *mocks.MockRegistryRepository) {
+				registryRepo.EXPECT().GetSourceTypes(gomock.Any()).Return(sourceTypes, nil).Times(1)
+				registryRepo.EXPECT().GetStates(gomock.Any()).Return(states, nil).Times(1)
+			},
+			checkResponse: func(r *Registry) {
+				for i := 0; i < 3; i++ {
+					reg, err := r.Get(context.Background())
+					require.NoError(t, err)
+					require.Equal(t, []string{"win"}, reg.States())
+				}
+			},
+		},
+		{
+			name: "Reloaded after the ttl",
+			ttl:  time.Nanosecond,
+			buildStubs: func(registryRepo *mocks.MockRegistryRepository) {
+				registryRepo.EXPECT().GetSourceTypes(gomock.Any()).Return(sourceTypes, nil).Times(2)
+				registryRepo.EXPECT().GetStates(gomock.Any()).Return(states, nil)
+				registryRepo.EXPECT().GetStates(gomock.Any()).Return(append(states, &model.StateDao{Name: "void", Direction: model.DirectionNone}), nil)
+			},
+			checkResponse: func(r *Registry) {
+				reg, err := r.Get(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, []string{"win"}, reg.States())
+
+				time.Sleep(time.Millisecond)
+
+				reg, err = r.Get(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, []string{"win", "void"}, reg.States())
+			},
+		},
+		{
+			name: "Stale registry when reloading fails",
+			ttl:  time.Nanosecond,
+			buildStubs: func(registryRepo *mocks.MockRegistryRepository) {
+				registryRepo.EXPECT().GetSourceTypes(gomock.Any()).Return(sourceTypes, nil)
+				registryRepo.EXPECT().GetStates(gomock.Any()).Return(states, nil)
+				registryRepo.EXPECT().GetSourceTypes(gomock.Any()).Return(nil, dummyErr)
+			},
+			checkResponse: func(r *Registry) {
+				_, err := r.Get(context.Background())
+				require.NoError(t, err)
+
+				time.Sleep(time.Millisecond)
+
+				reg, err := r.Get(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, []string{"win"}, reg.States())
+			},
+		},
+		{
+			name: "Loading fails",
+			ttl:  time.Hour,
+			buildStubs: func(registryRepo *mocks.MockRegistryRepository) {
+				registryRepo.EXPECT().GetSourceTypes(gomock.Any()).Return(sourceTypes, nil)
+				registryRepo.EXPECT().GetStates(gomock.Any()).Return(nil, dummyErr)
+			},
+			checkResponse: func(r *Registry) {
+				_, err := r.Get(context.Background())
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			registryRepo := mocks.NewMockRegistryRepository(ctrl)
+			tc.buildStubs(registryRepo)
+
+			tc.checkResponse(New(slog.Default(), &config.Config{Registry: config.Registry{TTL: tc.ttl}}, registryRepo))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// adminAuth lets through the requests which carry the admin token as a bearer token.
+// Every request is refused while no token is configured.
+func adminAuth(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			given, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+
+			if !ok || token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				return c.JSON(http.StatusUnauthorized, model.Error{
+					Code:    http.StatusUnauthorized,
+					Message: model.ErrorUnauthorized.Error(),
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminAuth(t *testing.T) {
+	testCases := []struct {
+		name          string
+		token         string
+		authorization string
+		expectedCode  int
+	}{
+		{name: "OK", token: "secret", authorization: "Bearer secret", expectedCode: http.StatusNoContent},
+		{name: "Wrong token", token: "secret", authorization: "Bearer guess", expectedCode: http.StatusUnauthorized},
+		{name: "Missing token", token: "secret", expectedCode: http.StatusUnauthorized},
+		{name: "Not a bearer token", token: "secret", authorization: "secret", expectedCode: http.StatusUnauthorized},
+		{name: "No token configured", authorization: "Bearer ", expectedCode: http.StatusUnauthorized},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/1/adjustments", nil)
+			req.Header.Set(echo.HeaderAuthorization, tc.authorization)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := adminAuth(tc.token)(func(c echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			})(c)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
@@ -5,27 +5,89 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/ttagiyeva/entain/internal/config"
 	"github.com/ttagiyeva/entain/internal/database"
+	exclusionHttp "github.com/ttagiyeva/entain/internal/exclusion/delivery/http"
+	limitHttp "github.com/ttagiyeva/entain/internal/limit/delivery/http"
+	"github.com/ttagiyeva/entain/internal/model"
 	"github.com/ttagiyeva/entain/internal/transaction/delivery/http"
+	userHttp "github.com/ttagiyeva/entain/internal/user/delivery/http"
 )
 
 // RegisterRouters registers all routers for the service.
-func RegisterRouters(e *echo.Echo, h *http.Handler, db *database.Postgres) error {
-	e.GET("/health", healthCheck(db))
+func RegisterRouters(
+	e *echo.Echo,
+	conf *config.Config,
+	h *http.Handler,
+	uh *userHttp.Handler,
+	lh *limitHttp.Handler,
+	xh *exclusionHttp.Handler,
+	db *database.Postgres,
+) error {
+	e.GET("/health", healthCheck(db, conf.Migrate.Mode))
 
 	grp := e.Group("api/v1")
 	grp.POST("/users/:id/transactions", h.Process)
+	grp.POST("/transactions\\:batch", h.ProcessBatch)
+	grp.GET("/users/:id/transactions", h.History)
+	grp.GET("/users/:id/balance", uh.GetBalance)
+	grp.POST("/users/:id/deposits", h.Deposit)
+	grp.POST("/users/:id/withdrawals", h.Withdraw)
+	grp.POST("/users/:id/bets", h.PlaceBet)
+	grp.POST("/bets/:id/settle", h.SettleBet)
+	grp.POST("/bets/:id/void", h.VoidBet)
+	grp.POST("/transfers", h.Transfer)
+	grp.POST("/users/:id/wallets", uh.OpenWallet)
+	grp.POST("/users", uh.CreateUser)
+	grp.GET("/users/:id", uh.GetUser)
+	grp.GET("/users/:id/limits", lh.GetLimits)
+	grp.GET("/users/:id/exclusions", xh.GetExclusions)
+	grp.POST("/users/:id/exclusions", xh.StartExclusion)
+
+	admin := grp.Group("/admin", adminAuth(conf.Admin.Token))
+	admin.POST("/users/:id/adjustments", h.Adjust)
+	admin.POST("/users/:id/bonuses", h.GrantBonus)
+	admin.POST("/payments/:id/confirm", h.ConfirmPayment)
+	admin.POST("/payments/:id/fail", h.FailPayment)
+	admin.PUT("/users/:id/limits", lh.SetLimit)
+	admin.POST("/users/:id/suspend", uh.Suspend)
+	admin.POST("/users/:id/activate", uh.Activate)
+	admin.POST("/users/:id/close", uh.Close)
+	admin.POST("/transactions\\:import", h.Import)
+	admin.GET("/transactions\\:export", h.Export)
 
 	return nil
 }
 
-// Healthcheck of the service.
-func healthCheck(db *database.Postgres) echo.HandlerFunc {
+// healthDB is the part of the database the health check uses.
+type healthDB interface {
+	Ping() error
+	MigrationStatus() (*model.MigrationStatus, error)
+}
+
+// Healthcheck of the service, it fails if the database is unreachable. It reports the migration status and
+// fails as well if the schema is behind or dirty, unless the migrate mode is off and the schema is left to the operator.
+func healthCheck(db healthDB, mode string) echo.HandlerFunc {
+	checked := mode != config.MigrateModeOff
+
 	return func(c echo.Context) error {
 		if db.Ping() != nil {
 			return c.JSON(StatusInternalServerError, echo.Map{"status": "failed"})
 		}
 
-		return c.JSON(StatusOK, echo.Map{"status": "ok"})
+		status, err := db.MigrationStatus()
+		if err != nil {
+			if checked {
+				return c.JSON(StatusInternalServerError, echo.Map{"status": "failed"})
+			}
+
+			return c.JSON(StatusOK, echo.Map{"status": "ok"})
+		}
+
+		if checked && status.Check() != nil {
+			return c.JSON(StatusInternalServerError, echo.Map{"status": "failed", "migrations": status})
+		}
+
+		return c.JSON(StatusOK, echo.Map{"status": "ok", "migrations": status})
 	}
 }
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ttagiyeva/entain/internal/config"
+	"github.com/ttagiyeva/entain/internal/model"
+	transactionHttp "github.com/ttagiyeva/entain/internal/transaction/delivery/http"
+)
+
+func TestBatchRoute(t *testing.T) {
+	e := echo.New()
+	require.NoError(t, RegisterRouters(e, &config.Config{}, transactionHttp.NewHandler(nil, &config.Config{}, nil, nil), nil, nil, nil, nil))
+
+	// The colon of the path is escaped, it is not the start of a path param.
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions:batch", strings.NewReader(`{"transactions":[]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.JSONEq(t, `{"code":400,"message":"Transactions field is required"}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/transactions:unknown", nil)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAdminRoutes(t *testing.T) {
+	e := echo.New()
+	require.NoError(t, RegisterRouters(e, &config.Config{Admin: config.Admin{Token: "secret"}}, transactionHttp.NewHandler(nil, &config.Config{}, nil, nil), nil, nil, nil, nil))
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodPut, path: "/api/v1/admin/users/1/limits"},
+		{method: http.MethodPost, path: "/api/v1/admin/users/1/adjustments"},
+		{method: http.MethodPost, path: "/api/v1/admin/users/1/suspend"},
+		{method: http.MethodPost, path: "/api/v1/admin/users/1/activate"},
+		{method: http.MethodPost, path: "/api/v1/admin/users/1/close"},
+		{method: http.MethodPost, path: "/api/v1/admin/users/1/bonuses"},
+		{method: http.MethodPost, path: "/api/v1/admin/payments/1/confirm"},
+		{method: http.MethodPost, path: "/api/v1/admin/payments/1/fail"},
+		{method: http.MethodPost, path: "/api/v1/admin/transactions:import"},
+		{method: http.MethodGet, path: "/api/v1/admin/transactions:export"},
+	}
+
+	for _, r := range routes {
+		t.Run(r.method+" "+r.path, func(t *testing.T) {
+			req := httptest.NewRequest(r.method, r.path, nil)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+			req = httptest.NewRequest(r.method, strings.Replace(r.path, "/admin", "", 1), nil)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer secret")
+
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			require.Contains(t, []int{http.StatusNotFound, http.StatusMethodNotAllowed}, rec.Code)
+		})
+	}
+}
+
+// fakeHealthDB is a database whose ping and migration status are given.
+type fakeHealthDB struct {
+	pingErr   error
+	status    *model.MigrationStatus
+	statusErr error
+}
+
+func (db *fakeHealthDB) Ping() error {
+	return db.pingErr
+}
+
+func (db *fakeHealthDB) MigrationStatus() (*model.MigrationStatus, error) {
+	return db.status, db.statusErr
+}
+
+func TestHealthCheck(t *testing.T) {
+	current := &model.MigrationStatus{Version: 17, Latest: 17}
+	behind := &model.MigrationStatus{Version: 16, Latest: 17}
+	dirty := &model.MigrationStatus{Version: 17, Latest: 17, Dirty: true}
+
+	testCases := []struct {
+		name         string
+		db           *fakeHealthDB
+		mode         string
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "OK", db: &fakeHealthDB{status: current}, mode: config.MigrateModeAuto, expectedCode: http.StatusOK, expectedBody: `{"status":"ok","migrations":{"version":17,"latest":17,"dirty":false}}`},
+		{name: "Database unreachable", db: &fakeHealthDB{pingErr: errors.New("refused")}, mode: config.MigrateModeAuto, expectedCode: http.StatusInternalServerError, expectedBody: `{"status":"failed"}`},
+		{name: "Behind", db: &fakeHealthDB{status: behind}, mode: config.MigrateModeAuto, expectedCode: http.StatusInternalServerError, expectedBody: `{"status":"failed","migrations":{"version":16,"latest":17,"dirty":false}}`},
+		{name: "Dirty", db: &fakeHealthDB{status: dirty}, mode: config.MigrateModeCheckOnly, expectedCode: http.StatusInternalServerError, expectedBody: `{"status":"failed","migrations":{"version":17,"latest":17,"dirty":true}}`},
+		{name: "Status unavailable", db: &fakeHealthDB{statusErr: errors.New("no table")}, mode: config.MigrateModeCheckOnly, expectedCode: http.StatusInternalServerError, expectedBody: `{"status":"failed"}`},
+		{name: "Behind with migrations off", db: &fakeHealthDB{status: behind}, mode: config.MigrateModeOff, expectedCode: http.StatusOK, expectedBody: `{"status":"ok","migrations":{"version":16,"latest":17,"dirty":false}}`},
+		{name: "Dirty with migrations off", db: &fakeHealthDB{status: dirty}, mode: config.MigrateModeOff, expectedCode: http.StatusOK, expectedBody: `{"status":"ok","migrations":{"version":17,"latest":17,"dirty":true}}`},
+		{name: "Status unavailable with migrations off", db: &fakeHealthDB{statusErr: errors.New("no table")}, mode: config.MigrateModeOff, expectedCode: http.StatusOK, expectedBody: `{"status":"ok"}`},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			require.NoError(t, healthCheck(tc.db, tc.mode)(c))
+			require.Equal(t, tc.expectedCode, rec.Code)
+			require.JSONEq(t, tc.expectedBody, rec.Body.String())
+		})
+	}
+}
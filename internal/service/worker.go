@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.uber.org/fx"
+
+	"github.com/ttagiyeva/entain/internal/config"
+	"github.com/ttagiyeva/entain/internal/transaction"
+)
+
+// RegisterPostProcess runs the transaction post process for the lifetime of the application.
+// On stop the worker is cancelled and the in-flight batch is awaited.
+func RegisterPostProcess(lc fx.Lifecycle, log *slog.Logger, conf *config.Config, uc transaction.Usecase) {
+	if !conf.PostProcess.Enabled {
+		log.Info("post process is disabled")
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+
+				uc.PostProcess(ctx)
+			}()
+
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			log.Info("stopping the post process gracefully")
+
+			cancel()
+
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return fmt.Errorf("failed to wait for the post process to stop: %w", stopCtx.Err())
+			}
+		},
+	})
+}
+
+// RegisterBetExpiry runs the expiry of unsettled bets for the lifetime of the application.
+// On stop the worker is cancelled and the in-flight batch is awaited.
+func RegisterBetExpiry(lc fx.Lifecycle, log *slog.Logger, conf *config.Config, uc transaction.Usecase) {
+	if !conf.Bets.Enabled {
+		log.Info("bet expiry is disabled")
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+
+				uc.ExpireBets(ctx)
+			}()
+
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			log.Info("stopping the bet expiry gracefully")
+
+			cancel()
+
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return fmt.Errorf("failed to wait for the bet expiry to stop: %w", stopCtx.Err())
+			}
+		},
+	})
+}
@@ -1,34 +1,64 @@
 package http
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/labstack/echo/v4"
 
+	"github.com/ttagiyeva/entain/internal/config"
 	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/registry"
 	"github.com/ttagiyeva/entain/internal/transaction"
 )
 
 const (
 	SourceType = "Source-Type"
+
+	// IdempotencyKey is the header which identifies a transfer, a retry with the same key is not moved again.
+	IdempotencyKey = "Idempotency-Key"
+
+	// defaultPageSize is the number of transactions in a history page when no limit is given.
+	defaultPageSize = 50
+	// defaultMaxBatchSize is used when the configured maximum batch size is not valid.
+	defaultMaxBatchSize = 100
+	// exportFlushEvery is the number of exported transactions after which the response is flushed.
+	exportFlushEvery = 100
+
+	// MIMEApplicationNDJSON is the content type of newline-delimited json.
+	MIMEApplicationNDJSON = "application/x-ndjson"
+	// MIMETextCSV is the content type of csv.
+	MIMETextCSV = "text/csv"
 )
 
 // Handler is a structure which manages http handlers.
 type Handler struct {
-	log     *slog.Logger
-	usecase transaction.Usecase
+	log          *slog.Logger
+	usecase      transaction.Usecase
+	registry     registry.Usecase
+	maxBatchSize int
 }
 
 // NewHandler creates a new http handler.
-func NewHandler(log *slog.Logger, u transaction.Usecase) *Handler {
+func NewHandler(log *slog.Logger, conf *config.Config, u transaction.Usecase, r registry.Usecase) *Handler {
+	maxBatchSize := conf.Batch.MaxSize
+	if maxBatchSize <= 0 {
+		maxBatchSize = defaultMaxBatchSize
+	}
+
 	return &Handler{
-		log:     log,
-		usecase: u,
+		log:          log,
+		usecase:      u,
+		registry:     r,
+		maxBatchSize: maxBatchSize,
 	}
 }
 
@@ -46,14 +76,22 @@ func (h *Handler) Process(ctx echo.Context) error {
 	transaction.UserID = ctx.Param("id")
 	transaction.SourceType = ctx.Request().Header.Get(SourceType)
 
-	sv := validator.New()
+	c := ctx.Request().Context()
 
-	err = sv.Struct(transaction)
+	reg, err := h.registry.Get(c)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, h.validatorError(err))
+		h.log.Error("failed to get the registry", "error", err)
+
+		return ctx.JSON(http.StatusInternalServerError, model.Error{
+			Code:    http.StatusInternalServerError,
+			Message: model.ErrorInternalServerError.Error(),
+		})
 	}
 
-	c := ctx.Request().Context()
+	err = reg.Validator().Struct(transaction)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, h.validatorError(err, reg))
+	}
 
 	err = h.usecase.Process(c, transaction)
 	if err != nil {
@@ -67,7 +105,660 @@ func (h *Handler) Process(ctx echo.Context) error {
 	return ctx.NoContent(http.StatusOK)
 }
 
-func (h *Handler) validatorError(err error) model.Error {
+// ProcessBatch processes the transactions of a batch of a game server and returns the result of each of them
+// in the order of the request. The Source-Type header applies to every transaction of the batch. An invalid
+// transaction fails on its own, unless the batch is atomic, in which case the whole batch is refused.
+func (h *Handler) ProcessBatch(ctx echo.Context) error {
+	batch := &model.Batch{}
+
+	err := ctx.Bind(batch)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: model.ErrorBadRequest,
+		})
+	}
+
+	if len(batch.Transactions) == 0 {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Transactions field is required",
+		})
+	}
+
+	if len(batch.Transactions) > h.maxBatchSize {
+		return ctx.JSON(http.StatusRequestEntityTooLarge, model.Error{
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("A batch must have at most %d transactions", h.maxBatchSize),
+		})
+	}
+
+	c := ctx.Request().Context()
+
+	reg, err := h.registry.Get(c)
+	if err != nil {
+		h.log.Error("failed to get the registry", "error", err)
+
+		return ctx.JSON(http.StatusInternalServerError, model.Error{
+			Code:    http.StatusInternalServerError,
+			Message: model.ErrorInternalServerError.Error(),
+		})
+	}
+
+	sourceType := ctx.Request().Header.Get(SourceType)
+	results := make([]*model.BatchResult, len(batch.Transactions))
+	valid := make([]*model.Transaction, 0, len(batch.Transactions))
+	indexes := make([]int, 0, len(batch.Transactions))
+
+	for i, tr := range batch.Transactions {
+		if tr == nil {
+			tr = &model.Transaction{}
+		}
+
+		tr.SourceType = sourceType
+
+		err = reg.Validator().Struct(tr)
+		if err != nil {
+			resp := h.validatorError(err, reg)
+
+			if batch.Atomic {
+				resp.Message = fmt.Sprintf("Transaction %d: %s", i, resp.Message)
+
+				return ctx.JSON(resp.Code, resp)
+			}
+
+			results[i] = &model.BatchResult{TransactionID: tr.TransactionID, Status: model.BatchStatusFailed, Error: &resp}
+
+			continue
+		}
+
+		valid = append(valid, tr)
+		indexes = append(indexes, i)
+	}
+
+	if len(valid) > 0 {
+		processed, err := h.usecase.ProcessBatch(c, valid, batch.Atomic)
+		if err != nil {
+			h.log.Error("failed to process batch", "error", err)
+
+			resp := getError(err)
+
+			return ctx.JSON(resp.Code, resp)
+		}
+
+		for j, result := range processed {
+			if result.Err != nil {
+				h.log.With("body", valid[j]).Error("failed to process transaction of the batch", "error", result.Err)
+
+				resp := getError(result.Err)
+				result.Error = &resp
+			}
+
+			results[indexes[j]] = result
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, model.BatchResponse{Results: results})
+}
+
+// Import processes the newline-delimited json transactions of the request body one at a time, it is a part of
+// the admin api for migrations. The Source-Type header, if given, overrides the source type of every transaction.
+// The response streams as newline-delimited json a failure for every line which could not be processed and
+// the progress every configured number of lines, the last line is the final progress.
+func (h *Handler) Import(ctx echo.Context) error {
+	c := ctx.Request().Context()
+
+	reg, err := h.registry.Get(c)
+	if err != nil {
+		h.log.Error("failed to get the registry", "error", err)
+
+		return ctx.JSON(http.StatusInternalServerError, model.Error{
+			Code:    http.StatusInternalServerError,
+			Message: model.ErrorInternalServerError.Error(),
+		})
+	}
+
+	// The response starts while the body is still read, net/http closes the unread body once the headers are
+	// sent unless full duplex is enabled. HTTP/2 does not support enabling it as it is always full duplex.
+	err = http.NewResponseController(ctx.Response().Writer).EnableFullDuplex()
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.log.Error("failed to enable full duplex", "error", err)
+	}
+
+	stream := &importStream{h: h, ctx: ctx, reg: reg, enc: json.NewEncoder(ctx.Response())}
+
+	progress, err := h.usecase.Import(c, ctx.Request().Body, ctx.Request().Header.Get(SourceType), stream)
+	if err != nil {
+		h.log.Error("failed to import transactions", "error", err)
+
+		resp := getError(err)
+
+		if !ctx.Response().Committed {
+			return ctx.JSON(resp.Code, resp)
+		}
+
+		if progress == nil {
+			progress = &model.ImportProgress{}
+		}
+
+		progress.Error = &resp
+		stream.write(progress)
+	}
+
+	return nil
+}
+
+// importStream writes the progress of an import and its failed lines to the response.
+type importStream struct {
+	h   *Handler
+	ctx echo.Context
+	reg *model.Registry
+	enc *json.Encoder
+}
+
+// Progress implements transaction.ImportReporter.
+func (s *importStream) Progress(p model.ImportProgress) {
+	s.write(&p)
+}
+
+// Failure implements transaction.ImportReporter.
+func (s *importStream) Failure(f *model.ImportFailure) {
+	resp := getError(f.Err)
+
+	var errs validator.ValidationErrors
+	if errors.As(f.Err, &errs) {
+		resp = s.h.validatorError(errs, s.reg)
+	} else {
+		s.h.log.With("line", f.Line).Error("failed to import transaction", "error", f.Err)
+	}
+
+	f.Error = &resp
+	s.write(f)
+}
+
+// write writes v as a line of the response and flushes it, so the client sees the progress at once.
+func (s *importStream) write(v any) {
+	res := s.ctx.Response()
+
+	if !res.Committed {
+		res.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+		res.WriteHeader(http.StatusOK)
+	}
+
+	err := s.enc.Encode(v)
+	if err != nil {
+		s.h.log.Error("failed to write import progress", "error", err)
+
+		return
+	}
+
+	res.Flush()
+}
+
+// Export streams the stored transactions in the order they were made as newline-delimited json or csv,
+// it is a part of the admin api for analytics. The userId, from and to query params are optional.
+func (h *Handler) Export(ctx echo.Context) error {
+	filter, err := exportFilter(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+	}
+
+	err = model.NewValidator().Struct(filter)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, h.validatorError(err, nil))
+	}
+
+	res := ctx.Response()
+	count := 0
+
+	var write func(tr *model.TransactionView) error
+
+	var flush func() error
+
+	if filter.Format == model.ExportFormatCSV {
+		res.Header().Set(echo.HeaderContentType, MIMETextCSV)
+
+		// The header stays in the buffer of the writer, so an early error can still be sent as json.
+		w := csv.NewWriter(res)
+		_ = w.Write(model.TransactionCSVHeader)
+
+		write = func(tr *model.TransactionView) error {
+			return w.Write(tr.CSVRecord())
+		}
+
+		flush = func() error {
+			w.Flush()
+
+			return w.Error()
+		}
+	} else {
+		res.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+
+		enc := json.NewEncoder(res)
+
+		write = func(tr *model.TransactionView) error {
+			return enc.Encode(tr)
+		}
+
+		flush = func() error {
+			return nil
+		}
+	}
+
+	err = h.usecase.Export(ctx.Request().Context(), filter, func(tr *model.TransactionView) error {
+		err := write(tr)
+		if err != nil {
+			return fmt.Errorf("failed to write transaction: %w", err)
+		}
+
+		count++
+
+		if count%exportFlushEvery == 0 {
+			err = flush()
+			if err != nil {
+				return fmt.Errorf("failed to flush transactions: %w", err)
+			}
+
+			res.Flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		h.log.With("filter", filter).Error("failed to export transactions", "error", err)
+
+		// Once the first transactions are sent the status cannot change, the client sees a truncated export.
+		if !res.Committed {
+			resp := getError(err)
+			res.Header().Del(echo.HeaderContentType)
+
+			return ctx.JSON(resp.Code, resp)
+		}
+
+		return nil
+	}
+
+	err = flush()
+	if err != nil {
+		h.log.With("filter", filter).Error("failed to export transactions", "error", err)
+
+		return nil
+	}
+
+	if !res.Committed {
+		res.WriteHeader(http.StatusOK)
+	}
+
+	res.Flush()
+
+	return nil
+}
+
+// History returns the transaction history of the user, the latest transactions first.
+func (h *Handler) History(ctx echo.Context) error {
+	filter, err := historyFilter(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+	}
+
+	c := ctx.Request().Context()
+
+	reg, err := h.registry.Get(c)
+	if err != nil {
+		h.log.Error("failed to get the registry", "error", err)
+
+		return ctx.JSON(http.StatusInternalServerError, model.Error{
+			Code:    http.StatusInternalServerError,
+			Message: model.ErrorInternalServerError.Error(),
+		})
+	}
+
+	err = reg.Validator().Struct(filter)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, h.validatorError(err, reg))
+	}
+
+	page, err := h.usecase.GetTransactions(c, filter)
+	if err != nil {
+		h.log.With("filter", filter).Error("failed to get transaction history", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusOK, page)
+}
+
+// GrantBonus credits bonus funds with a wagering requirement to the wallet of the user.
+func (h *Handler) GrantBonus(ctx echo.Context) error {
+	bonus := &model.Bonus{}
+
+	err := ctx.Bind(bonus)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: model.ErrorBadRequest,
+		})
+	}
+
+	bonus.UserID = ctx.Param("id")
+
+	sv := model.NewValidator()
+
+	err = sv.Struct(bonus)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, h.validatorError(err, nil))
+	}
+
+	err = h.usecase.GrantBonus(ctx.Request().Context(), bonus)
+	if err != nil {
+		h.log.With("body", bonus).Error("failed to grant bonus", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.NoContent(http.StatusCreated)
+}
+
+// Deposit starts a deposit to the wallet of the user, it is pending until the payment provider completes it.
+func (h *Handler) Deposit(ctx echo.Context) error {
+	return h.startPayment(ctx, model.PaymentKindDeposit)
+}
+
+// Withdraw starts a withdrawal from the wallet of the user, its amount is reserved until the payment provider completes it.
+func (h *Handler) Withdraw(ctx echo.Context) error {
+	return h.startPayment(ctx, model.PaymentKindWithdrawal)
+}
+
+func (h *Handler) startPayment(ctx echo.Context, kind string) error {
+	payment := &model.Payment{}
+
+	err := ctx.Bind(payment)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: model.ErrorBadRequest,
+		})
+	}
+
+	payment.UserID = ctx.Param("id")
+	payment.Kind = kind
+
+	sv := model.NewValidator()
+
+	err = sv.Struct(payment)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, h.validatorError(err, nil))
+	}
+
+	tr, err := h.usecase.StartPayment(ctx.Request().Context(), payment)
+	if err != nil {
+		h.log.With("body", payment).Error("failed to start payment", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusCreated, tr)
+}
+
+// ConfirmPayment confirms a pending deposit or withdrawal.
+func (h *Handler) ConfirmPayment(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	tr, err := h.usecase.ConfirmPayment(ctx.Request().Context(), id)
+	if err != nil {
+		h.log.With("id", id).Error("failed to confirm payment", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusOK, tr)
+}
+
+// FailPayment fails a pending deposit or withdrawal.
+func (h *Handler) FailPayment(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	tr, err := h.usecase.FailPayment(ctx.Request().Context(), id)
+	if err != nil {
+		h.log.With("id", id).Error("failed to fail payment", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusOK, tr)
+}
+
+// Adjust credits or debits the wallet of the user by hand, it is a part of the admin api for support staff.
+func (h *Handler) Adjust(ctx echo.Context) error {
+	adjustment := &model.Adjustment{}
+
+	err := ctx.Bind(adjustment)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: model.ErrorBadRequest,
+		})
+	}
+
+	adjustment.UserID = ctx.Param("id")
+
+	err = model.NewValidator().Struct(adjustment)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, h.validatorError(err, nil))
+	}
+
+	tr, err := h.usecase.Adjust(ctx.Request().Context(), adjustment)
+	if err != nil {
+		h.log.With("body", adjustment).Error("failed to adjust balance", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusCreated, tr)
+}
+
+// Transfer moves money from the wallet of a user to the wallet of another one.
+func (h *Handler) Transfer(ctx echo.Context) error {
+	req := &model.Transfer{}
+
+	err := ctx.Bind(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: model.ErrorBadRequest,
+		})
+	}
+
+	req.IdempotencyKey = ctx.Request().Header.Get(IdempotencyKey)
+
+	err = model.NewValidator().Struct(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, h.validatorError(err, nil))
+	}
+
+	transfer, err := h.usecase.Transfer(ctx.Request().Context(), req)
+	if err != nil {
+		h.log.With("body", req).Error("failed to transfer", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusCreated, transfer)
+}
+
+// PlaceBet places a bet of a game round, its stake is reserved from the wallet of the user until it is settled.
+func (h *Handler) PlaceBet(ctx echo.Context) error {
+	req := &model.PlaceBet{}
+
+	err := ctx.Bind(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: model.ErrorBadRequest,
+		})
+	}
+
+	req.UserID = ctx.Param("id")
+
+	err = model.NewValidator().Struct(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, h.validatorError(err, nil))
+	}
+
+	bet, err := h.usecase.PlaceBet(ctx.Request().Context(), req)
+	if err != nil {
+		h.log.With("body", req).Error("failed to place bet", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusCreated, bet)
+}
+
+// SettleBet settles a placed bet as a win or a loss.
+func (h *Handler) SettleBet(ctx echo.Context) error {
+	req := &model.SettleBet{}
+
+	err := ctx.Bind(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: model.ErrorBadRequest,
+		})
+	}
+
+	req.ID = ctx.Param("id")
+
+	err = model.NewValidator().Struct(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, h.validatorError(err, nil))
+	}
+
+	bet, err := h.usecase.SettleBet(ctx.Request().Context(), req)
+	if err != nil {
+		h.log.With("body", req).Error("failed to settle bet", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusOK, bet)
+}
+
+// VoidBet voids a placed bet and releases its stake.
+func (h *Handler) VoidBet(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	bet, err := h.usecase.VoidBet(ctx.Request().Context(), id)
+	if err != nil {
+		h.log.With("id", id).Error("failed to void bet", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusOK, bet)
+}
+
+// historyFilter builds the filter of the transaction history from the query params.
+func historyFilter(ctx echo.Context) (*model.TransactionFilter, error) {
+	filter := &model.TransactionFilter{
+		UserID:     ctx.Param("id"),
+		State:      ctx.QueryParam("state"),
+		SourceType: ctx.QueryParam("sourceType"),
+		Limit:      defaultPageSize,
+	}
+
+	if v := ctx.QueryParam("cancelled"); v != "" {
+		cancelled, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.New("Value of the cancelled param must be a boolean")
+		}
+
+		filter.Cancelled = &cancelled
+	}
+
+	for param, field := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := ctx.QueryParam(param); v != "" {
+			tm, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("Value of the %s param must be a RFC3339 time", param)
+			}
+
+			*field = &tm
+		}
+	}
+
+	if v := ctx.QueryParam("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.New("Value of the limit param must be an integer")
+		}
+
+		filter.Limit = limit
+	}
+
+	cursor, err := model.DecodeCursor(ctx.QueryParam("cursor"))
+	if err != nil {
+		return nil, errors.New("Value of the cursor param is not valid")
+	}
+
+	filter.Cursor = cursor
+
+	return filter, nil
+}
+
+func exportFilter(ctx echo.Context) (*model.ExportFilter, error) {
+	filter := &model.ExportFilter{
+		UserID: ctx.QueryParam("userId"),
+		Format: ctx.QueryParam("format"),
+	}
+
+	if filter.Format == "" {
+		filter.Format = model.ExportFormatNDJSON
+	}
+
+	for param, field := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := ctx.QueryParam(param); v != "" {
+			tm, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("Value of the %s param must be a RFC3339 time", param)
+			}
+
+			*field = &tm
+		}
+	}
+
+	return filter, nil
+}
+
+// validatorError describes the validation errors, the registry lists the allowed values of the "state"
+// and "source_type" tags and may be nil if the validated struct has none of them.
+func (h *Handler) validatorError(err error, reg *model.Registry) model.Error {
 	if _, ok := err.(*validator.InvalidValidationError); ok {
 		h.log.Error("failed to assert validation error", "error", err)
 
@@ -88,6 +779,22 @@ func (h *Handler) validatorError(err error) model.Error {
 			sb.WriteString(fmt.Sprintf("Value of the %s field must be one of '%s'", err.Field(), err.Param()))
 		case "gt":
 			sb.WriteString(fmt.Sprintf("Value of the %s field must be greater than %s", err.Field(), err.Param()))
+		case "gte", "min":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must be at least %s", err.Field(), err.Param()))
+		case "max":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must be at most %s", err.Field(), err.Param()))
+		case "nefield":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must differ from the %s field", err.Field(), err.Param()))
+		case "uuid":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must be a UUID", err.Field()))
+		case "iso4217":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must be an ISO 4217 currency code", err.Field()))
+		case "transaction_id":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must not start with '%s'", err.Field(), strings.Join(model.ReservedTransactionIDPrefixes, "' or '")))
+		case "state":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must be one of '%s'", err.Field(), strings.Join(reg.States(), " ")))
+		case "source_type":
+			sb.WriteString(fmt.Sprintf("Value of the %s field must be one of '%s'", err.Field(), strings.Join(reg.SourceTypes(), " ")))
 		}
 	}
 
@@ -98,8 +805,42 @@ func getError(err error) model.Error {
 	switch {
 	case errors.Is(err, model.ErrorUserNotFound):
 		return model.Error{Code: http.StatusNotFound, Message: model.ErrorUserNotFound.Error()}
+	case errors.Is(err, model.ErrorUserSuspended):
+		return model.Error{Code: http.StatusLocked, Message: model.ErrorUserSuspended.Error()}
+	case errors.Is(err, model.ErrorUserClosed):
+		return model.Error{Code: http.StatusGone, Message: model.ErrorUserClosed.Error()}
+	case errors.Is(err, model.ErrorWalletNotFound):
+		return model.Error{Code: http.StatusUnprocessableEntity, Message: model.ErrorWalletNotFound.Error()}
 	case errors.Is(err, model.ErrorInsufficientBalance):
 		return model.Error{Code: http.StatusForbidden, Message: model.ErrorInsufficientBalance.Error()}
+	case errors.Is(err, model.ErrorUserSelfExcluded):
+		return model.Error{Code: http.StatusForbidden, Message: model.ErrorUserSelfExcluded.Error()}
+	case errors.Is(err, model.ErrorLossLimitExceeded):
+		return model.Error{Code: http.StatusTooManyRequests, Message: model.ErrorLossLimitExceeded.Error()}
+	case errors.Is(err, model.ErrorDepositLimitExceeded):
+		return model.Error{Code: http.StatusTooManyRequests, Message: model.ErrorDepositLimitExceeded.Error()}
+	case errors.Is(err, model.ErrorTransactionNotFound):
+		return model.Error{Code: http.StatusNotFound, Message: model.ErrorTransactionNotFound.Error()}
+	case errors.Is(err, model.ErrorPaymentNotPending):
+		return model.Error{Code: http.StatusConflict, Message: model.ErrorPaymentNotPending.Error()}
+	case errors.Is(err, model.ErrorPaymentState):
+		return model.Error{Code: http.StatusBadRequest, Message: model.ErrorPaymentState.Error()}
+	case errors.Is(err, model.ErrorAdjustmentState):
+		return model.Error{Code: http.StatusBadRequest, Message: model.ErrorAdjustmentState.Error()}
+	case errors.Is(err, model.ErrorTransferAlreadyExists):
+		return model.Error{Code: http.StatusConflict, Message: model.ErrorTransferAlreadyExists.Error()}
+	case errors.Is(err, model.ErrorTransferState):
+		return model.Error{Code: http.StatusBadRequest, Message: model.ErrorTransferState.Error()}
+	case errors.Is(err, model.ErrorBetNotFound):
+		return model.Error{Code: http.StatusNotFound, Message: model.ErrorBetNotFound.Error()}
+	case errors.Is(err, model.ErrorBetAlreadyExists):
+		return model.Error{Code: http.StatusConflict, Message: model.ErrorBetAlreadyExists.Error()}
+	case errors.Is(err, model.ErrorBetNotPlaced):
+		return model.Error{Code: http.StatusConflict, Message: model.ErrorBetNotPlaced.Error()}
+	case errors.Is(err, model.ErrorInvalidLine):
+		return model.Error{Code: http.StatusBadRequest, Message: model.ErrorInvalidLine.Error()}
+	case errors.Is(err, model.ErrorUnknownState):
+		return model.Error{Code: http.StatusBadRequest, Message: model.ErrorUnknownState.Error()}
 	case errors.Is(err, model.ErrorTransactionAlreadyExists):
 		return model.Error{Code: http.StatusConflict, Message: model.ErrorTransactionAlreadyExists.Error()}
 	default:
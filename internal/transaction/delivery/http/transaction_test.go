@@ -1,22 +1,46 @@
 package http
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ttagiyeva/entain/internal/config"
 	"github.com/ttagiyeva/entain/internal/model"
+	registryMocks "github.com/ttagiyeva/entain/internal/registry/mocks"
+	"github.com/ttagiyeva/entain/internal/transaction"
 	"github.com/ttagiyeva/entain/internal/transaction/mocks"
 )
 
+// testRegistry is the registry of the initial migrations, in the order the repository returns it.
+func testRegistry() *model.Registry {
+	return model.NewRegistry(
+		[]*model.SourceTypeDao{{Name: "game"}, {Name: "payment"}, {Name: "server"}},
+		[]*model.StateDao{{Name: "lost", Direction: model.DirectionDebit}, {Name: "win", Direction: model.DirectionCredit}},
+	)
+}
+
+// newTestHandler returns a handler whose registry usecase always returns testRegistry.
+func newTestHandler(ctrl *gomock.Controller, trUsecase *mocks.MockUsecase) *Handler {
+	regUsecase := registryMocks.NewMockRegistryUsecase(ctrl)
+	regUsecase.EXPECT().Get(gomock.Any()).Return(testRegistry(), nil).AnyTimes()
+
+	return NewHandler(slog.Default(), &config.Config{Batch: config.Batch{MaxSize: 3}}, trUsecase, regUsecase)
+}
+
 // TestTransactionHandler_Process tests the transaction handler process method.
 func TestTransactionHandler_Process(t *testing.T) {
 	testCases := []struct {
@@ -29,7 +53,7 @@ func TestTransactionHandler_Process(t *testing.T) {
 	}{
 		{
 			name: "OK",
-			body: []byte(`{"transactionId":"1","state":"win","amount":1}`),
+			body: []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"EUR"}`),
 			buildStubs: func(trUsecase *mocks.MockUsecase) {
 				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(nil)
 			},
@@ -37,7 +61,16 @@ func TestTransactionHandler_Process(t *testing.T) {
 		},
 		{
 			name:       "Invalid request body",
-			body:       []byte(`{"transactionId":"1","state":"win","amount":"1"}`),
+			body:       []byte(`{"transactionId":"1","state":"win","amount":"1","currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: model.ErrorBadRequest,
+			},
+		},
+		{
+			name:       "Amount with more than two decimals",
+			body:       []byte(`{"transactionId":"1","state":"win","amount":10.155,"currency":"EUR"}`),
 			buildStubs: func(trUsecase *mocks.MockUsecase) {},
 			expectedError: model.Error{
 				Code:    http.StatusBadRequest,
@@ -46,26 +79,26 @@ func TestTransactionHandler_Process(t *testing.T) {
 		},
 		{
 			name:       "Invalid source type",
-			body:       []byte(`{"transactionId":"1","state":"win","amount":1}`),
+			body:       []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"EUR"}`),
 			SourceType: "test",
 			buildStubs: func(trUsecase *mocks.MockUsecase) {},
 			expectedError: model.Error{
 				Code:    http.StatusBadRequest,
-				Message: "Value of the SourceType field must be one of 'game server payment'",
+				Message: "Value of the SourceType field must be one of 'game payment server'",
 			},
 		},
 		{
 			name:       "Invalid state",
-			body:       []byte(`{"transactionId":"1","state":"won","amount":1}`),
+			body:       []byte(`{"transactionId":"1","state":"won","amount":1,"currency":"EUR"}`),
 			buildStubs: func(trUsecase *mocks.MockUsecase) {},
 			expectedError: model.Error{
 				Code:    http.StatusBadRequest,
-				Message: "Value of the State field must be one of 'win lost'",
+				Message: "Value of the State field must be one of 'lost win'",
 			},
 		},
 		{
 			name:       "Invalid amount",
-			body:       []byte(`{"transactionId":"1","state":"win","amount":-1}`),
+			body:       []byte(`{"transactionId":"1","state":"win","amount":-1,"currency":"EUR"}`),
 			buildStubs: func(trUsecase *mocks.MockUsecase) {},
 			expectedError: model.Error{
 				Code:    http.StatusBadRequest,
@@ -74,16 +107,51 @@ func TestTransactionHandler_Process(t *testing.T) {
 		},
 		{
 			name:       "Invalid transactionId",
-			body:       []byte(`{"state":"win","amount":1}`),
+			body:       []byte(`{"state":"win","amount":1,"currency":"EUR"}`),
 			buildStubs: func(trUsecase *mocks.MockUsecase) {},
 			expectedError: model.Error{
 				Code:    http.StatusBadRequest,
 				Message: "TransactionID field is required",
 			},
 		},
+		{
+			name:       "Reserved transactionId",
+			body:       []byte(`{"transactionId":"transfer:1:in","state":"win","amount":1,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the TransactionID field must not start with 'transfer:' or 'bet:'",
+			},
+		},
+		{
+			name:       "Missing currency",
+			body:       []byte(`{"transactionId":"1","state":"win","amount":1}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Currency field is required",
+			},
+		},
+		{
+			name:       "Invalid currency",
+			body:       []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"EURO"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the Currency field must be an ISO 4217 currency code",
+			},
+		},
+		{
+			name: "Wallet not found",
+			body: []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"USD"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(model.ErrorWalletNotFound)
+			},
+			expectedError: model.Error{Code: http.StatusUnprocessableEntity, Message: model.ErrorWalletNotFound.Error()},
+		},
 		{
 			name: "Transaction already exists",
-			body: []byte(`{"transactionId":"1","state":"win","amount":1}`),
+			body: []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"EUR"}`),
 			buildStubs: func(trUsecase *mocks.MockUsecase) {
 				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(model.ErrorTransactionAlreadyExists)
 			},
@@ -91,15 +159,55 @@ func TestTransactionHandler_Process(t *testing.T) {
 		},
 		{
 			name: "Insufficient Balance error",
-			body: []byte(`{"transactionId":"1","state":"win","amount":1}`),
+			body: []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"EUR"}`),
 			buildStubs: func(trUsecase *mocks.MockUsecase) {
 				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(model.ErrorInsufficientBalance)
 			},
 			expectedError: getError(model.ErrorInsufficientBalance),
 		},
+		{
+			name: "Self-excluded user",
+			body: []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(model.ErrorUserSelfExcluded)
+			},
+			expectedError: model.Error{Code: http.StatusForbidden, Message: model.ErrorUserSelfExcluded.Error()},
+		},
+		{
+			name: "Loss limit exceeded",
+			body: []byte(`{"transactionId":"1","state":"lost","amount":1,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(model.ErrorLossLimitExceeded)
+			},
+			expectedError: model.Error{Code: http.StatusTooManyRequests, Message: model.ErrorLossLimitExceeded.Error()},
+		},
+		{
+			name: "State removed from the registry meanwhile",
+			body: []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(model.ErrorUnknownState)
+			},
+			expectedError: model.Error{Code: http.StatusBadRequest, Message: model.ErrorUnknownState.Error()},
+		},
+		{
+			name: "Suspended user",
+			body: []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(model.ErrorUserSuspended)
+			},
+			expectedError: model.Error{Code: http.StatusLocked, Message: model.ErrorUserSuspended.Error()},
+		},
+		{
+			name: "Closed user",
+			body: []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(model.ErrorUserClosed)
+			},
+			expectedError: model.Error{Code: http.StatusGone, Message: model.ErrorUserClosed.Error()},
+		},
 		{
 			name: "User not found",
-			body: []byte(`{"transactionId":"1","state":"win","amount":1}`),
+			body: []byte(`{"transactionId":"1","state":"win","amount":1,"currency":"EUR"}`),
 			buildStubs: func(trUsecase *mocks.MockUsecase) {
 				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(model.ErrorUserNotFound)
 			},
@@ -107,7 +215,7 @@ func TestTransactionHandler_Process(t *testing.T) {
 		},
 		{
 			name: "Internal server error",
-			body: []byte(`{"transactionId":"0","state":"win","amount":1}`),
+			body: []byte(`{"transactionId":"0","state":"win","amount":1,"currency":"EUR"}`),
 			buildStubs: func(trUsecase *mocks.MockUsecase) {
 				trUsecase.EXPECT().Process(gomock.Any(), gomock.Any()).Return(fmt.Errorf("unexpected error"))
 			},
@@ -124,7 +232,7 @@ func TestTransactionHandler_Process(t *testing.T) {
 			trUsecase := mocks.NewMockUsecase(ctrl)
 			tc.buildStubs(trUsecase)
 
-			handler := NewHandler(slog.Default(), trUsecase)
+			handler := newTestHandler(ctrl, trUsecase)
 
 			e := echo.New()
 			req := httptest.NewRequest(http.MethodPost, "/users/1/transactions", bytes.NewReader(tc.body))
@@ -156,3 +264,1143 @@ func TestTransactionHandler_Process(t *testing.T) {
 		})
 	}
 }
+
+// TestTransactionHandler_ProcessBatch tests the transaction handler process batch method.
+func TestTransactionHandler_ProcessBatch(t *testing.T) {
+	testCases := []struct {
+		name         string
+		body         []byte
+		buildStubs   func(trUsecase *mocks.MockUsecase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "OK",
+			body: []byte(`{"transactions":[{"transactionId":"1","state":"win","amount":1,"currency":"EUR","userId":"1"},{"transactionId":"2","state":"lost","amount":1,"currency":"EUR","userId":"2"}]}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().ProcessBatch(gomock.Any(), []*model.Transaction{
+					{TransactionID: "1", State: "win", Amount: 100, Currency: "EUR", UserID: "1", SourceType: "game"},
+					{TransactionID: "2", State: "lost", Amount: 100, Currency: "EUR", UserID: "2", SourceType: "game"},
+				}, false).Return([]*model.BatchResult{
+					{TransactionID: "1", Status: model.BatchStatusProcessed},
+					{TransactionID: "2", Status: model.BatchStatusFailed, Err: model.ErrorInsufficientBalance},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"results":[
+				{"transactionId":"1","status":"processed"},
+				{"transactionId":"2","status":"failed","error":{"code":403,"message":"insufficient balance error"}}
+			]}`,
+		},
+		{
+			name: "Invalid transaction fails on its own",
+			body: []byte(`{"transactions":[{"transactionId":"1","state":"won","amount":1,"currency":"EUR","userId":"1"},{"transactionId":"2","state":"win","amount":1,"currency":"EUR","userId":"1"}]}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().ProcessBatch(gomock.Any(), gomock.Len(1), false).Return([]*model.BatchResult{
+					{TransactionID: "2", Status: model.BatchStatusDuplicate},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"results":[
+				{"transactionId":"1","status":"failed","error":{"code":400,"message":"Value of the State field must be one of 'lost win'"}},
+				{"transactionId":"2","status":"duplicate"}
+			]}`,
+		},
+		{
+			name:         "Invalid transaction of an atomic batch",
+			body:         []byte(`{"atomic":true,"transactions":[{"transactionId":"1","state":"win","amount":1,"currency":"EUR","userId":"1"},{"transactionId":"2","state":"win","amount":1,"currency":"EUR"}]}`),
+			buildStubs:   func(trUsecase *mocks.MockUsecase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"code":400,"message":"Transaction 1: UserID field is required"}`,
+		},
+		{
+			name:         "Empty batch",
+			body:         []byte(`{"transactions":[]}`),
+			buildStubs:   func(trUsecase *mocks.MockUsecase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"code":400,"message":"Transactions field is required"}`,
+		},
+		{
+			name:         "Too large batch",
+			body:         []byte(`{"transactions":[{},{},{},{}]}`),
+			buildStubs:   func(trUsecase *mocks.MockUsecase) {},
+			expectedCode: http.StatusRequestEntityTooLarge,
+			expectedBody: `{"code":413,"message":"A batch must have at most 3 transactions"}`,
+		},
+		{
+			name: "Internal server error",
+			body: []byte(`{"atomic":true,"transactions":[{"transactionId":"1","state":"win","amount":1,"currency":"EUR","userId":"1"}]}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().ProcessBatch(gomock.Any(), gomock.Any(), true).Return(nil, fmt.Errorf("unexpected error"))
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"code":500,"message":"internal server error"}`,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/transactions:batch", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(SourceType, "game")
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.ProcessBatch(c)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedCode, rec.Code)
+			require.JSONEq(t, tc.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+// TestTransactionHandler_History tests the transaction handler history method.
+func TestTransactionHandler_History(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(trUsecase *mocks.MockUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name:  "OK",
+			query: "state=win&sourceType=game&cancelled=false&from=2024-01-01T00:00:00Z&limit=10&cursor=" + model.EncodeCursor(5),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().GetTransactions(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, filter *model.TransactionFilter) (*model.TransactionPage, error) {
+						require.Equal(t, "1", filter.UserID)
+						require.Equal(t, "win", filter.State)
+						require.Equal(t, "game", filter.SourceType)
+						require.Equal(t, false, *filter.Cancelled)
+						require.Equal(t, 2024, filter.From.Year())
+						require.Nil(t, filter.To)
+						require.Equal(t, int64(5), filter.Cursor)
+						require.Equal(t, 10, filter.Limit)
+
+						return &model.TransactionPage{}, nil
+					})
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid state",
+			query:      "state=won",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the State field must be one of 'lost win'",
+			},
+		},
+		{
+			name:       "Invalid cancelled",
+			query:      "cancelled=maybe",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the cancelled param must be a boolean",
+			},
+		},
+		{
+			name:       "Invalid from",
+			query:      "from=yesterday",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the from param must be a RFC3339 time",
+			},
+		},
+		{
+			name:       "Invalid cursor",
+			query:      "cursor=abc",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the cursor param is not valid",
+			},
+		},
+		{
+			name:       "Too big limit",
+			query:      "limit=1000",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the Limit field must be at most 100",
+			},
+		},
+		{
+			name:  "User not found",
+			query: "",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().GetTransactions(gomock.Any(), gomock.Any()).Return(nil, model.ErrorUserNotFound)
+			},
+			expectedError: getError(model.ErrorUserNotFound),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/users/1/transactions?"+tc.query, nil)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			err := handler.History(c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+// TestTransactionHandler_GrantBonus tests the transaction handler grant bonus method.
+func TestTransactionHandler_GrantBonus(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(trUsecase *mocks.MockUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name: "OK",
+			body: []byte(`{"currency":"EUR","amount":5,"wagering":20}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().GrantBonus(gomock.Any(), &model.Bonus{UserID: "1", Currency: "EUR", Amount: 500, Wagering: 2000}).Return(nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:       "Invalid request body",
+			body:       []byte(`{"currency":"EUR","amount":"5","wagering":20}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: model.ErrorBadRequest,
+			},
+		},
+		{
+			name:       "Missing wagering",
+			body:       []byte(`{"currency":"EUR","amount":5}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Wagering field is required",
+			},
+		},
+		{
+			name:       "Invalid currency",
+			body:       []byte(`{"currency":"XYZ","amount":5,"wagering":20}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the Currency field must be an ISO 4217 currency code",
+			},
+		},
+		{
+			name: "Wallet not found",
+			body: []byte(`{"currency":"USD","amount":5,"wagering":20}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().GrantBonus(gomock.Any(), gomock.Any()).Return(model.ErrorWalletNotFound)
+			},
+			expectedError: getError(model.ErrorWalletNotFound),
+		},
+		{
+			name: "Suspended user",
+			body: []byte(`{"currency":"EUR","amount":5,"wagering":20}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().GrantBonus(gomock.Any(), gomock.Any()).Return(model.ErrorUserSuspended)
+			},
+			expectedError: getError(model.ErrorUserSuspended),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/1/bonuses", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			err := handler.GrantBonus(c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+// TestTransactionHandler_StartPayment tests the transaction handler deposit and withdraw methods.
+func TestTransactionHandler_StartPayment(t *testing.T) {
+	testCases := []struct {
+		name          string
+		kind          string
+		body          []byte
+		buildStubs    func(trUsecase *mocks.MockUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name: "Deposit",
+			kind: model.PaymentKindDeposit,
+			body: []byte(`{"transactionId":"1","amount":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().StartPayment(gomock.Any(), &model.Payment{TransactionID: "1", Amount: 500, Currency: "EUR", UserID: "1", Kind: model.PaymentKindDeposit}).
+					Return(&model.TransactionView{ID: "2", State: model.StateDepositPending}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "Withdrawal",
+			kind: model.PaymentKindWithdrawal,
+			body: []byte(`{"transactionId":"1","amount":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().StartPayment(gomock.Any(), &model.Payment{TransactionID: "1", Amount: 500, Currency: "EUR", UserID: "1", Kind: model.PaymentKindWithdrawal}).
+					Return(&model.TransactionView{ID: "2", State: model.StateWithdrawalPending}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:       "Missing transaction id",
+			kind:       model.PaymentKindDeposit,
+			body:       []byte(`{"amount":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "TransactionID field is required",
+			},
+		},
+		{
+			name: "Withdrawal over the available balance",
+			kind: model.PaymentKindWithdrawal,
+			body: []byte(`{"transactionId":"1","amount":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().StartPayment(gomock.Any(), gomock.Any()).Return(nil, model.ErrorInsufficientBalance)
+			},
+			expectedError: getError(model.ErrorInsufficientBalance),
+		},
+		{
+			name: "Deposit limit exceeded",
+			kind: model.PaymentKindDeposit,
+			body: []byte(`{"transactionId":"1","amount":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().StartPayment(gomock.Any(), gomock.Any()).Return(nil, model.ErrorDepositLimitExceeded)
+			},
+			expectedError: model.Error{Code: http.StatusTooManyRequests, Message: model.ErrorDepositLimitExceeded.Error()},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/users/1/"+tc.kind+"s", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			var err error
+			if tc.kind == model.PaymentKindDeposit {
+				err = handler.Deposit(c)
+			} else {
+				err = handler.Withdraw(c)
+			}
+
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+// TestTransactionHandler_CompletePayment tests the transaction handler confirm and fail payment methods.
+func TestTransactionHandler_CompletePayment(t *testing.T) {
+	testCases := []struct {
+		name          string
+		confirmed     bool
+		buildStubs    func(trUsecase *mocks.MockUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name:      "Confirmed",
+			confirmed: true,
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().ConfirmPayment(gomock.Any(), "1").Return(&model.TransactionView{ID: "1", State: model.StateDepositConfirmed}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "Failed",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().FailPayment(gomock.Any(), "1").Return(&model.TransactionView{ID: "1", State: model.StateDepositFailed}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:      "Not pending",
+			confirmed: true,
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().ConfirmPayment(gomock.Any(), "1").Return(nil, model.ErrorPaymentNotPending)
+			},
+			expectedError: model.Error{Code: http.StatusConflict, Message: model.ErrorPaymentNotPending.Error()},
+		},
+		{
+			name: "Not found",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().FailPayment(gomock.Any(), "1").Return(nil, model.ErrorTransactionNotFound)
+			},
+			expectedError: model.Error{Code: http.StatusNotFound, Message: model.ErrorTransactionNotFound.Error()},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/payments/1/confirm", nil)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			var err error
+			if tc.confirmed {
+				err = handler.ConfirmPayment(c)
+			} else {
+				err = handler.FailPayment(c)
+			}
+
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+func TestTransactionHandler_PlaceBet(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(trUsecase *mocks.MockUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name: "OK",
+			body: []byte(`{"betId":"round-1","stake":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().PlaceBet(gomock.Any(), &model.PlaceBet{BetID: "round-1", Stake: 500, Currency: "EUR", UserID: "1"}).
+					Return(&model.Bet{ID: "2", State: model.BetStatePlaced}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:       "Missing stake",
+			body:       []byte(`{"betId":"round-1","currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Stake field is required",
+			},
+		},
+		{
+			name: "BetID with another payload",
+			body: []byte(`{"betId":"round-1","stake":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().PlaceBet(gomock.Any(), gomock.Any()).Return(nil, model.ErrorBetAlreadyExists)
+			},
+			expectedError: model.Error{Code: http.StatusConflict, Message: model.ErrorBetAlreadyExists.Error()},
+		},
+		{
+			name: "Stake over the available balance",
+			body: []byte(`{"betId":"round-1","stake":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().PlaceBet(gomock.Any(), gomock.Any()).Return(nil, model.ErrorInsufficientBalance)
+			},
+			expectedError: getError(model.ErrorInsufficientBalance),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/users/1/bets", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			err := handler.PlaceBet(c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+// TestTransactionHandler_CloseBet tests the transaction handler settle and void bet methods.
+func TestTransactionHandler_CloseBet(t *testing.T) {
+	testCases := []struct {
+		name          string
+		void          bool
+		body          []byte
+		buildStubs    func(trUsecase *mocks.MockUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name: "Win",
+			body: []byte(`{"outcome":"win","amount":7.5}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().SettleBet(gomock.Any(), &model.SettleBet{ID: "1", Outcome: model.BetOutcomeWin, Amount: 750}).
+					Return(&model.Bet{ID: "1", State: model.BetStateWon}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:       "Unknown outcome",
+			body:       []byte(`{"outcome":"draw"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the Outcome field must be one of 'win loss'",
+			},
+		},
+		{
+			name: "Already settled",
+			body: []byte(`{"outcome":"loss"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().SettleBet(gomock.Any(), gomock.Any()).Return(nil, model.ErrorBetNotPlaced)
+			},
+			expectedError: model.Error{Code: http.StatusConflict, Message: model.ErrorBetNotPlaced.Error()},
+		},
+		{
+			name: "Void",
+			void: true,
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().VoidBet(gomock.Any(), "1").Return(&model.Bet{ID: "1", State: model.BetStateVoid}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "Void of an unknown bet",
+			void: true,
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().VoidBet(gomock.Any(), "1").Return(nil, model.ErrorBetNotFound)
+			},
+			expectedError: model.Error{Code: http.StatusNotFound, Message: model.ErrorBetNotFound.Error()},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/bets/1/settle", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			var err error
+			if tc.void {
+				err = handler.VoidBet(c)
+			} else {
+				err = handler.SettleBet(c)
+			}
+
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+func TestTransactionHandler_Adjust(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(trUsecase *mocks.MockUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name: "OK",
+			body: []byte(`{"transactionId":"1","direction":"credit","amount":5,"currency":"EUR","reason":"lost win","operatorId":"agent-7"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Adjust(gomock.Any(), &model.Adjustment{
+					TransactionID: "1",
+					Direction:     model.DirectionCredit,
+					Amount:        500,
+					Currency:      "EUR",
+					Reason:        "lost win",
+					OperatorID:    "agent-7",
+					UserID:        "1",
+				}).Return(&model.TransactionView{ID: "2", State: model.StateAdjustmentCredit}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:       "Missing reason",
+			body:       []byte(`{"transactionId":"1","direction":"credit","amount":5,"currency":"EUR","operatorId":"agent-7"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Reason field is required",
+			},
+		},
+		{
+			name:       "Missing operator",
+			body:       []byte(`{"transactionId":"1","direction":"debit","amount":5,"currency":"EUR","reason":"duplicate win"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "OperatorID field is required",
+			},
+		},
+		{
+			name: "Debit over the available balance",
+			body: []byte(`{"transactionId":"1","direction":"debit","amount":5,"currency":"EUR","reason":"duplicate win","operatorId":"agent-7"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Adjust(gomock.Any(), gomock.Any()).Return(nil, model.ErrorInsufficientBalance)
+			},
+			expectedError: getError(model.ErrorInsufficientBalance),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/1/adjustments", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			err := handler.Adjust(c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+func TestTransactionHandler_Transfer(t *testing.T) {
+	testCases := []struct {
+		name          string
+		key           string
+		body          []byte
+		buildStubs    func(trUsecase *mocks.MockUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name: "OK",
+			key:  "t-1",
+			body: []byte(`{"fromUserId":"1","toUserId":"2","amount":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Transfer(gomock.Any(), &model.Transfer{
+					IdempotencyKey: "t-1",
+					FromUserID:     "1",
+					ToUserID:       "2",
+					Amount:         500,
+					Currency:       "EUR",
+				}).Return(&model.TransferView{ID: "1", IdempotencyKey: "t-1"}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:       "Missing idempotency key",
+			body:       []byte(`{"fromUserId":"1","toUserId":"2","amount":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "IdempotencyKey field is required",
+			},
+		},
+		{
+			name:       "Transfer to the sender",
+			key:        "t-1",
+			body:       []byte(`{"fromUserId":"1","toUserId":"1","amount":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the ToUserID field must differ from the FromUserID field",
+			},
+		},
+		{
+			name: "Idempotency key of another transfer",
+			key:  "t-1",
+			body: []byte(`{"fromUserId":"1","toUserId":"2","amount":5,"currency":"EUR"}`),
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Transfer(gomock.Any(), gomock.Any()).Return(nil, model.ErrorTransferAlreadyExists)
+			},
+			expectedError: getError(model.ErrorTransferAlreadyExists),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(IdempotencyKey, tc.key)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.Transfer(c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+func TestTransactionHandler_Import(t *testing.T) {
+	testCases := []struct {
+		name         string
+		buildStubs   func(trUsecase *mocks.MockUsecase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "OK",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Import(gomock.Any(), gomock.Any(), "server", gomock.Any()).DoAndReturn(func(_ context.Context, _ io.Reader, _ string, reporter transaction.ImportReporter) (*model.ImportProgress, error) {
+					reporter.Failure(&model.ImportFailure{Line: 1, Err: fmt.Errorf("failed to decode the line: %w", model.ErrorInvalidLine)})
+					reporter.Failure(&model.ImportFailure{Line: 2, TransactionID: "2", Err: model.ErrorInsufficientBalance})
+					reporter.Progress(model.ImportProgress{Lines: 2, Failed: 2})
+
+					progress := &model.ImportProgress{Lines: 3, Processed: 1, Failed: 2, Done: true}
+					reporter.Progress(*progress)
+
+					return progress, nil
+				})
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"line":1,"error":{"code":400,"message":"line is not a valid json transaction"}}
+{"line":2,"transactionId":"2","error":{"code":403,"message":"insufficient balance error"}}
+{"lines":2,"processed":0,"duplicates":0,"failed":2,"done":false}
+{"lines":3,"processed":1,"duplicates":0,"failed":2,"done":true}
+`,
+		},
+		{
+			name: "Import stopped after the first lines",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Import(gomock.Any(), gomock.Any(), "server", gomock.Any()).DoAndReturn(func(_ context.Context, _ io.Reader, _ string, reporter transaction.ImportReporter) (*model.ImportProgress, error) {
+					reporter.Progress(model.ImportProgress{Lines: 2, Processed: 2})
+
+					return &model.ImportProgress{Lines: 2, Processed: 2}, model.ErrorInvalidLine
+				})
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"lines":2,"processed":2,"duplicates":0,"failed":0,"done":false}
+{"lines":2,"processed":2,"duplicates":0,"failed":0,"done":false,"error":{"code":400,"message":"line is not a valid json transaction"}}
+`,
+		},
+		{
+			name: "Import failed before the first line",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Import(gomock.Any(), gomock.Any(), "server", gomock.Any()).Return(nil, fmt.Errorf("unexpected error"))
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"code":500,"message":"internal server error"}
+`,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/transactions:import", bytes.NewReader(nil))
+			req.Header.Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+			req.Header.Set(SourceType, "server")
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.Import(c)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedCode, rec.Code)
+			require.Equal(t, tc.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+// TestTransactionHandler_ImportLargeBody runs the import over a real connection, the response starts
+// with the first failed line while most of the body is still unread. net/http discards an unread body
+// below 256KB and stops reading one above it when the headers are sent, unless full duplex is enabled.
+func TestTransactionHandler_ImportLargeBody(t *testing.T) {
+	for _, lines := range []int{2000, 6000} {
+		t.Run(fmt.Sprintf("%d lines", lines), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			trUsecase.EXPECT().Import(gomock.Any(), gomock.Any(), "server", gomock.Any()).DoAndReturn(func(_ context.Context, r io.Reader, _ string, reporter transaction.ImportReporter) (*model.ImportProgress, error) {
+				progress := &model.ImportProgress{}
+
+				scanner := bufio.NewScanner(r)
+				for scanner.Scan() {
+					progress.Lines++
+
+					if progress.Lines == 1 {
+						progress.Failed++
+						reporter.Failure(&model.ImportFailure{Line: 1, Err: model.ErrorInsufficientBalance})
+
+						continue
+					}
+
+					progress.Processed++
+
+					if progress.Lines%1000 == 0 {
+						reporter.Progress(*progress)
+					}
+				}
+
+				if err := scanner.Err(); err != nil {
+					return progress, err
+				}
+
+				progress.Done = true
+				reporter.Progress(*progress)
+
+				return progress, nil
+			})
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			e.POST("/admin/transactions\\:import", handler.Import)
+
+			srv := httptest.NewServer(e)
+			defer srv.Close()
+
+			var body bytes.Buffer
+			for i := 1; i <= lines; i++ {
+				fmt.Fprintf(&body, `{"transactionId":"%d","userId":"00000000-0000-0000-0000-000000000001","state":"win","amount":1.5,"currency":"EUR"}`+"\n", i)
+			}
+
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/admin/transactions:import", &body)
+			require.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+			req.Header.Set(SourceType, "server")
+
+			res, err := srv.Client().Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			data, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			out := strings.Split(strings.TrimSpace(string(data)), "\n")
+			require.Equal(t, fmt.Sprintf(`{"lines":%d,"processed":%d,"duplicates":0,"failed":1,"done":true}`, lines, lines-1), out[len(out)-1])
+		})
+	}
+}
+
+func TestTransactionHandler_Export(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	transactions := []*model.TransactionView{
+		{ID: "1", TransactionID: "a", UserID: "u", SourceType: "game", State: "win", Amount: 1050, Currency: "EUR", CreatedAt: createdAt},
+		{ID: "2", TransactionID: "b", UserID: "u", SourceType: "game", State: "lost", Amount: 500, Currency: "EUR", CreatedAt: createdAt, Cancelled: true, CancelledAt: &createdAt},
+	}
+
+	export := func(_ context.Context, _ *model.ExportFilter, fn func(*model.TransactionView) error) error {
+		for _, tr := range transactions {
+			err := fn(tr)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	testCases := []struct {
+		name         string
+		query        string
+		buildStubs   func(trUsecase *mocks.MockUsecase)
+		expectedCode int
+		expectedType string
+		expectedBody string
+	}{
+		{
+			name:  "NDJSON",
+			query: "userId=00000000-0000-0000-0000-000000000001&from=2024-01-01T00:00:00Z",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+				trUsecase.EXPECT().Export(gomock.Any(), &model.ExportFilter{
+					UserID: "00000000-0000-0000-0000-000000000001",
+					From:   &from,
+					Format: model.ExportFormatNDJSON,
+				}, gomock.Any()).DoAndReturn(export)
+			},
+			expectedCode: http.StatusOK,
+			expectedType: MIMEApplicationNDJSON,
+			expectedBody: `{"id":"1","transactionId":"a","userId":"u","sourceType":"game","state":"win","amount":10.50,"currency":"EUR","bonusAmount":0.00,"createdAt":"2024-01-02T03:04:05Z","cancelled":false,"cancelledAt":null}
+{"id":"2","transactionId":"b","userId":"u","sourceType":"game","state":"lost","amount":5.00,"currency":"EUR","bonusAmount":0.00,"createdAt":"2024-01-02T03:04:05Z","cancelled":true,"cancelledAt":"2024-01-02T03:04:05Z"}
+`,
+		},
+		{
+			name:  "CSV",
+			query: "format=csv",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Export(gomock.Any(), &model.ExportFilter{Format: model.ExportFormatCSV}, gomock.Any()).DoAndReturn(export)
+			},
+			expectedCode: http.StatusOK,
+			expectedType: MIMETextCSV,
+			expectedBody: `id,transactionId,userId,sourceType,state,amount,currency,bonusAmount,createdAt,cancelled,cancelledAt,operatorId,reason
+1,a,u,game,win,10.50,EUR,0.00,2024-01-02T03:04:05Z,false,,,
+2,b,u,game,lost,5.00,EUR,0.00,2024-01-02T03:04:05Z,true,2024-01-02T03:04:05Z,,
+`,
+		},
+		{
+			name:  "Empty CSV",
+			query: "format=csv",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedType: MIMETextCSV,
+			expectedBody: "id,transactionId,userId,sourceType,state,amount,currency,bonusAmount,createdAt,cancelled,cancelledAt,operatorId,reason\n",
+		},
+		{
+			name:         "Invalid format",
+			query:        "format=xml",
+			buildStubs:   func(trUsecase *mocks.MockUsecase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedType: echo.MIMEApplicationJSON,
+			expectedBody: `{"code":400,"message":"Value of the Format field must be one of 'ndjson csv'"}` + "\n",
+		},
+		{
+			name:         "Invalid user",
+			query:        "userId=1",
+			buildStubs:   func(trUsecase *mocks.MockUsecase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedType: echo.MIMEApplicationJSON,
+			expectedBody: `{"code":400,"message":"Value of the UserID field must be a UUID"}` + "\n",
+		},
+		{
+			name:  "Export failed before the first transaction",
+			query: "format=csv",
+			buildStubs: func(trUsecase *mocks.MockUsecase) {
+				trUsecase.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("unexpected error"))
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedType: echo.MIMEApplicationJSON,
+			expectedBody: `{"code":500,"message":"internal server error"}` + "\n",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trUsecase := mocks.NewMockUsecase(ctrl)
+			tc.buildStubs(trUsecase)
+
+			handler := newTestHandler(ctrl, trUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/admin/transactions:export?"+tc.query, nil)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.Export(c)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedCode, rec.Code)
+			require.Equal(t, true, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), tc.expectedType))
+			require.Equal(t, tc.expectedBody, rec.Body.String())
+		})
+	}
+}
@@ -37,32 +37,18 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // CancelTransaction mocks base method.
-func (m *MockRepository) CancelTransaction(ctx context.Context, id string) error {
+func (m *MockRepository) CancelTransaction(tx *sql.Tx, ctx context.Context, id string) (*model.TransactionDao, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CancelTransaction", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// CancelTransaction indicates an expected call of CancelTransaction.
-func (mr *MockRepositoryMockRecorder) CancelTransaction(ctx, id interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransaction", reflect.TypeOf((*MockRepository)(nil).CancelTransaction), ctx, id)
-}
-
-// CheckExistance mocks base method.
-func (m *MockRepository) CheckExistance(ctx context.Context, id string) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckExistance", ctx, id)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "CancelTransaction", tx, ctx, id)
+	ret0, _ := ret[0].(*model.TransactionDao)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CheckExistance indicates an expected call of CheckExistance.
-func (mr *MockRepositoryMockRecorder) CheckExistance(ctx, id interface{}) *gomock.Call {
+// CancelTransaction indicates an expected call of CancelTransaction.
+func (mr *MockRepositoryMockRecorder) CancelTransaction(tx, ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckExistance", reflect.TypeOf((*MockRepository)(nil).CheckExistance), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransaction", reflect.TypeOf((*MockRepository)(nil).CancelTransaction), tx, ctx, id)
 }
 
 // CreateTransaction mocks base method.
@@ -79,6 +65,20 @@ func (mr *MockRepositoryMockRecorder) CreateTransaction(tx, ctx, tr interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockRepository)(nil).CreateTransaction), tx, ctx, tr)
 }
 
+// ExportTransactions mocks base method.
+func (m *MockRepository) ExportTransactions(ctx context.Context, filter *model.ExportFilter, fn func(*model.TransactionDao) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTransactions", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportTransactions indicates an expected call of ExportTransactions.
+func (mr *MockRepositoryMockRecorder) ExportTransactions(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTransactions", reflect.TypeOf((*MockRepository)(nil).ExportTransactions), ctx, filter, fn)
+}
+
 // GetLatestOddAndUncancelledTransactions mocks base method.
 func (m *MockRepository) GetLatestOddAndUncancelledTransactions(ctx context.Context, limit int) ([]*model.TransactionDao, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +94,65 @@ func (mr *MockRepositoryMockRecorder) GetLatestOddAndUncancelledTransactions(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestOddAndUncancelledTransactions", reflect.TypeOf((*MockRepository)(nil).GetLatestOddAndUncancelledTransactions), ctx, limit)
 }
 
+// GetTransactionByTransactionID mocks base method.
+func (m *MockRepository) GetTransactionByTransactionID(tx *sql.Tx, ctx context.Context, transactionID string) (*model.TransactionDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionByTransactionID", tx, ctx, transactionID)
+	ret0, _ := ret[0].(*model.TransactionDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionByTransactionID indicates an expected call of GetTransactionByTransactionID.
+func (mr *MockRepositoryMockRecorder) GetTransactionByTransactionID(tx, ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByTransactionID", reflect.TypeOf((*MockRepository)(nil).GetTransactionByTransactionID), tx, ctx, transactionID)
+}
+
+// GetTransactionForUpdate mocks base method.
+func (m *MockRepository) GetTransactionForUpdate(tx *sql.Tx, ctx context.Context, id string) (*model.TransactionDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionForUpdate", tx, ctx, id)
+	ret0, _ := ret[0].(*model.TransactionDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionForUpdate indicates an expected call of GetTransactionForUpdate.
+func (mr *MockRepositoryMockRecorder) GetTransactionForUpdate(tx, ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionForUpdate", reflect.TypeOf((*MockRepository)(nil).GetTransactionForUpdate), tx, ctx, id)
+}
+
+// GetUserTransactions mocks base method.
+func (m *MockRepository) GetUserTransactions(ctx context.Context, filter *model.TransactionFilter) ([]*model.TransactionDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTransactions", ctx, filter)
+	ret0, _ := ret[0].([]*model.TransactionDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTransactions indicates an expected call of GetUserTransactions.
+func (mr *MockRepositoryMockRecorder) GetUserTransactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTransactions", reflect.TypeOf((*MockRepository)(nil).GetUserTransactions), ctx, filter)
+}
+
+// UpdateTransactionState mocks base method.
+func (m *MockRepository) UpdateTransactionState(tx *sql.Tx, ctx context.Context, id, state string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransactionState", tx, ctx, id, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTransactionState indicates an expected call of UpdateTransactionState.
+func (mr *MockRepositoryMockRecorder) UpdateTransactionState(tx, ctx, id, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionState", reflect.TypeOf((*MockRepository)(nil).UpdateTransactionState), tx, ctx, id, state)
+}
+
 // MockDatabase is a mock of Database interface.
 type MockDatabase struct {
 	ctrl     *gomock.Controller
@@ -159,3 +218,55 @@ func (mr *MockDatabaseMockRecorder) Rollback(tx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockDatabase)(nil).Rollback), tx)
 }
+
+// MockElector is a mock of Elector interface.
+type MockElector struct {
+	ctrl     *gomock.Controller
+	recorder *MockElectorMockRecorder
+}
+
+// MockElectorMockRecorder is the mock recorder for MockElector.
+type MockElectorMockRecorder struct {
+	mock *MockElector
+}
+
+// NewMockElector creates a new mock instance.
+func NewMockElector(ctrl *gomock.Controller) *MockElector {
+	mock := &MockElector{ctrl: ctrl}
+	mock.recorder = &MockElectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockElector) EXPECT() *MockElectorMockRecorder {
+	return m.recorder
+}
+
+// IsLeader mocks base method.
+func (m *MockElector) IsLeader(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLeader", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsLeader indicates an expected call of IsLeader.
+func (mr *MockElectorMockRecorder) IsLeader(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLeader", reflect.TypeOf((*MockElector)(nil).IsLeader), ctx)
+}
+
+// Resign mocks base method.
+func (m *MockElector) Resign(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resign", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resign indicates an expected call of Resign.
+func (mr *MockElectorMockRecorder) Resign(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resign", reflect.TypeOf((*MockElector)(nil).Resign), ctx)
+}
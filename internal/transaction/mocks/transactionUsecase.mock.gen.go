@@ -6,10 +6,12 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/ttagiyeva/entain/internal/model"
+	transaction "github.com/ttagiyeva/entain/internal/transaction"
 )
 
 // MockUsecase is a mock of Usecase interface.
@@ -35,6 +37,178 @@ func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
 	return m.recorder
 }
 
+// Adjust mocks base method.
+func (m *MockUsecase) Adjust(ctx context.Context, a *model.Adjustment) (*model.TransactionView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", ctx, a)
+	ret0, _ := ret[0].(*model.TransactionView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Adjust indicates an expected call of Adjust.
+func (mr *MockUsecaseMockRecorder) Adjust(ctx, a interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockUsecase)(nil).Adjust), ctx, a)
+}
+
+// Cancel mocks base method.
+func (m *MockUsecase) Cancel(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockUsecaseMockRecorder) Cancel(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockUsecase)(nil).Cancel), ctx, id)
+}
+
+// ConfirmPayment mocks base method.
+func (m *MockUsecase) ConfirmPayment(ctx context.Context, id string) (*model.TransactionView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmPayment", ctx, id)
+	ret0, _ := ret[0].(*model.TransactionView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmPayment indicates an expected call of ConfirmPayment.
+func (mr *MockUsecaseMockRecorder) ConfirmPayment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmPayment", reflect.TypeOf((*MockUsecase)(nil).ConfirmPayment), ctx, id)
+}
+
+// ExpireBets mocks base method.
+func (m *MockUsecase) ExpireBets(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ExpireBets", ctx)
+}
+
+// ExpireBets indicates an expected call of ExpireBets.
+func (mr *MockUsecaseMockRecorder) ExpireBets(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireBets", reflect.TypeOf((*MockUsecase)(nil).ExpireBets), ctx)
+}
+
+// Export mocks base method.
+func (m *MockUsecase) Export(ctx context.Context, filter *model.ExportFilter, fn func(*model.TransactionView) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockUsecaseMockRecorder) Export(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockUsecase)(nil).Export), ctx, filter, fn)
+}
+
+// FailPayment mocks base method.
+func (m *MockUsecase) FailPayment(ctx context.Context, id string) (*model.TransactionView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailPayment", ctx, id)
+	ret0, _ := ret[0].(*model.TransactionView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailPayment indicates an expected call of FailPayment.
+func (mr *MockUsecaseMockRecorder) FailPayment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailPayment", reflect.TypeOf((*MockUsecase)(nil).FailPayment), ctx, id)
+}
+
+// GetTransactions mocks base method.
+func (m *MockUsecase) GetTransactions(ctx context.Context, filter *model.TransactionFilter) (*model.TransactionPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactions", ctx, filter)
+	ret0, _ := ret[0].(*model.TransactionPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactions indicates an expected call of GetTransactions.
+func (mr *MockUsecaseMockRecorder) GetTransactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockUsecase)(nil).GetTransactions), ctx, filter)
+}
+
+// GrantBonus mocks base method.
+func (m *MockUsecase) GrantBonus(ctx context.Context, bonus *model.Bonus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantBonus", ctx, bonus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantBonus indicates an expected call of GrantBonus.
+func (mr *MockUsecaseMockRecorder) GrantBonus(ctx, bonus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantBonus", reflect.TypeOf((*MockUsecase)(nil).GrantBonus), ctx, bonus)
+}
+
+// Import mocks base method.
+func (m *MockUsecase) Import(ctx context.Context, r io.Reader, sourceType string, reporter transaction.ImportReporter) (*model.ImportProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, r, sourceType, reporter)
+	ret0, _ := ret[0].(*model.ImportProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockUsecaseMockRecorder) Import(ctx, r, sourceType, reporter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockUsecase)(nil).Import), ctx, r, sourceType, reporter)
+}
+
+// IsBetExpiryRunning mocks base method.
+func (m *MockUsecase) IsBetExpiryRunning() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBetExpiryRunning")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsBetExpiryRunning indicates an expected call of IsBetExpiryRunning.
+func (mr *MockUsecaseMockRecorder) IsBetExpiryRunning() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBetExpiryRunning", reflect.TypeOf((*MockUsecase)(nil).IsBetExpiryRunning))
+}
+
+// IsPostProcessRunning mocks base method.
+func (m *MockUsecase) IsPostProcessRunning() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPostProcessRunning")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsPostProcessRunning indicates an expected call of IsPostProcessRunning.
+func (mr *MockUsecaseMockRecorder) IsPostProcessRunning() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPostProcessRunning", reflect.TypeOf((*MockUsecase)(nil).IsPostProcessRunning))
+}
+
+// PlaceBet mocks base method.
+func (m *MockUsecase) PlaceBet(ctx context.Context, req *model.PlaceBet) (*model.Bet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceBet", ctx, req)
+	ret0, _ := ret[0].(*model.Bet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceBet indicates an expected call of PlaceBet.
+func (mr *MockUsecaseMockRecorder) PlaceBet(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceBet", reflect.TypeOf((*MockUsecase)(nil).PlaceBet), ctx, req)
+}
+
 // PostProcess mocks base method.
 func (m *MockUsecase) PostProcess(ctx context.Context) {
 	m.ctrl.T.Helper()
@@ -60,3 +234,125 @@ func (mr *MockUsecaseMockRecorder) Process(arg0, arg1 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockUsecase)(nil).Process), arg0, arg1)
 }
+
+// ProcessBatch mocks base method.
+func (m *MockUsecase) ProcessBatch(ctx context.Context, trs []*model.Transaction, atomic bool) ([]*model.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessBatch", ctx, trs, atomic)
+	ret0, _ := ret[0].([]*model.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessBatch indicates an expected call of ProcessBatch.
+func (mr *MockUsecaseMockRecorder) ProcessBatch(ctx, trs, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessBatch", reflect.TypeOf((*MockUsecase)(nil).ProcessBatch), ctx, trs, atomic)
+}
+
+// SettleBet mocks base method.
+func (m *MockUsecase) SettleBet(ctx context.Context, req *model.SettleBet) (*model.Bet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleBet", ctx, req)
+	ret0, _ := ret[0].(*model.Bet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SettleBet indicates an expected call of SettleBet.
+func (mr *MockUsecaseMockRecorder) SettleBet(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleBet", reflect.TypeOf((*MockUsecase)(nil).SettleBet), ctx, req)
+}
+
+// StartPayment mocks base method.
+func (m *MockUsecase) StartPayment(ctx context.Context, p *model.Payment) (*model.TransactionView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartPayment", ctx, p)
+	ret0, _ := ret[0].(*model.TransactionView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartPayment indicates an expected call of StartPayment.
+func (mr *MockUsecaseMockRecorder) StartPayment(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartPayment", reflect.TypeOf((*MockUsecase)(nil).StartPayment), ctx, p)
+}
+
+// Transfer mocks base method.
+func (m *MockUsecase) Transfer(ctx context.Context, req *model.Transfer) (*model.TransferView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, req)
+	ret0, _ := ret[0].(*model.TransferView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockUsecaseMockRecorder) Transfer(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockUsecase)(nil).Transfer), ctx, req)
+}
+
+// VoidBet mocks base method.
+func (m *MockUsecase) VoidBet(ctx context.Context, id string) (*model.Bet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidBet", ctx, id)
+	ret0, _ := ret[0].(*model.Bet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidBet indicates an expected call of VoidBet.
+func (mr *MockUsecaseMockRecorder) VoidBet(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidBet", reflect.TypeOf((*MockUsecase)(nil).VoidBet), ctx, id)
+}
+
+// MockImportReporter is a mock of ImportReporter interface.
+type MockImportReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportReporterMockRecorder
+}
+
+// MockImportReporterMockRecorder is the mock recorder for MockImportReporter.
+type MockImportReporterMockRecorder struct {
+	mock *MockImportReporter
+}
+
+// NewMockImportReporter creates a new mock instance.
+func NewMockImportReporter(ctrl *gomock.Controller) *MockImportReporter {
+	mock := &MockImportReporter{ctrl: ctrl}
+	mock.recorder = &MockImportReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportReporter) EXPECT() *MockImportReporterMockRecorder {
+	return m.recorder
+}
+
+// Failure mocks base method.
+func (m *MockImportReporter) Failure(f *model.ImportFailure) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Failure", f)
+}
+
+// Failure indicates an expected call of Failure.
+func (mr *MockImportReporterMockRecorder) Failure(f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Failure", reflect.TypeOf((*MockImportReporter)(nil).Failure), f)
+}
+
+// Progress mocks base method.
+func (m *MockImportReporter) Progress(p model.ImportProgress) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Progress", p)
+}
+
+// Progress indicates an expected call of Progress.
+func (mr *MockImportReporterMockRecorder) Progress(p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Progress", reflect.TypeOf((*MockImportReporter)(nil).Progress), p)
+}
@@ -12,9 +12,13 @@ import (
 //go:generate mockgen -source ./repository.go -package mocks -destination mocks/transactionRepository.mock.gen.go
 type Repository interface {
 	CreateTransaction(tx *sql.Tx, ctx context.Context, tr *model.TransactionDao) error
-	CancelTransaction(ctx context.Context, id string) error
-	CheckExistance(ctx context.Context, id string) (bool, error)
+	CancelTransaction(tx *sql.Tx, ctx context.Context, id string) (*model.TransactionDao, error)
+	GetTransactionByTransactionID(tx *sql.Tx, ctx context.Context, transactionID string) (*model.TransactionDao, error)
+	GetTransactionForUpdate(tx *sql.Tx, ctx context.Context, id string) (*model.TransactionDao, error)
+	UpdateTransactionState(tx *sql.Tx, ctx context.Context, id, state string) error
 	GetLatestOddAndUncancelledTransactions(ctx context.Context, limit int) ([]*model.TransactionDao, error)
+	GetUserTransactions(ctx context.Context, filter *model.TransactionFilter) ([]*model.TransactionDao, error)
+	ExportTransactions(ctx context.Context, filter *model.ExportFilter, fn func(*model.TransactionDao) error) error
 }
 
 //go:generate mockgen -source ./repository.go -package mocks -destination mocks/transactionRepository.mock.gen.go
@@ -23,3 +27,9 @@ type Database interface {
 	Rollback(tx *sql.Tx) error
 	Commit(tx *sql.Tx) error
 }
+
+// Elector decides which instance of the service runs the post processing.
+type Elector interface {
+	IsLeader(ctx context.Context) (bool, error)
+	Resign(ctx context.Context) error
+}
@@ -32,9 +32,13 @@ func (t *Transaction) CreateTransaction(tx *sql.Tx, ctx context.Context, transac
 			transaction_id,
 			source_type,
 			state,
-			amount
-		) VALUES ($1, $2, $3, $4, $5)
-		RETURNING id;
+			amount,
+			currency,
+			bonus_amount,
+			operator_id,
+			reason
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, seq;
 	`
 
 	err := tx.QueryRowContext(
@@ -45,7 +49,11 @@ func (t *Transaction) CreateTransaction(tx *sql.Tx, ctx context.Context, transac
 		transaction.SourceType,
 		transaction.State,
 		transaction.Amount,
-	).Scan(&transaction.ID)
+		transaction.Currency,
+		transaction.BonusAmount,
+		transaction.OperatorID,
+		transaction.Reason,
+	).Scan(&transaction.ID, &transaction.Seq)
 
 	if err != nil {
 		var pqError *pq.Error
@@ -53,6 +61,10 @@ func (t *Transaction) CreateTransaction(tx *sql.Tx, ctx context.Context, transac
 			if pqError.Constraint == "unique_transaction_id" {
 				return fmt.Errorf("failed to insert transaction because of unique constraint: %w", model.ErrorTransactionAlreadyExists)
 			}
+
+			if pqError.Constraint == "transactions_state_fkey" || pqError.Constraint == "transactions_source_type_fkey" {
+				return fmt.Errorf("failed to insert transaction because of %s: %w", pqError.Constraint, model.ErrorUnknownState)
+			}
 		}
 
 		return fmt.Errorf("failed to execute insert transaction query: %w", err)
@@ -61,89 +73,201 @@ func (t *Transaction) CreateTransaction(tx *sql.Tx, ctx context.Context, transac
 	return nil
 }
 
-// CancelTransaction cancels a transaction by id.
-func (t *Transaction) CancelTransaction(ctx context.Context, id string) error {
+// CancelTransaction marks an uncancelled transaction as cancelled within the given db tx and returns it.
+func (t *Transaction) CancelTransaction(tx *sql.Tx, ctx context.Context, id string) (*model.TransactionDao, error) {
 	query := `
 		UPDATE transactions
 		SET cancelled = true, cancelled_at = NOW()
-		WHERE id = $1
-		RETURNING id;
+		WHERE id = $1 AND cancelled = false
+		RETURNING id, seq, user_id, transaction_id, source_type, state, amount, currency, bonus_amount, created_at, cancelled;
 	`
-	tx, err := t.conn.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to start a db tx: %w", err)
-	}
+	transaction := &model.TransactionDao{}
 
-	_, err = tx.ExecContext(
+	err := tx.QueryRowContext(
 		ctx,
 		query,
 		id,
+	).Scan(
+		&transaction.ID,
+		&transaction.Seq,
+		&transaction.UserID,
+		&transaction.TransactionID,
+		&transaction.SourceType,
+		&transaction.State,
+		&transaction.Amount,
+		&transaction.Currency,
+		&transaction.BonusAmount,
+		&transaction.CreatedAt,
+		&transaction.Cancelled,
 	)
 
 	if err != nil {
-		errTx := tx.Rollback()
-		if errTx != nil {
-			return fmt.Errorf("failed to rollback the db tx: %w %w", errTx, err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed because transaction not found or already cancelled: %w", model.ErrorTransactionNotFound)
 		}
 
-		return fmt.Errorf("failed to execute update transaction query: %w", err)
+		return nil, fmt.Errorf("failed to execute update transaction query: %w", err)
 	}
 
-	err = tx.Commit()
+	return transaction, nil
+}
+
+// GetTransactionByTransactionID returns a transaction by the transactionId given by the provider.
+func (t *Transaction) GetTransactionByTransactionID(tx *sql.Tx, ctx context.Context, transactionID string) (*model.TransactionDao, error) {
+	query := `
+		SELECT id,
+			seq,
+			user_id,
+			transaction_id,
+			source_type,
+			state,
+			amount,
+			currency,
+			bonus_amount,
+			created_at,
+			cancelled,
+			operator_id,
+			reason
+		FROM transactions
+		WHERE transaction_id = $1;
+	`
+	transaction := &model.TransactionDao{}
+
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		transactionID,
+	).Scan(
+		&transaction.ID,
+		&transaction.Seq,
+		&transaction.UserID,
+		&transaction.TransactionID,
+		&transaction.SourceType,
+		&transaction.State,
+		&transaction.Amount,
+		&transaction.Currency,
+		&transaction.BonusAmount,
+		&transaction.CreatedAt,
+		&transaction.Cancelled,
+		&transaction.OperatorID,
+		&transaction.Reason,
+	)
+
 	if err != nil {
-		return fmt.Errorf("failed to commit the db tx: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed because transaction not found: %w", model.ErrorTransactionNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to execute get transaction query: %w", err)
 	}
 
-	return nil
+	return transaction, nil
 }
 
-// CheckExistance checks existance of transaction in database
-func (t *Transaction) CheckExistance(ctx context.Context, id string) (bool, error) {
+// GetTransactionForUpdate returns a transaction by its id and locks the row until the given db tx ends.
+func (t *Transaction) GetTransactionForUpdate(tx *sql.Tx, ctx context.Context, id string) (*model.TransactionDao, error) {
 	query := `
-		SELECT EXISTS (
-			SELECT 1
-			FROM transactions
-			WHERE transaction_id = $1
-		);
+		SELECT id,
+			seq,
+			user_id,
+			transaction_id,
+			source_type,
+			state,
+			amount,
+			currency,
+			bonus_amount,
+			created_at,
+			cancelled
+		FROM transactions
+		WHERE id = $1 FOR UPDATE;
 	`
-	var exists bool
+	transaction := &model.TransactionDao{}
 
-	err := t.conn.QueryRowContext(
+	err := tx.QueryRowContext(
 		ctx,
 		query,
-		&id,
+		id,
 	).Scan(
-		&exists,
+		&transaction.ID,
+		&transaction.Seq,
+		&transaction.UserID,
+		&transaction.TransactionID,
+		&transaction.SourceType,
+		&transaction.State,
+		&transaction.Amount,
+		&transaction.Currency,
+		&transaction.BonusAmount,
+		&transaction.CreatedAt,
+		&transaction.Cancelled,
 	)
 
 	if err != nil {
-		return false, fmt.Errorf("failed to execute check transaction existance query: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed because transaction not found: %w", model.ErrorTransactionNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to execute get transaction for update query: %w", err)
 	}
 
-	return exists, nil
+	return transaction, nil
 }
 
-// GetLatestOddAndUncancelledTransactions returns the latest odd transactions with a limit.
-// Odd records definition was unclear, it means odd amount, transactionId or id etc., so I haven't implemented it.
+// UpdateTransactionState moves a transaction to the given state within the given db tx.
+func (t *Transaction) UpdateTransactionState(tx *sql.Tx, ctx context.Context, id, state string) error {
+	query := `
+		UPDATE transactions
+		SET state = $2
+		WHERE id = $1
+		RETURNING id;
+	`
+	err := tx.QueryRowContext(ctx, query, id, state).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed because transaction not found: %w", model.ErrorTransactionNotFound)
+		}
+
+		var pqError *pq.Error
+		if errors.As(err, &pqError) && pqError.Constraint == "transactions_state_fkey" {
+			return fmt.Errorf("failed to update transaction because of %s: %w", pqError.Constraint, model.ErrorUnknownState)
+		}
+
+		return fmt.Errorf("failed to execute update transaction state query: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestOddAndUncancelledTransactions returns the odd and uncancelled transactions among the latest ones.
+// A transaction is odd when its insertion sequence number is odd, the window is the latest limit transactions
+// by that sequence, cancelled or not, so the same rule always selects the same rows.
+// Only gambling transactions are selected, payments, adjustments and transfer legs count towards the window only.
 func (t *Transaction) GetLatestOddAndUncancelledTransactions(ctx context.Context, limit int) ([]*model.TransactionDao, error) {
 	query := `
 		SELECT id,
+			seq,
 			user_id,
 			transaction_id,
 			source_type,
 			state,
 			amount,
+			currency,
+			bonus_amount,
 			created_at,
-			cancelled 
-		 FROM transactions
-			WHERE cancelled = false
-			ORDER BY created_at DESC
+			cancelled
+		FROM (
+			SELECT *
+			FROM transactions
+			ORDER BY seq DESC
 			LIMIT $1
+		) latest
+		WHERE seq % 2 = 1 AND cancelled = false AND source_type = ANY($2)
+		ORDER BY seq DESC
 	`
 	rows, err := t.conn.QueryContext(
 		ctx,
 		query,
 		limit,
+		pq.Array(model.GamingSourceTypes),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get latest odd and uncancelled transactions query: %w", err)
@@ -157,11 +281,14 @@ func (t *Transaction) GetLatestOddAndUncancelledTransactions(ctx context.Context
 		transaction := &model.TransactionDao{}
 		err = rows.Scan(
 			&transaction.ID,
+			&transaction.Seq,
 			&transaction.UserID,
 			&transaction.TransactionID,
 			&transaction.SourceType,
 			&transaction.State,
 			&transaction.Amount,
+			&transaction.Currency,
+			&transaction.BonusAmount,
 			&transaction.CreatedAt,
 			&transaction.Cancelled,
 		)
@@ -172,5 +299,159 @@ func (t *Transaction) GetLatestOddAndUncancelledTransactions(ctx context.Context
 		transactions = append(transactions, transaction)
 	}
 
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate transaction rows: %w", err)
+	}
+
 	return transactions, nil
 }
+
+// GetUserTransactions returns the transactions of a user matching the filter, the latest first.
+// The page starts right after the transaction with the cursor sequence number and holds at most filter.Limit rows.
+func (t *Transaction) GetUserTransactions(ctx context.Context, filter *model.TransactionFilter) ([]*model.TransactionDao, error) {
+	query := `
+		SELECT id,
+			seq,
+			user_id,
+			transaction_id,
+			source_type,
+			state,
+			amount,
+			currency,
+			bonus_amount,
+			created_at,
+			cancelled,
+			cancelled_at,
+			operator_id,
+			reason
+		FROM transactions
+		WHERE user_id = $1
+			AND ($2::bigint = 0 OR seq < $2::bigint)
+			AND ($3::text = '' OR state::text = $3::text)
+			AND ($4::text = '' OR source_type::text = $4::text)
+			AND ($5::boolean IS NULL OR cancelled = $5::boolean)
+			AND ($6::timestamptz IS NULL OR created_at >= $6::timestamptz)
+			AND ($7::timestamptz IS NULL OR created_at < $7::timestamptz)
+		ORDER BY seq DESC
+		LIMIT $8
+	`
+	rows, err := t.conn.QueryContext(
+		ctx,
+		query,
+		filter.UserID,
+		filter.Cursor,
+		filter.State,
+		filter.SourceType,
+		filter.Cancelled,
+		filter.From,
+		filter.To,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get user transactions query: %w", err)
+	}
+
+	defer rows.Close()
+
+	transactions := []*model.TransactionDao{}
+
+	for rows.Next() {
+		transaction := &model.TransactionDao{}
+		err = rows.Scan(
+			&transaction.ID,
+			&transaction.Seq,
+			&transaction.UserID,
+			&transaction.TransactionID,
+			&transaction.SourceType,
+			&transaction.State,
+			&transaction.Amount,
+			&transaction.Currency,
+			&transaction.BonusAmount,
+			&transaction.CreatedAt,
+			&transaction.Cancelled,
+			&transaction.CancelledAt,
+			&transaction.OperatorID,
+			&transaction.Reason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction row: %w", err)
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate transaction rows: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// ExportTransactions calls fn with every transaction matching the filter in the order they were made.
+// The rows are read one by one as fn consumes them, an error of fn stops the export and is returned.
+func (t *Transaction) ExportTransactions(ctx context.Context, filter *model.ExportFilter, fn func(*model.TransactionDao) error) error {
+	query := `
+		SELECT id,
+			seq,
+			user_id,
+			transaction_id,
+			source_type,
+			state,
+			amount,
+			currency,
+			bonus_amount,
+			created_at,
+			cancelled,
+			cancelled_at,
+			operator_id,
+			reason
+		FROM transactions
+		WHERE ($1::text = '' OR user_id = NULLIF($1::text, '')::uuid)
+			AND ($2::timestamptz IS NULL OR created_at >= $2::timestamptz)
+			AND ($3::timestamptz IS NULL OR created_at < $3::timestamptz)
+		ORDER BY seq
+	`
+	rows, err := t.conn.QueryContext(ctx, query, filter.UserID, filter.From, filter.To)
+	if err != nil {
+		return fmt.Errorf("failed to execute export transactions query: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		transaction := &model.TransactionDao{}
+		err = rows.Scan(
+			&transaction.ID,
+			&transaction.Seq,
+			&transaction.UserID,
+			&transaction.TransactionID,
+			&transaction.SourceType,
+			&transaction.State,
+			&transaction.Amount,
+			&transaction.Currency,
+			&transaction.BonusAmount,
+			&transaction.CreatedAt,
+			&transaction.Cancelled,
+			&transaction.CancelledAt,
+			&transaction.OperatorID,
+			&transaction.Reason,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan transaction row: %w", err)
+		}
+
+		err = fn(transaction)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("failed to iterate transaction rows: %w", err)
+	}
+
+	return nil
+}
@@ -3,19 +3,32 @@ package repository_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/bxcodec/faker/v3"
-	"github.com/golang-migrate/migrate"
-	_ "github.com/golang-migrate/migrate/database/postgres"
-	_ "github.com/golang-migrate/migrate/source/file"
+	"github.com/golang-migrate/migrate/v4"
 	"github.com/stretchr/testify/suite"
 	"github.com/testcontainers/testcontainers-go"
 
+	betRepo "github.com/ttagiyeva/entain/internal/bet/repository"
+	"github.com/ttagiyeva/entain/internal/config"
 	"github.com/ttagiyeva/entain/internal/database"
+	exclusionRepo "github.com/ttagiyeva/entain/internal/exclusion/repository"
+	ledgerRepo "github.com/ttagiyeva/entain/internal/ledger/repository"
+	limitRepo "github.com/ttagiyeva/entain/internal/limit/repository"
 	"github.com/ttagiyeva/entain/internal/model"
+	registryRepo "github.com/ttagiyeva/entain/internal/registry/repository"
+	registryUsecase "github.com/ttagiyeva/entain/internal/registry/usecase"
+	"github.com/ttagiyeva/entain/internal/transaction"
 	"github.com/ttagiyeva/entain/internal/transaction/repository"
+	"github.com/ttagiyeva/entain/internal/transaction/usecase"
+	transferRepo "github.com/ttagiyeva/entain/internal/transfer/repository"
+	userRepo "github.com/ttagiyeva/entain/internal/user/repository"
 	"github.com/ttagiyeva/entain/internal/util"
 )
 
@@ -43,6 +56,28 @@ func (t *transactionRepoTestSuite) SetupTest() {
 	}
 }
 
+// newUsecase returns a transaction usecase on the repositories of the suite, a nil elector is a leader of its own.
+func (t *transactionRepoTestSuite) newUsecase(conf *config.Config, e transaction.Elector) *usecase.Transaction {
+	if e == nil {
+		e = database.NewLeader(t.db, 1)
+	}
+
+	return usecase.New(
+		slog.Default(),
+		conf,
+		t.repo,
+		userRepo.New(t.db.Connection),
+		ledgerRepo.New(t.db.Connection),
+		limitRepo.New(t.db.Connection),
+		exclusionRepo.New(t.db.Connection),
+		registryUsecase.New(slog.Default(), &config.Config{}, registryRepo.New(t.db.Connection)),
+		betRepo.New(t.db.Connection),
+		transferRepo.New(t.db.Connection),
+		t.db,
+		e,
+	)
+}
+
 func (t *transactionRepoTestSuite) TearDownTest() {
 	t.NoError(t.db.MigrateDown())
 }
@@ -54,6 +89,7 @@ func (t *transactionRepoTestSuite) TestCreateTransaction() {
 		SourceType:    "game",
 		State:         "win",
 		Amount:        0.0,
+		Currency:      "EUR",
 		CreatedAt:     time.Now(),
 		Cancelled:     false,
 	}
@@ -75,6 +111,7 @@ func (t *transactionRepoTestSuite) TestCancelTransaction() {
 		SourceType:    "game",
 		State:         "win",
 		Amount:        0.0,
+		Currency:      "EUR",
 		CreatedAt:     time.Now(),
 		Cancelled:     false,
 	}
@@ -87,19 +124,31 @@ func (t *transactionRepoTestSuite) TestCancelTransaction() {
 	err := tx.Commit()
 	t.NoError(err)
 
-	t.NoError(t.repo.CancelTransaction(t.ctx, transaction.ID))
+	tx = t.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
+
+	cancelled, err := t.repo.CancelTransaction(tx, t.ctx, transaction.ID)
+	t.NoError(err)
+	t.Equal(transaction.ID, cancelled.ID)
+	t.Equal(true, cancelled.Cancelled)
+
+	cancelled, err = t.repo.CancelTransaction(tx, t.ctx, transaction.ID)
+	t.Equal(true, errors.Is(err, model.ErrorTransactionNotFound))
+	t.Nil(cancelled)
 
-	err = t.repo.CancelTransaction(t.ctx, faker.UUIDHyphenated())
-	t.Nil(err)
+	cancelled, err = t.repo.CancelTransaction(tx, t.ctx, faker.UUIDHyphenated())
+	t.Equal(true, errors.Is(err, model.ErrorTransactionNotFound))
+	t.Nil(cancelled)
 }
 
-func (t *transactionRepoTestSuite) TestCheckExistance() {
+func (t *transactionRepoTestSuite) TestGetTransactionByTransactionID() {
 	transaction := &model.TransactionDao{
 		UserID:        "00000000-0000-0000-0000-000000000001",
 		TransactionID: faker.UUIDHyphenated(),
 		SourceType:    "game",
 		State:         "win",
-		Amount:        0.0,
+		Amount:        1015,
+		Currency:      "EUR",
 		CreatedAt:     time.Now(),
 		Cancelled:     false,
 	}
@@ -112,36 +161,1127 @@ func (t *transactionRepoTestSuite) TestCheckExistance() {
 	err := tx.Commit()
 	t.NoError(err)
 
-	ok, err := t.repo.CheckExistance(t.ctx, transaction.TransactionID)
-	t.Equal(true, ok)
-	t.NoError(err)
+	tx = t.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
 
-	ok, err = t.repo.CheckExistance(t.ctx, faker.UUIDHyphenated())
-	t.Equal(false, ok)
+	stored, err := t.repo.GetTransactionByTransactionID(tx, t.ctx, transaction.TransactionID)
 	t.NoError(err)
+	t.Equal(transaction.ID, stored.ID)
+	t.Equal(model.Money(1015), stored.Amount)
+
+	stored, err = t.repo.GetTransactionByTransactionID(tx, t.ctx, faker.UUIDHyphenated())
+	t.Equal(true, errors.Is(err, model.ErrorTransactionNotFound))
+	t.Nil(stored)
 }
 
 func (t *transactionRepoTestSuite) TestGetLatestOddAndUncancelledTransactions() {
-	transaction := &model.TransactionDao{
+	transactions := make([]*model.TransactionDao, 0, 5)
+
+	for i := 0; i < 5; i++ {
+		transaction := &model.TransactionDao{
+			UserID:        "00000000-0000-0000-0000-000000000001",
+			TransactionID: faker.UUIDHyphenated(),
+			SourceType:    "game",
+			State:         "win",
+			Amount:        0.0,
+			Currency:      "EUR",
+		}
+
+		tx := t.db.Connection.MustBegin().Tx
+
+		t.NoError(t.repo.CreateTransaction(tx, t.ctx, transaction))
+		t.NoError(tx.Commit())
+
+		transactions = append(transactions, transaction)
+	}
+
+	for i := 1; i < len(transactions); i++ {
+		t.Equal(transactions[i-1].Seq+1, transactions[i].Seq)
+	}
+
+	// The window of the latest 4 transactions holds two odd sequence numbers.
+	odd := []string{}
+	for _, tr := range transactions[1:] {
+		if tr.Seq%2 == 1 {
+			odd = append([]string{tr.ID}, odd...)
+		}
+	}
+
+	result, err := t.repo.GetLatestOddAndUncancelledTransactions(t.ctx, 4)
+	t.NoError(err)
+	t.Equal(2, len(result))
+
+	for i, tr := range result {
+		t.Equal(odd[i], tr.ID)
+		t.Equal(int64(1), tr.Seq%2)
+		t.Equal(false, tr.Cancelled)
+	}
+
+	_, err = t.db.Connection.ExecContext(t.ctx, `UPDATE transactions SET cancelled = true WHERE id = $1`, odd[0])
+	t.NoError(err)
+
+	// Cancelled transactions stay in the window but are not selected again.
+	result, err = t.repo.GetLatestOddAndUncancelledTransactions(t.ctx, 4)
+	t.NoError(err)
+	t.Equal(1, len(result))
+	t.Equal(odd[1], result[0].ID)
+
+	// The latest transaction is either even or the one cancelled above.
+	result, err = t.repo.GetLatestOddAndUncancelledTransactions(t.ctx, 1)
+	t.NoError(err)
+	t.Empty(result)
+}
+
+func (t *transactionRepoTestSuite) TestConcurrentProcess() {
+	const (
+		userID  = "00000000-0000-0000-0000-000000000001"
+		wins    = 200
+		losses  = 100
+		initial = model.Money(100000)
+	)
+
+	_, err := t.db.Connection.ExecContext(t.ctx, `UPDATE wallets SET balance = $1 WHERE user_id = $2 AND currency = 'EUR'`, initial, userID)
+	t.Require().NoError(err)
+
+	t.db.Connection.SetMaxOpenConns(20)
+	defer t.db.Connection.SetMaxOpenConns(0)
+
+	uc := t.newUsecase(&config.Config{}, nil)
+
+	wg := sync.WaitGroup{}
+	errCh := make(chan error, wins+losses)
+
+	for i := 0; i < wins+losses; i++ {
+		state := "win"
+		if i%3 == 2 {
+			state = "lost"
+		}
+
+		wg.Add(1)
+
+		go func(state string) {
+			defer wg.Done()
+
+			errCh <- uc.Process(t.ctx, &model.Transaction{
+				TransactionID: faker.UUIDHyphenated(),
+				UserID:        userID,
+				SourceType:    "game",
+				State:         state,
+				Amount:        100,
+				Currency:      "EUR",
+			})
+		}(state)
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		t.NoError(err)
+	}
+
+	var balance model.Money
+
+	err = t.db.Connection.QueryRowContext(t.ctx, `SELECT balance FROM wallets WHERE user_id = $1 AND currency = 'EUR'`, userID).Scan(&balance)
+	t.Require().NoError(err)
+	t.Equal(initial+(wins-losses)*100, balance)
+}
+
+func (t *transactionRepoTestSuite) TestReplayProcess() {
+	uc := t.newUsecase(&config.Config{}, nil)
+
+	tr := &model.Transaction{
+		TransactionID: faker.UUIDHyphenated(),
 		UserID:        "00000000-0000-0000-0000-000000000001",
+		SourceType:    "game",
+		State:         "win",
+		Amount:        500,
+		Currency:      "EUR",
+	}
+
+	t.NoError(uc.Process(t.ctx, tr))
+	t.NoError(uc.Process(t.ctx, tr))
+
+	var balance model.Money
+
+	err := t.db.Connection.QueryRowContext(t.ctx, `SELECT balance FROM wallets WHERE user_id = $1 AND currency = 'EUR'`, tr.UserID).Scan(&balance)
+	t.Require().NoError(err)
+	t.Equal(model.Money(500), balance)
+
+	changed := *tr
+	changed.Amount = 600
+
+	err = uc.Process(t.ctx, &changed)
+	t.Equal(true, errors.Is(err, model.ErrorTransactionAlreadyExists))
+}
+
+func (t *transactionRepoTestSuite) TestCancelReversesBalance() {
+	uc := t.newUsecase(&config.Config{}, nil)
+
+	win := &model.Transaction{
 		TransactionID: faker.UUIDHyphenated(),
+		UserID:        "00000000-0000-0000-0000-000000000001",
 		SourceType:    "game",
 		State:         "win",
-		Amount:        0.0,
-		CreatedAt:     time.Now(),
-		Cancelled:     false,
+		Amount:        1000,
+		Currency:      "EUR",
+	}
+	lost := &model.Transaction{
+		TransactionID: faker.UUIDHyphenated(),
+		UserID:        win.UserID,
+		SourceType:    "game",
+		State:         "lost",
+		Amount:        500,
+		Currency:      "EUR",
 	}
 
+	t.NoError(uc.Process(t.ctx, win))
+	t.NoError(uc.Process(t.ctx, lost))
+
+	balance := func() model.Money {
+		var b model.Money
+
+		err := t.db.Connection.QueryRowContext(t.ctx, `SELECT balance FROM wallets WHERE user_id = $1 AND currency = 'EUR'`, win.UserID).Scan(&b)
+		t.Require().NoError(err)
+
+		return b
+	}
+
+	id := func(transactionID string) string {
+		tx := t.db.Connection.MustBegin().Tx
+		defer tx.Rollback()
+
+		tr, err := t.repo.GetTransactionByTransactionID(tx, t.ctx, transactionID)
+		t.Require().NoError(err)
+
+		return tr.ID
+	}
+
+	// Only 5.00 is left of the 10.00 win, so it cannot be reversed yet.
+	err := uc.Cancel(t.ctx, id(win.TransactionID))
+	t.Equal(true, errors.Is(err, model.ErrorCancellationInsufficientBalance))
+	t.Equal(model.Money(500), balance())
+
+	t.NoError(uc.Cancel(t.ctx, id(lost.TransactionID)))
+	t.Equal(model.Money(1000), balance())
+
+	t.NoError(uc.Cancel(t.ctx, id(win.TransactionID)))
+	t.Equal(model.Money(0), balance())
+
+	err = uc.Cancel(t.ctx, id(win.TransactionID))
+	t.Equal(true, errors.Is(err, model.ErrorTransactionNotFound))
+
+	entries, err := ledgerRepo.New(t.db.Connection).GetUserEntries(t.ctx, win.UserID)
+	t.NoError(err)
+	t.Len(entries, 4)
+
+	reasons := []string{}
+	for _, entry := range entries {
+		reasons = append(reasons, entry.Reason)
+	}
+
+	t.Equal([]string{
+		model.LedgerReasonTransaction,
+		model.LedgerReasonTransaction,
+		model.LedgerReasonCancellation,
+		model.LedgerReasonCancellation,
+	}, reasons)
+	t.Equal(model.Money(0), entries[3].BalanceAfter)
+
+	mismatches, err := ledgerRepo.New(t.db.Connection).GetMismatches(t.ctx)
+	t.NoError(err)
+	t.Empty(mismatches)
+}
+
+func (t *transactionRepoTestSuite) TestBonusWagering() {
+	uc := t.newUsecase(&config.Config{}, nil)
+	users := userRepo.New(t.db.Connection)
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	process := func(state string, amount model.Money) error {
+		return uc.Process(t.ctx, &model.Transaction{
+			TransactionID: faker.UUIDHyphenated(),
+			UserID:        userID,
+			SourceType:    "game",
+			State:         state,
+			Amount:        amount,
+			Currency:      "EUR",
+		})
+	}
+
+	wallet := func() *model.WalletDao {
+		wallets, err := users.GetWallets(t.ctx, userID)
+		t.Require().NoError(err)
+		t.Require().Len(wallets, 1)
+
+		return wallets[0]
+	}
+
+	t.NoError(process("win", 1000))
+	t.NoError(uc.GrantBonus(t.ctx, &model.Bonus{UserID: userID, Currency: "EUR", Amount: 1000, Wagering: 2000}))
+
+	// The loss takes the whole real balance and the rest from the bonus.
+	t.NoError(process("lost", 1500))
+	t.Equal(model.Money(0), wallet().Balance)
+	t.Equal(model.Money(500), wallet().BonusBalance)
+	t.Equal(model.Money(1500), wallet().Wagered)
+
+	// While wagering, a win is credited to the bonus.
+	t.NoError(process("win", 1000))
+	t.Equal(model.Money(0), wallet().Balance)
+	t.Equal(model.Money(1500), wallet().BonusBalance)
+
+	err := process("lost", 2000)
+	t.Equal(true, errors.Is(err, model.ErrorInsufficientBalance))
+
+	// Meeting the requirement converts what is left of the bonus to real money.
+	t.NoError(process("lost", 500))
+	w := wallet()
+	t.Equal(model.Money(1000), w.Balance)
+	t.Equal(model.Money(0), w.BonusBalance)
+	t.Equal(model.Money(0), w.WageringRequired)
+	t.Equal(model.Money(0), w.Wagered)
+
+	mismatches, err := ledgerRepo.New(t.db.Connection).GetMismatches(t.ctx)
+	t.NoError(err)
+	t.Empty(mismatches)
+}
+
+func (t *transactionRepoTestSuite) TestLossLimit() {
+	uc := t.newUsecase(&config.Config{}, nil)
+	limits := limitRepo.New(t.db.Connection)
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	process := func(state string, amount model.Money) error {
+		return uc.Process(t.ctx, &model.Transaction{
+			TransactionID: faker.UUIDHyphenated(),
+			UserID:        userID,
+			SourceType:    "game",
+			State:         state,
+			Amount:        amount,
+			Currency:      "EUR",
+		})
+	}
+
+	t.NoError(process("win", 10000))
+
 	tx := t.db.Connection.MustBegin().Tx
+	t.NoError(limits.SaveLimit(tx, t.ctx, &model.LimitDao{
+		UserID:   userID,
+		Currency: "EUR",
+		Kind:     model.LimitKindLoss,
+		Period:   model.LimitPeriodDay,
+		Amount:   2000,
+	}))
+	t.NoError(tx.Commit())
 
-	t.NoError(t.repo.CreateTransaction(tx, t.ctx, transaction))
-	t.NotEqual(0, transaction.ID)
+	t.NoError(process("lost", 1500))
 
-	err := tx.Commit()
+	err := process("lost", 1000)
+	t.Equal(true, errors.Is(err, model.ErrorLossLimitExceeded))
+
+	// Winnings of the day make room for further losses.
+	t.NoError(process("win", 500))
+	t.NoError(process("lost", 1000))
+}
+
+func (t *transactionRepoTestSuite) TestLossLimitOpenBets() {
+	uc := t.newUsecase(&config.Config{}, nil)
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	t.NoError(uc.Process(t.ctx, &model.Transaction{
+		TransactionID: faker.UUIDHyphenated(),
+		UserID:        userID,
+		SourceType:    "game",
+		State:         "win",
+		Amount:        10000,
+		Currency:      "EUR",
+	}))
+
+	tx := t.db.Connection.MustBegin().Tx
+	t.NoError(limitRepo.New(t.db.Connection).SaveLimit(tx, t.ctx, &model.LimitDao{
+		UserID:   userID,
+		Currency: "EUR",
+		Kind:     model.LimitKindLoss,
+		Period:   model.LimitPeriodDay,
+		Amount:   2000,
+	}))
+	t.NoError(tx.Commit())
+
+	place := func(stake model.Money) (*model.Bet, error) {
+		return uc.PlaceBet(t.ctx, &model.PlaceBet{BetID: faker.UUIDHyphenated(), Stake: stake, Currency: "EUR", UserID: userID})
+	}
+
+	// Each stake fits the limit alone, but both bets could be lost.
+	first, err := place(1500)
+	t.Require().NoError(err)
+
+	_, err = place(1000)
+	t.Equal(true, errors.Is(err, model.ErrorLossLimitExceeded))
+
+	// An open stake counts towards a loss of a single transaction as well.
+	err = uc.Process(t.ctx, &model.Transaction{
+		TransactionID: faker.UUIDHyphenated(),
+		UserID:        userID,
+		SourceType:    "game",
+		State:         "lost",
+		Amount:        1000,
+		Currency:      "EUR",
+	})
+	t.Equal(true, errors.Is(err, model.ErrorLossLimitExceeded))
+
+	// A voided bet gives its room back.
+	_, err = uc.VoidBet(t.ctx, first.ID)
+	t.Require().NoError(err)
+
+	_, err = place(1000)
+	t.NoError(err)
+}
+
+func (t *transactionRepoTestSuite) TestSelfExclusion() {
+	uc := t.newUsecase(&config.Config{}, nil)
+
+	tr := &model.Transaction{
+		TransactionID: faker.UUIDHyphenated(),
+		UserID:        "00000000-0000-0000-0000-000000000001",
+		SourceType:    "game",
+		State:         "win",
+		Amount:        1000,
+		Currency:      "EUR",
+	}
+
+	now := time.Now()
+	t.Require().NoError(exclusionRepo.New(t.db.Connection).CreateExclusion(t.ctx, &model.ExclusionDao{
+		UserID:    tr.UserID,
+		Period:    model.ExclusionPeriodDay,
+		StartedAt: now,
+		EndsAt:    model.ExclusionEnd(model.ExclusionPeriodDay, now),
+	}))
+
+	err := uc.Process(t.ctx, tr)
+	t.Equal(true, errors.Is(err, model.ErrorUserSelfExcluded))
+
+	var count int
+	t.NoError(t.db.Connection.QueryRowContext(t.ctx, `SELECT COUNT(*) FROM transactions`).Scan(&count))
+	t.Equal(0, count)
+}
+
+func (t *transactionRepoTestSuite) TestRegisteredStates() {
+	// A new source type and new states are usable without a deploy once they are in the registry tables.
+	_, err := t.db.Connection.ExecContext(t.ctx, `
+		INSERT INTO source_types (name) VALUES ('casino');
+		INSERT INTO states (name, direction) VALUES ('refund', 'credit'), ('void', 'none');
+	`)
+	t.Require().NoError(err)
+
+	uc := t.newUsecase(&config.Config{}, nil)
+	users := userRepo.New(t.db.Connection)
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	process := func(state string) (*model.Transaction, error) {
+		tr := &model.Transaction{
+			TransactionID: faker.UUIDHyphenated(),
+			UserID:        userID,
+			SourceType:    "casino",
+			State:         state,
+			Amount:        1000,
+			Currency:      "EUR",
+		}
+
+		return tr, uc.Process(t.ctx, tr)
+	}
+
+	balance := func() model.Money {
+		tx := t.db.Connection.MustBegin().Tx
+		defer tx.Rollback()
+
+		wallet, err := users.GetWalletForUpdate(tx, t.ctx, userID, "EUR")
+		t.Require().NoError(err)
+
+		return wallet.Balance
+	}
+
+	refund, err := process("refund")
+	t.NoError(err)
+	t.Equal(model.Money(1000), balance())
+
+	_, err = process("void")
+	t.NoError(err)
+	t.Equal(model.Money(1000), balance())
+
+	_, err = process("chargeback")
+	t.Equal(true, errors.Is(err, model.ErrorUnknownState))
+
+	// A cancelled refund is reversed by the direction of its state.
+	tx := t.db.Connection.MustBegin().Tx
+	stored, err := t.repo.GetTransactionByTransactionID(tx, t.ctx, refund.TransactionID)
+	t.Require().NoError(err)
+	t.NoError(tx.Rollback())
+
+	t.NoError(uc.Cancel(t.ctx, stored.ID))
+	t.Equal(model.Money(0), balance())
+}
+
+func (t *transactionRepoTestSuite) TestPayments() {
+	uc := t.newUsecase(&config.Config{}, nil)
+	users := userRepo.New(t.db.Connection)
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	start := func(kind string, amount model.Money) (*model.TransactionView, error) {
+		return uc.StartPayment(t.ctx, &model.Payment{
+			TransactionID: faker.UUIDHyphenated(),
+			Amount:        amount,
+			Currency:      "EUR",
+			UserID:        userID,
+			Kind:          kind,
+		})
+	}
+
+	wallet := func() *model.WalletDao {
+		tx := t.db.Connection.MustBegin().Tx
+		defer tx.Rollback()
+
+		w, err := users.GetWalletForUpdate(tx, t.ctx, userID, "EUR")
+		t.Require().NoError(err)
+
+		return w
+	}
+
+	// A deposit moves money only once it is confirmed.
+	deposit, err := start(model.PaymentKindDeposit, 10000)
+	t.Require().NoError(err)
+	t.Equal(model.Money(0), wallet().Balance)
+
+	deposit, err = uc.ConfirmPayment(t.ctx, deposit.ID)
+	t.Require().NoError(err)
+	t.Equal(model.StateDepositConfirmed, deposit.State)
+	t.Equal(model.Money(10000), wallet().Balance)
+
+	_, err = uc.FailPayment(t.ctx, deposit.ID)
+	t.Equal(true, errors.Is(err, model.ErrorPaymentNotPending))
+
+	// A pending withdrawal holds its amount, which cannot be lost in the meantime.
+	withdrawal, err := start(model.PaymentKindWithdrawal, 6000)
+	t.Require().NoError(err)
+	t.Equal(model.Money(10000), wallet().Balance)
+	t.Equal(model.Money(6000), wallet().Reserved)
+
+	err = uc.Process(t.ctx, &model.Transaction{
+		TransactionID: faker.UUIDHyphenated(),
+		UserID:        userID,
+		SourceType:    "game",
+		State:         "lost",
+		Amount:        5000,
+		Currency:      "EUR",
+	})
+	t.Equal(true, errors.Is(err, model.ErrorInsufficientBalance))
+
+	_, err = start(model.PaymentKindWithdrawal, 5000)
+	t.Equal(true, errors.Is(err, model.ErrorInsufficientBalance))
+
+	// A failed withdrawal releases its amount, a confirmed one takes it.
+	_, err = uc.FailPayment(t.ctx, withdrawal.ID)
+	t.Require().NoError(err)
+	t.Equal(model.Money(0), wallet().Reserved)
+
+	withdrawal, err = start(model.PaymentKindWithdrawal, 4000)
+	t.Require().NoError(err)
+
+	_, err = uc.ConfirmPayment(t.ctx, withdrawal.ID)
+	t.Require().NoError(err)
+	t.Equal(model.Money(6000), wallet().Balance)
+	t.Equal(model.Money(0), wallet().Reserved)
+
+	// A cancelled pending withdrawal cannot be confirmed anymore and releases its amount.
+	withdrawal, err = start(model.PaymentKindWithdrawal, 1000)
+	t.Require().NoError(err)
+	t.NoError(uc.Cancel(t.ctx, withdrawal.ID))
+	t.Equal(model.Money(0), wallet().Reserved)
+
+	_, err = uc.ConfirmPayment(t.ctx, withdrawal.ID)
+	t.Equal(true, errors.Is(err, model.ErrorPaymentNotPending))
+
+	mismatches, err := ledgerRepo.New(t.db.Connection).GetMismatches(t.ctx)
+	t.NoError(err)
+	t.Empty(mismatches)
+}
+
+func (t *transactionRepoTestSuite) TestAdjustments() {
+	uc := t.newUsecase(&config.Config{}, nil)
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	adjust := func(direction string, amount model.Money) (*model.TransactionView, error) {
+		return uc.Adjust(t.ctx, &model.Adjustment{
+			TransactionID: faker.UUIDHyphenated(),
+			Direction:     direction,
+			Amount:        amount,
+			Currency:      "EUR",
+			Reason:        "support ticket",
+			OperatorID:    "agent-7",
+			UserID:        userID,
+		})
+	}
+
+	credit, err := adjust(model.DirectionCredit, 1000)
+	t.Require().NoError(err)
+
+	_, err = adjust(model.DirectionDebit, 1001)
+	t.Equal(true, errors.Is(err, model.ErrorInsufficientBalance))
+
+	_, err = adjust(model.DirectionDebit, 400)
+	t.Require().NoError(err)
+
+	// Adjustments cannot be made through the transactions of the game servers and the payment provider.
+	err = uc.Process(t.ctx, &model.Transaction{
+		TransactionID: faker.UUIDHyphenated(),
+		UserID:        userID,
+		SourceType:    model.SourceTypeAdjustment,
+		State:         "win",
+		Amount:        1000,
+		Currency:      "EUR",
+	})
+	t.Equal(true, errors.Is(err, model.ErrorAdjustmentState))
+
+	page, err := uc.GetTransactions(t.ctx, &model.TransactionFilter{UserID: userID, SourceType: model.SourceTypeAdjustment, Limit: 10})
+	t.Require().NoError(err)
+	t.Len(page.Transactions, 2)
+	t.Equal(model.StateAdjustmentDebit, page.Transactions[0].State)
+	t.Equal(credit.ID, page.Transactions[1].ID)
+	t.Equal("agent-7", *page.Transactions[1].OperatorID)
+	t.Equal("support ticket", *page.Transactions[1].Reason)
+
+	// An adjustment must tell who made it and why.
+	tx := t.db.Connection.MustBegin().Tx
+	err = t.repo.CreateTransaction(tx, t.ctx, &model.TransactionDao{
+		UserID:        userID,
+		TransactionID: faker.UUIDHyphenated(),
+		SourceType:    model.SourceTypeAdjustment,
+		State:         model.StateAdjustmentCredit,
+		Amount:        100,
+		Currency:      "EUR",
+	})
+	t.Error(err)
+	t.NoError(tx.Rollback())
+
+	mismatches, err := ledgerRepo.New(t.db.Connection).GetMismatches(t.ctx)
+	t.NoError(err)
+	t.Empty(mismatches)
+}
+
+func (t *transactionRepoTestSuite) TestBatches() {
+	uc := t.newUsecase(&config.Config{}, nil)
+	users := userRepo.New(t.db.Connection)
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	balance := func() model.Money {
+		tx := t.db.Connection.MustBegin().Tx
+		defer tx.Rollback()
+
+		w, err := users.GetWalletForUpdate(tx, t.ctx, userID, "EUR")
+		t.Require().NoError(err)
+
+		return w.Balance
+	}
+
+	transaction := func(state string, amount model.Money) *model.Transaction {
+		return &model.Transaction{
+			TransactionID: faker.UUIDHyphenated(),
+			UserID:        userID,
+			SourceType:    "game",
+			State:         state,
+			Amount:        amount,
+			Currency:      "EUR",
+		}
+	}
+
+	before := balance()
+	win := transaction("win", 100)
+
+	// The loss of an atomic batch is more than the balance after the win, so the win is rolled back too.
+	results, err := uc.ProcessBatch(t.ctx, []*model.Transaction{win, transaction("lost", before+101)}, true)
+	t.Require().NoError(err)
+	t.Equal(model.BatchStatusSkipped, results[0].Status)
+	t.Equal(true, errors.Is(results[1].Err, model.ErrorInsufficientBalance))
+	t.Equal(before, balance())
+
+	results, err = uc.ProcessBatch(t.ctx, []*model.Transaction{win, transaction("lost", before+101), win}, false)
+	t.Require().NoError(err)
+	t.Equal(model.BatchStatusProcessed, results[0].Status)
+	t.Equal(model.BatchStatusFailed, results[1].Status)
+	t.Equal(model.BatchStatusDuplicate, results[2].Status)
+	t.Equal(before+100, balance())
+
+	results, err = uc.ProcessBatch(t.ctx, []*model.Transaction{transaction("lost", 50), win, transaction("win", 20)}, true)
+	t.Require().NoError(err)
+	t.Equal(model.BatchStatusProcessed, results[0].Status)
+	t.Equal(model.BatchStatusDuplicate, results[1].Status)
+	t.Equal(model.BatchStatusProcessed, results[2].Status)
+	t.Equal(before+70, balance())
+
+	mismatches, err := ledgerRepo.New(t.db.Connection).GetMismatches(t.ctx)
+	t.NoError(err)
+	t.Empty(mismatches)
+}
+
+func (t *transactionRepoTestSuite) TestImportAndExport() {
+	uc := t.newUsecase(&config.Config{Import: config.Import{ProgressEvery: 2}}, nil)
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	start := time.Now().Add(-time.Second)
+
+	lines := []string{}
+	for i := 0; i < 5; i++ {
+		lines = append(lines, fmt.Sprintf(`{"transactionId":"import-%d","userId":"%s","state":"win","amount":1,"currency":"EUR"}`, i, userID))
+	}
+
+	// The last line repeats the first one.
+	lines = append(lines, lines[0])
+
+	progress, err := uc.Import(t.ctx, strings.NewReader(strings.Join(lines, "\n")), "server", &discardReporter{})
+	t.Require().NoError(err)
+	t.Equal(&model.ImportProgress{Lines: 6, Processed: 5, Duplicates: 1, Done: true}, progress)
+
+	exported := []string{}
+	err = uc.Export(t.ctx, &model.ExportFilter{UserID: userID, From: &start, Format: model.ExportFormatNDJSON}, func(tr *model.TransactionView) error {
+		exported = append(exported, tr.TransactionID)
+
+		return nil
+	})
+	t.Require().NoError(err)
+	t.Equal([]string{"import-0", "import-1", "import-2", "import-3", "import-4"}, exported)
+
+	count := 0
+	err = t.repo.ExportTransactions(t.ctx, &model.ExportFilter{UserID: faker.UUIDHyphenated()}, func(*model.TransactionDao) error {
+		count++
+
+		return nil
+	})
+	t.Require().NoError(err)
+	t.Equal(0, count)
+
+	err = t.repo.ExportTransactions(t.ctx, &model.ExportFilter{To: &start}, func(tr *model.TransactionDao) error {
+		t.Equal(true, tr.CreatedAt.Before(start))
+
+		return nil
+	})
+	t.Require().NoError(err)
+}
+
+// discardReporter ignores what an import reports.
+type discardReporter struct{}
+
+func (discardReporter) Progress(model.ImportProgress) {}
+
+func (discardReporter) Failure(*model.ImportFailure) {}
+
+func (t *transactionRepoTestSuite) TestTransfers() {
+	uc := t.newUsecase(&config.Config{}, nil)
+	users := userRepo.New(t.db.Connection)
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	receiver := &model.UserDao{}
+	t.Require().NoError(users.CreateUser(t.ctx, receiver))
+
+	_, err := t.db.Connection.ExecContext(t.ctx, "INSERT INTO wallets (user_id, currency) VALUES ($1, 'EUR')", receiver.ID)
+	t.Require().NoError(err)
+
+	balance := func(id string) model.Money {
+		tx := t.db.Connection.MustBegin().Tx
+		defer tx.Rollback()
+
+		w, err := users.GetWalletForUpdate(tx, t.ctx, id, "EUR")
+		t.Require().NoError(err)
+
+		return w.Balance
+	}
+
+	before := balance(userID)
+
+	req := &model.Transfer{IdempotencyKey: faker.UUIDHyphenated(), FromUserID: userID, ToUserID: receiver.ID, Amount: 300, Currency: "EUR"}
+
+	transfer, err := uc.Transfer(t.ctx, req)
+	t.Require().NoError(err)
+	t.Equal(before-300, balance(userID))
+	t.Equal(model.Money(300), balance(receiver.ID))
+
+	// A retry returns the same transfer without moving the money again.
+	replay, err := uc.Transfer(t.ctx, req)
+	t.Require().NoError(err)
+	t.Equal(transfer.ID, replay.ID)
+	t.Equal(model.Money(300), balance(receiver.ID))
+
+	other := *req
+	other.Amount = 100
+	_, err = uc.Transfer(t.ctx, &other)
+	t.Equal(true, errors.Is(err, model.ErrorTransferAlreadyExists))
+
+	// The receiver cannot send more than it holds, and nothing moves when it fails.
+	_, err = uc.Transfer(t.ctx, &model.Transfer{IdempotencyKey: faker.UUIDHyphenated(), FromUserID: receiver.ID, ToUserID: userID, Amount: 301, Currency: "EUR"})
+	t.Equal(true, errors.Is(err, model.ErrorInsufficientBalance))
+	t.Equal(model.Money(300), balance(receiver.ID))
+
+	// The legs can be neither made by hand nor cancelled one by one.
+	err = uc.Process(t.ctx, &model.Transaction{
+		TransactionID: faker.UUIDHyphenated(),
+		UserID:        userID,
+		SourceType:    model.SourceTypeTransfer,
+		State:         model.StateTransferIn,
+		Amount:        1000,
+		Currency:      "EUR",
+	})
+	t.Equal(true, errors.Is(err, model.ErrorTransferState))
+
+	t.Equal(true, errors.Is(uc.Cancel(t.ctx, transfer.CreditTransactionID), model.ErrorTransferState))
+	t.Equal(model.Money(300), balance(receiver.ID))
+
+	page, err := uc.GetTransactions(t.ctx, &model.TransactionFilter{UserID: receiver.ID, Limit: 10})
+	t.Require().NoError(err)
+	t.Len(page.Transactions, 1)
+	t.Equal(transfer.CreditTransactionID, page.Transactions[0].ID)
+	t.Equal(model.StateTransferIn, page.Transactions[0].State)
+
+	mismatches, err := ledgerRepo.New(t.db.Connection).GetMismatches(t.ctx)
+	t.NoError(err)
+	t.Empty(mismatches)
+}
+
+func (t *transactionRepoTestSuite) TestBets() {
+	uc := t.newUsecase(&config.Config{}, nil)
+	users := userRepo.New(t.db.Connection)
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	place := func(stake model.Money) (*model.Bet, error) {
+		return uc.PlaceBet(t.ctx, &model.PlaceBet{
+			BetID:    faker.UUIDHyphenated(),
+			Stake:    stake,
+			Currency: "EUR",
+			UserID:   userID,
+		})
+	}
+
+	wallet := func() *model.WalletDao {
+		tx := t.db.Connection.MustBegin().Tx
+		defer tx.Rollback()
+
+		w, err := users.GetWalletForUpdate(tx, t.ctx, userID, "EUR")
+		t.Require().NoError(err)
+
+		return w
+	}
+
+	t.Require().NoError(uc.Process(t.ctx, &model.Transaction{
+		TransactionID: faker.UUIDHyphenated(),
+		UserID:        userID,
+		SourceType:    "payment",
+		State:         "win",
+		Amount:        10000,
+		Currency:      "EUR",
+	}))
+
+	// A placed bet holds its stake, which cannot be staked again.
+	lost, err := place(6000)
+	t.Require().NoError(err)
+	t.Equal(model.Money(10000), wallet().Balance)
+	t.Equal(model.Money(6000), wallet().Reserved)
+
+	_, err = place(5000)
+	t.Equal(true, errors.Is(err, model.ErrorInsufficientBalance))
+
+	lost, err = uc.SettleBet(t.ctx, &model.SettleBet{ID: lost.ID, Outcome: model.BetOutcomeLoss})
+	t.Require().NoError(err)
+	t.Equal(model.BetStateLost, lost.State)
+	t.Equal(model.Money(4000), wallet().Balance)
+	t.Equal(model.Money(0), wallet().Reserved)
+
+	tx := t.db.Connection.MustBegin().Tx
+	tr, err := t.repo.GetTransactionForUpdate(tx, t.ctx, *lost.TransactionID)
+	t.Require().NoError(err)
+	t.NoError(tx.Rollback())
+	t.Equal("lost", tr.State)
+	t.Equal(model.Money(6000), tr.Amount)
+
+	_, err = uc.VoidBet(t.ctx, lost.ID)
+	t.Equal(true, errors.Is(err, model.ErrorBetNotPlaced))
+
+	// A won bet keeps its stake and wins the amount on top of it.
+	won, err := place(1000)
+	t.Require().NoError(err)
+
+	_, err = uc.SettleBet(t.ctx, &model.SettleBet{ID: won.ID, Outcome: model.BetOutcomeWin, Amount: 2500})
+	t.Require().NoError(err)
+	t.Equal(model.Money(6500), wallet().Balance)
+	t.Equal(model.Money(0), wallet().Reserved)
+
+	// A voided bet only gives its stake back.
+	voided, err := place(500)
+	t.Require().NoError(err)
+
+	_, err = uc.VoidBet(t.ctx, voided.ID)
+	t.Require().NoError(err)
+	t.Equal(model.Money(6500), wallet().Balance)
+	t.Equal(model.Money(0), wallet().Reserved)
+
+	// An unsettled bet expires and gives its stake back.
+	conf := &config.Config{
+		Bets: config.Bets{TTL: time.Millisecond, Interval: time.Millisecond * 20, BatchSize: 10},
+	}
+	expiring := t.newUsecase(conf, nil)
+
+	expired, err := expiring.PlaceBet(t.ctx, &model.PlaceBet{BetID: faker.UUIDHyphenated(), Stake: 500, Currency: "EUR", UserID: userID})
+	t.Require().NoError(err)
+	t.Equal(model.Money(500), wallet().Reserved)
+
+	ctx, cancel := context.WithCancel(t.ctx)
+	go expiring.ExpireBets(ctx)
+
+	t.Eventually(func() bool {
+		return wallet().Reserved == 0
+	}, time.Second*5, time.Millisecond*20)
+
+	cancel()
+
+	_, err = uc.SettleBet(t.ctx, &model.SettleBet{ID: expired.ID, Outcome: model.BetOutcomeLoss})
+	t.Equal(true, errors.Is(err, model.ErrorBetNotPlaced))
+
+	mismatches, err := ledgerRepo.New(t.db.Connection).GetMismatches(t.ctx)
+	t.NoError(err)
+	t.Empty(mismatches)
+}
+
+func (t *transactionRepoTestSuite) TestLeaderElection() {
+	conf := &config.Config{
+		PostProcess: config.PostProcess{
+			Enabled:   true,
+			Interval:  time.Millisecond * 50,
+			BatchSize: 10,
+			LockKey:   42,
+		},
+	}
+
+	leaders := []*database.Leader{
+		database.NewLeader(t.db, conf.PostProcess.LockKey),
+		database.NewLeader(t.db, conf.PostProcess.LockKey),
+	}
+
+	ucs := []*usecase.Transaction{
+		t.newUsecase(conf, leaders[0]),
+		t.newUsecase(conf, leaders[1]),
+	}
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	for i := 0; i < 4; i++ {
+		t.NoError(ucs[0].Process(t.ctx, &model.Transaction{
+			TransactionID: faker.UUIDHyphenated(),
+			UserID:        userID,
+			SourceType:    "game",
+			State:         "win",
+			Amount:        100,
+			Currency:      "EUR",
+		}))
+	}
+
+	ctx, cancel := context.WithCancel(t.ctx)
+	defer cancel()
+
+	go ucs[0].PostProcess(ctx)
+	go ucs[1].PostProcess(ctx)
+
+	count := func(query string) int {
+		var n int
+
+		err := t.db.Connection.QueryRowContext(t.ctx, query).Scan(&n)
+		t.Require().NoError(err)
+
+		return n
+	}
+
+	t.Eventually(func() bool {
+		return count(`SELECT COUNT(*) FROM transactions WHERE cancelled = true`) == 2
+	}, time.Second*5, time.Millisecond*50)
+
+	first, err := leaders[0].IsLeader(ctx)
+	t.NoError(err)
+
+	second, err := leaders[1].IsLeader(ctx)
+	t.NoError(err)
+
+	t.NotEqual(first, second)
+
+	follower := leaders[1]
+	if second {
+		follower = leaders[0]
+	}
+
+	// The connection of the leader dies, so postgres releases its lock.
+	t.Equal(1, count(`SELECT COUNT(pg_terminate_backend(pid)) FROM pg_locks WHERE locktype = 'advisory'`))
+
+	t.Eventually(func() bool {
+		ok, err := follower.IsLeader(ctx)
+
+		return err == nil && ok
+	}, time.Second*5, time.Millisecond*50)
+
+	var balance model.Money
+
+	err = t.db.Connection.QueryRowContext(t.ctx, `SELECT balance FROM wallets WHERE user_id = $1 AND currency = 'EUR'`, userID).Scan(&balance)
+	t.Require().NoError(err)
+	t.Equal(model.Money(200), balance)
+
+	cancel()
+
+	t.Eventually(func() bool {
+		return !ucs[0].IsPostProcessRunning() && !ucs[1].IsPostProcessRunning()
+	}, time.Second*5, time.Millisecond*50)
+}
+
+func (t *transactionRepoTestSuite) TestPostProcessSkipsPaymentsAndAdjustments() {
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	var last int64
+
+	create := func(sourceType, state string) *model.TransactionDao {
+		tr := &model.TransactionDao{
+			UserID:        userID,
+			TransactionID: faker.UUIDHyphenated(),
+			SourceType:    sourceType,
+			State:         state,
+			Currency:      "EUR",
+		}
+
+		tx := t.db.Connection.MustBegin().Tx
+
+		t.Require().NoError(t.repo.CreateTransaction(tx, t.ctx, tr))
+		t.Require().NoError(tx.Commit())
+
+		last = tr.Seq
+
+		return tr
+	}
+
+	// createOdd inserts a game transaction first if needed, so the transaction gets an odd sequence number.
+	createOdd := func(sourceType, state string) *model.TransactionDao {
+		if last == 0 || last%2 == 0 {
+			create(model.SourceTypeGame, model.StateWin)
+		}
+
+		tr := create(sourceType, state)
+		t.Require().Equal(int64(1), tr.Seq%2)
+
+		return tr
+	}
+
+	create(model.SourceTypeGame, model.StateWin)
+
+	game := createOdd(model.SourceTypeGame, model.StateWin)
+	payment := createOdd(model.SourceTypePayment, model.StateDepositConfirmed)
+	adjustment := createOdd(model.SourceTypeAdjustment, model.StateAdjustmentCredit)
+	server := createOdd(model.SourceTypeServer, model.StateWin)
+
+	conf := &config.Config{
+		PostProcess: config.PostProcess{
+			Enabled:   true,
+			Interval:  time.Millisecond * 50,
+			BatchSize: 20,
+			LockKey:   43,
+		},
+	}
+	uc := t.newUsecase(conf, database.NewLeader(t.db, conf.PostProcess.LockKey))
+
+	ctx, cancel := context.WithCancel(t.ctx)
+	defer cancel()
+
+	go uc.PostProcess(ctx)
+
+	cancelled := func(tr *model.TransactionDao) bool {
+		var c bool
+
+		err := t.db.Connection.QueryRowContext(t.ctx, `SELECT cancelled FROM transactions WHERE id = $1`, tr.ID).Scan(&c)
+		t.Require().NoError(err)
+
+		return c
+	}
+
+	t.Eventually(func() bool {
+		return cancelled(game) && cancelled(server)
+	}, time.Second*5, time.Millisecond*50)
+
+	cancel()
+
+	t.Eventually(func() bool {
+		return !uc.IsPostProcessRunning()
+	}, time.Second*5, time.Millisecond*50)
+
+	t.Equal(false, cancelled(payment))
+	t.Equal(false, cancelled(adjustment))
+
+	result, err := t.repo.GetLatestOddAndUncancelledTransactions(t.ctx, 20)
+	t.NoError(err)
+	t.Empty(result)
+}
+
+func (t *transactionRepoTestSuite) TestGetUserTransactions() {
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	for i, state := range []string{"win", "lost", "win", "win", "lost"} {
+		transaction := &model.TransactionDao{
+			UserID:        userID,
+			TransactionID: faker.UUIDHyphenated(),
+			SourceType:    "game",
+			State:         state,
+			Amount:        model.Money(i + 1),
+			Currency:      "EUR",
+		}
+
+		tx := t.db.Connection.MustBegin().Tx
+
+		t.NoError(t.repo.CreateTransaction(tx, t.ctx, transaction))
+		t.NoError(tx.Commit())
+	}
+
+	all, err := t.repo.GetUserTransactions(t.ctx, &model.TransactionFilter{UserID: userID, Limit: 10})
+	t.NoError(err)
+	t.Equal(5, len(all))
+	t.Equal(model.Money(5), all[0].Amount)
+	t.Nil(all[0].CancelledAt)
+
+	page, err := t.repo.GetUserTransactions(t.ctx, &model.TransactionFilter{UserID: userID, Cursor: all[1].Seq, Limit: 2})
+	t.NoError(err)
+	t.Equal(2, len(page))
+	t.Equal(all[2].ID, page[0].ID)
+	t.Equal(all[3].ID, page[1].ID)
+
+	wins, err := t.repo.GetUserTransactions(t.ctx, &model.TransactionFilter{UserID: userID, State: "win", Limit: 10})
+	t.NoError(err)
+	t.Equal(3, len(wins))
+
+	_, err = t.db.Connection.ExecContext(t.ctx, `UPDATE transactions SET cancelled = true, cancelled_at = NOW() WHERE id = $1`, all[0].ID)
+	t.NoError(err)
+
+	cancelled := true
+
+	result, err := t.repo.GetUserTransactions(t.ctx, &model.TransactionFilter{UserID: userID, Cancelled: &cancelled, Limit: 10})
+	t.NoError(err)
+	t.Equal(1, len(result))
+	t.NotNil(result[0].CancelledAt)
+
+	from := time.Now().Add(time.Hour)
+
+	result, err = t.repo.GetUserTransactions(t.ctx, &model.TransactionFilter{UserID: userID, From: &from, Limit: 10})
 	t.NoError(err)
+	t.Empty(result)
 
-	transactions, err := t.repo.GetLatestOddAndUncancelledTransactions(t.ctx, 10)
+	result, err = t.repo.GetUserTransactions(t.ctx, &model.TransactionFilter{UserID: userID, SourceType: "payment", Limit: 10})
 	t.NoError(err)
-	t.NotEmpty(transactions)
-	t.Equal(1, len(transactions))
+	t.Empty(result)
 }
@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"context"
+	"io"
 
 	"github.com/ttagiyeva/entain/internal/model"
 )
@@ -11,5 +12,28 @@ import (
 //go:generate mockgen -source ./usecase.go -mock_names Repository=MockTransactionUsecase -package mocks -destination mocks/transactionUsecase.mock.gen.go
 type Usecase interface {
 	Process(context.Context, *model.Transaction) error
+	ProcessBatch(ctx context.Context, trs []*model.Transaction, atomic bool) ([]*model.BatchResult, error)
+	Import(ctx context.Context, r io.Reader, sourceType string, reporter ImportReporter) (*model.ImportProgress, error)
+	Export(ctx context.Context, filter *model.ExportFilter, fn func(*model.TransactionView) error) error
+	Cancel(ctx context.Context, id string) error
+	GetTransactions(ctx context.Context, filter *model.TransactionFilter) (*model.TransactionPage, error)
+	GrantBonus(ctx context.Context, bonus *model.Bonus) error
+	StartPayment(ctx context.Context, p *model.Payment) (*model.TransactionView, error)
+	ConfirmPayment(ctx context.Context, id string) (*model.TransactionView, error)
+	FailPayment(ctx context.Context, id string) (*model.TransactionView, error)
+	Adjust(ctx context.Context, a *model.Adjustment) (*model.TransactionView, error)
+	Transfer(ctx context.Context, req *model.Transfer) (*model.TransferView, error)
+	PlaceBet(ctx context.Context, req *model.PlaceBet) (*model.Bet, error)
+	SettleBet(ctx context.Context, req *model.SettleBet) (*model.Bet, error)
+	VoidBet(ctx context.Context, id string) (*model.Bet, error)
 	PostProcess(ctx context.Context)
+	IsPostProcessRunning() bool
+	ExpireBets(ctx context.Context)
+	IsBetExpiryRunning() bool
+}
+
+// ImportReporter receives the progress of an import and its lines which could not be processed.
+type ImportReporter interface {
+	Progress(p model.ImportProgress)
+	Failure(f *model.ImportFailure)
 }
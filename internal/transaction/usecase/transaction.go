@@ -1,125 +1,1397 @@
 package usecase
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"math/rand"
+	"sort"
+	"sync/atomic"
 	"time"
 
+	"github.com/ttagiyeva/entain/internal/bet"
+	"github.com/ttagiyeva/entain/internal/config"
+	"github.com/ttagiyeva/entain/internal/exclusion"
+	"github.com/ttagiyeva/entain/internal/ledger"
+	"github.com/ttagiyeva/entain/internal/limit"
 	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/registry"
 	"github.com/ttagiyeva/entain/internal/transaction"
+	"github.com/ttagiyeva/entain/internal/transfer"
 	"github.com/ttagiyeva/entain/internal/user"
 )
 
 const (
-	// Interval defines the interval with second for cancel transaction process.
-	interval = 1
+	// defaultInterval is used when the configured post process interval is not valid.
+	defaultInterval = time.Second
+	// defaultBatchSize is used when the configured post process batch size is not valid.
+	defaultBatchSize = 10
+	// defaultBetTTL is used when the configured bet ttl is not valid.
+	defaultBetTTL = 30 * time.Minute
+	// defaultProgressEvery is used when the configured import progress interval is not valid.
+	defaultProgressEvery = 1000
+	// maxImportLineSize is the longest line an import reads, it bounds the memory an import uses.
+	maxImportLineSize = 64 * 1024
 )
 
 // Transaction is a structure which manages transaction usecase.
 type Transaction struct {
 	log             *slog.Logger
+	conf            config.PostProcess
+	betConf         config.Bets
+	importConf      config.Import
 	transactionRepo transaction.Repository
 	userRepo        user.Repository
+	ledgerRepo      ledger.Repository
+	limitRepo       limit.Repository
+	exclusionRepo   exclusion.Repository
+	registry        registry.Usecase
+	betRepo         bet.Repository
+	transferRepo    transfer.Repository
 	db              transaction.Database
+	elector         transaction.Elector
+	running         atomic.Bool
+	expiryRunning   atomic.Bool
 }
 
 // New creates a new transaction usecase.
-func New(log *slog.Logger, r transaction.Repository, u user.Repository, d transaction.Database) *Transaction {
+func New(
+	log *slog.Logger,
+	conf *config.Config,
+	r transaction.Repository,
+	u user.Repository,
+	l ledger.Repository,
+	lr limit.Repository,
+	x exclusion.Repository,
+	rg registry.Usecase,
+	b bet.Repository,
+	tf transfer.Repository,
+	d transaction.Database,
+	e transaction.Elector,
+) *Transaction {
 	return &Transaction{
 		log:             log,
+		conf:            conf.PostProcess,
+		betConf:         conf.Bets,
+		importConf:      conf.Import,
 		transactionRepo: r,
 		userRepo:        u,
+		ledgerRepo:      l,
+		limitRepo:       lr,
+		exclusionRepo:   x,
+		registry:        rg,
+		betRepo:         b,
+		transferRepo:    tf,
 		db:              d,
+		elector:         e,
 	}
 }
 
-// Process processes a transaction.
+// Process processes a transaction against the wallet of the user in the currency of the transaction.
+// The user row is locked first, so every step below is serialized per user inside a single db tx.
+// The state of the transaction decides through the registry whether it credits, debits or leaves the wallet as is.
 func (t *Transaction) Process(ctx context.Context, tr *model.Transaction) error {
-	exist, err := t.transactionRepo.CheckExistance(ctx, tr.TransactionID)
+	_, err := t.processInTx(ctx, tr)
+
+	return err
+}
+
+// ProcessBatch processes the transactions of a batch and returns the result of each of them in the same order.
+// Every transaction is processed in its own db tx unless atomic is set, in which case the users of the batch are
+// locked in the order of their ids and the whole batch is processed in a single db tx, so the first failing
+// transaction rolls back the others.
+func (t *Transaction) ProcessBatch(ctx context.Context, trs []*model.Transaction, atomic bool) ([]*model.BatchResult, error) {
+	if atomic {
+		return t.processAtomic(ctx, trs)
+	}
+
+	results := make([]*model.BatchResult, len(trs))
+
+	for i, tr := range trs {
+		replayed, err := t.processInTx(ctx, tr)
+		results[i] = model.NewBatchResult(tr, replayed, err)
+	}
+
+	return results, nil
+}
+
+// Import processes the newline-delimited json transactions read from r one at a time the way Process does,
+// so the memory it uses does not grow with the input. sourceType, unless empty, overrides the source type of
+// every line. A line which cannot be processed is reported and skipped, the progress is reported every configured
+// number of lines and once the input is read. Import stops at the first error reading r or once ctx is done.
+func (t *Transaction) Import(ctx context.Context, r io.Reader, sourceType string, reporter transaction.ImportReporter) (*model.ImportProgress, error) {
+	reg, err := t.registry.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the registry: %w", err)
+	}
+
+	every := t.importConf.ProgressEvery
+	if every <= 0 {
+		every = defaultProgressEvery
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxImportLineSize)
+
+	progress := &model.ImportProgress{}
+
+	for scanner.Scan() {
+		err = ctx.Err()
+		if err != nil {
+			return progress, fmt.Errorf("failed to finish the import: %w", err)
+		}
+
+		progress.Lines++
+
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) > 0 {
+			tr, replayed, err := t.importLine(ctx, reg, line, sourceType)
+
+			switch {
+			case err != nil:
+				progress.Failed++
+				reporter.Failure(&model.ImportFailure{Line: progress.Lines, TransactionID: tr.TransactionID, Err: err})
+			case replayed:
+				progress.Duplicates++
+			default:
+				progress.Processed++
+			}
+		}
+
+		if progress.Lines%every == 0 {
+			reporter.Progress(*progress)
+		}
+	}
+
+	err = scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		return progress, fmt.Errorf("failed because line %d is longer than %d bytes: %w", progress.Lines+1, maxImportLineSize, model.ErrorInvalidLine)
+	}
+
+	if err != nil {
+		return progress, fmt.Errorf("failed to read line %d: %w", progress.Lines+1, err)
+	}
+
+	progress.Done = true
+	reporter.Progress(*progress)
+
+	return progress, nil
+}
+
+// importLine decodes, validates and processes a line of an import.
+func (t *Transaction) importLine(ctx context.Context, reg *model.Registry, line []byte, sourceType string) (*model.Transaction, bool, error) {
+	tr := &model.Transaction{}
+
+	err := json.Unmarshal(line, tr)
+	if err != nil {
+		return tr, false, fmt.Errorf("failed to decode the line: %w: %w", model.ErrorInvalidLine, err)
+	}
+
+	if sourceType != "" {
+		tr.SourceType = sourceType
+	}
+
+	err = reg.Validator().Struct(tr)
+	if err != nil {
+		return tr, false, fmt.Errorf("failed to validate the line: %w", err)
+	}
+
+	replayed, err := t.processInTx(ctx, tr)
+
+	return tr, replayed, err
+}
+
+// Export calls fn with every stored transaction matching the filter in the order they were made.
+// The rows are streamed from the database, so the memory it uses does not grow with their number.
+func (t *Transaction) Export(ctx context.Context, filter *model.ExportFilter, fn func(*model.TransactionView) error) error {
+	err := t.transactionRepo.ExportTransactions(ctx, filter, func(tr *model.TransactionDao) error {
+		return fn(model.TransactionDaoToTransactionView(tr))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export transactions: %w", err)
+	}
+
+	return nil
+}
+
+// processAtomic processes the transactions of a batch in a single db tx.
+func (t *Transaction) processAtomic(ctx context.Context, trs []*model.Transaction) ([]*model.BatchResult, error) {
+	directions := make([]string, len(trs))
+
+	for i, tr := range trs {
+		direction, err := t.check(ctx, tr)
+		if err != nil {
+			return model.FailBatch(trs, i, err), nil
+		}
+
+		directions[i] = direction
+	}
+
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	// Concurrent batches of the same users lock them in the same order and cannot deadlock.
+	first := make(map[string]int, len(trs))
+	for i := len(trs) - 1; i >= 0; i-- {
+		first[trs[i].UserID] = i
+	}
+
+	userIDs := make([]string, 0, len(first))
+	for id := range first {
+		userIDs = append(userIDs, id)
+	}
+
+	sort.Strings(userIDs)
+
+	for _, id := range userIDs {
+		_, err = t.userRepo.GetUserForUpdate(tx, ctx, id)
+		if err != nil {
+			return model.FailBatch(trs, first[id], t.rollback(tx, fmt.Errorf("failed to get user: %w", err))), nil
+		}
+	}
+
+	results := make([]*model.BatchResult, len(trs))
+
+	for i, tr := range trs {
+		replayed, err := t.process(tx, ctx, tr, directions[i])
+		if err != nil {
+			return model.FailBatch(trs, i, t.rollback(tx, err)), nil
+		}
+
+		results[i] = model.NewBatchResult(tr, replayed, nil)
+	}
+
+	err = t.db.Commit(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return results, nil
+}
+
+// processInTx processes a transaction in its own db tx and reports whether it was a replay of a processed one.
+func (t *Transaction) processInTx(ctx context.Context, tr *model.Transaction) (bool, error) {
+	direction, err := t.check(ctx, tr)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	replayed, err := t.process(tx, ctx, tr, direction)
+	if err != nil {
+		return false, t.rollback(tx, err)
+	}
+
+	if replayed {
+		// The provider retries an already processed transaction, so the original outcome is returned.
+		err = t.db.Rollback(tx)
+		if err != nil {
+			return false, fmt.Errorf("failed to rollback the db tx of the replayed transaction: %w", err)
+		}
+
+		return true, nil
+	}
+
+	err = t.db.Commit(tx)
+	if err != nil {
+		return false, fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return false, nil
+}
+
+// check refuses the transactions which are made by other flows and returns the direction of the state.
+func (t *Transaction) check(ctx context.Context, tr *model.Transaction) (string, error) {
+	if model.IsPaymentState(tr.State) {
+		return "", fmt.Errorf("failed because %s is a payment state: %w", tr.State, model.ErrorPaymentState)
+	}
+
+	if tr.IsAdjustment() {
+		return "", fmt.Errorf("failed because %s %s is an adjustment: %w", tr.SourceType, tr.State, model.ErrorAdjustmentState)
+	}
+
+	if tr.IsTransfer() {
+		return "", fmt.Errorf("failed because %s %s is a transfer: %w", tr.SourceType, tr.State, model.ErrorTransferState)
+	}
+
+	return t.direction(ctx, tr.State)
+}
+
+// process processes a transaction within the given db tx, which the caller commits or rolls back.
+// It reports whether the transaction is a replay of an already processed one, which changes nothing.
+func (t *Transaction) process(tx *sql.Tx, ctx context.Context, tr *model.Transaction, direction string) (bool, error) {
+	user, err := t.userRepo.GetUserForUpdate(tx, ctx, tr.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	existing, err := t.transactionRepo.GetTransactionByTransactionID(tx, ctx, tr.TransactionID)
+	if err != nil && !errors.Is(err, model.ErrorTransactionNotFound) {
+		return false, fmt.Errorf("failed to check transaction existance: %w", err)
+	}
+
+	if existing != nil {
+		if !existing.IsReplayOf(tr) {
+			return false, fmt.Errorf("failed because the transaction already exists: %w", model.ErrorTransactionAlreadyExists)
+		}
+
+		return true, nil
+	}
+
+	err = user.StatusError()
+	if err != nil {
+		return false, fmt.Errorf("failed because the user cannot make transactions: %w", err)
+	}
+
+	err = t.checkExclusion(tx, ctx, tr.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed because the user cannot make transactions: %w", err)
+	}
+
+	wallet, err := t.userRepo.GetWalletForUpdate(tx, ctx, tr.UserID, tr.Currency)
+	if err != nil {
+		return false, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	m, err := wallet.Settle(direction, tr.Amount)
+	if err != nil {
+		return false, fmt.Errorf("failed because balance of the user is not enough: %w", err)
+	}
+
+	err = t.checkLimits(tx, ctx, tr, direction)
+	if err != nil {
+		return false, fmt.Errorf("failed because of a responsible gambling limit: %w", err)
+	}
+
+	trDao := model.TransactionToTransactionDao(tr)
+	trDao.BonusAmount = m.BonusPart()
+
+	err = t.transactionRepo.CreateTransaction(tx, ctx, trDao)
+	if err != nil {
+		return false, fmt.Errorf("failed to create the transaction: %w", err)
+	}
+
+	err = t.changeBalance(tx, ctx, wallet, m, model.LedgerReasonTransaction, &trDao.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update user balance: %w", err)
+	}
+
+	return false, nil
+}
+
+// direction returns the direction of a state in the registry.
+func (t *Transaction) direction(ctx context.Context, state string) (string, error) {
+	reg, err := t.registry.Get(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the registry: %w", err)
+	}
+
+	direction, ok := reg.Direction(state)
+	if !ok {
+		return "", fmt.Errorf("failed because state %s is not registered: %w", state, model.ErrorUnknownState)
+	}
+
+	return direction, nil
+}
+
+// checkExclusion returns ErrorUserSelfExcluded while the user has an exclusion in effect.
+func (t *Transaction) checkExclusion(tx *sql.Tx, ctx context.Context, userID string) error {
+	e, err := t.exclusionRepo.GetActiveExclusion(tx, ctx, userID)
+	if errors.Is(err, model.ErrorExclusionNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get exclusion: %w", err)
+	}
+
+	return fmt.Errorf("%s exclusion started at %s: %w", e.Period, e.StartedAt.Format(time.RFC3339), model.ErrorUserSelfExcluded)
+}
+
+// checkLimits refuses a transaction which would take the user over one of their limits in its currency.
+// The wallet is locked by the caller, so the usage cannot change until the transaction is stored.
+func (t *Transaction) checkLimits(tx *sql.Tx, ctx context.Context, tr *model.Transaction, direction string) error {
+	kind := tr.LimitKind(direction)
+	if kind == "" {
+		return nil
+	}
+
+	limits, err := t.limitRepo.GetLimitsByKind(tx, ctx, tr.UserID, tr.Currency, kind)
+	if err != nil {
+		return fmt.Errorf("failed to get limits: %w", err)
+	}
+
+	now := time.Now()
+
+	for _, l := range limits {
+		l.Settle(now)
+
+		usage, err := t.limitRepo.GetUsage(tx, ctx, tr.UserID, tr.Currency, kind, l.PeriodStart(now))
+		if err != nil {
+			return fmt.Errorf("failed to get limit usage: %w", err)
+		}
+
+		if usage+tr.Amount <= l.Amount {
+			continue
+		}
+
+		if kind == model.LimitKindDeposit {
+			return fmt.Errorf("%s on top of %s exceeds the %s limit of %s: %w", tr.Amount, usage, l.Period, l.Amount, model.ErrorDepositLimitExceeded)
+		}
+
+		return fmt.Errorf("%s on top of %s exceeds the %s limit of %s: %w", tr.Amount, usage, l.Period, l.Amount, model.ErrorLossLimitExceeded)
+	}
+
+	return nil
+}
+
+// Cancel cancels a transaction and reverses its effect on the wallet of the user in the same db tx,
+// a cancelled win is subtracted and a cancelled loss is refunded, see model.WalletDao.Reverse for the buckets.
+// If the user has already spent the won amount, the cancellation is refused and the transaction stays as is.
+func (t *Transaction) Cancel(ctx context.Context, id string) error {
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	tr, err := t.transactionRepo.CancelTransaction(tx, ctx, id)
+	if err != nil {
+		return t.rollback(tx, fmt.Errorf("failed to cancel the transaction: %w", err))
+	}
+
+	// Cancelling a single leg of a transfer would create or destroy money.
+	if tr.SourceType == model.SourceTypeTransfer {
+		return t.rollback(tx, fmt.Errorf("failed because transaction %s is a leg of a transfer: %w", tr.ID, model.ErrorTransferState))
+	}
+
+	wallet, err := t.userRepo.GetWalletForUpdate(tx, ctx, tr.UserID, tr.Currency)
+	if err != nil {
+		return t.rollback(tx, fmt.Errorf("failed to get wallet: %w", err))
+	}
+
+	m, err := t.reverse(ctx, wallet, tr)
+	if err != nil {
+		return t.rollback(tx, fmt.Errorf("failed to reverse %s of transaction %s: %w", tr.Amount, tr.ID, err))
+	}
+
+	err = t.changeBalance(tx, ctx, wallet, m, model.LedgerReasonCancellation, &tr.ID)
+	if err != nil {
+		if errors.Is(err, model.ErrorInsufficientBalance) {
+			err = model.ErrorCancellationInsufficientBalance
+		}
+
+		return t.rollback(tx, fmt.Errorf("failed to update user balance: %w", err))
+	}
+
+	err = t.db.Commit(tx)
+	if err != nil {
+		return fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return nil
+}
+
+// reverse returns the movement which reverses the transaction, payments and adjustments only ever moved real money.
+func (t *Transaction) reverse(ctx context.Context, wallet *model.WalletDao, tr *model.TransactionDao) (*model.WalletMovement, error) {
+	if model.IsPaymentState(tr.State) || tr.SourceType == model.SourceTypeAdjustment {
+		return wallet.ReversePayment(tr.State, tr.Amount)
+	}
+
+	direction, err := t.direction(ctx, tr.State)
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet.Reverse(direction, tr.Amount, tr.BonusAmount)
+}
+
+// GetTransactions returns a page of the transaction history of a user, the latest transactions first.
+func (t *Transaction) GetTransactions(ctx context.Context, filter *model.TransactionFilter) (*model.TransactionPage, error) {
+	_, err := t.userRepo.GetUser(ctx, filter.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// One more row than requested tells whether there is a next page.
+	query := *filter
+	query.Limit++
+
+	transactions, err := t.transactionRepo.GetUserTransactions(ctx, &query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user transactions: %w", err)
+	}
+
+	page := &model.TransactionPage{
+		Transactions: make([]*model.TransactionView, 0, len(transactions)),
+	}
+
+	if len(transactions) > filter.Limit {
+		transactions = transactions[:filter.Limit]
+		page.NextCursor = model.EncodeCursor(transactions[len(transactions)-1].Seq)
+	}
+
+	for _, tr := range transactions {
+		page.Transactions = append(page.Transactions, model.TransactionDaoToTransactionView(tr))
+	}
+
+	return page, nil
+}
+
+// GrantBonus credits bonus funds to the wallet of a user and adds their wagering to the outstanding requirement.
+func (t *Transaction) GrantBonus(ctx context.Context, bonus *model.Bonus) error {
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	user, err := t.userRepo.GetUserForUpdate(tx, ctx, bonus.UserID)
+	if err != nil {
+		return t.rollback(tx, fmt.Errorf("failed to get user: %w", err))
+	}
+
+	err = user.StatusError()
+	if err != nil {
+		return t.rollback(tx, fmt.Errorf("failed because the user cannot get a bonus: %w", err))
+	}
+
+	err = t.checkExclusion(tx, ctx, bonus.UserID)
+	if err != nil {
+		return t.rollback(tx, fmt.Errorf("failed because the user cannot get a bonus: %w", err))
+	}
+
+	wallet, err := t.userRepo.GetWalletForUpdate(tx, ctx, bonus.UserID, bonus.Currency)
+	if err != nil {
+		return t.rollback(tx, fmt.Errorf("failed to get wallet: %w", err))
+	}
+
+	m := &model.WalletMovement{
+		Bonus:            bonus.Amount,
+		WageringRequired: bonus.Wagering,
+	}
+
+	err = t.changeBalance(tx, ctx, wallet, m, model.LedgerReasonBonus, nil)
+	if err != nil {
+		return t.rollback(tx, fmt.Errorf("failed to update user balance: %w", err))
+	}
+
+	err = t.db.Commit(tx)
+	if err != nil {
+		return fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return nil
+}
+
+// StartPayment stores a pending deposit or withdrawal, a withdrawal reserves its amount from the available
+// real money until it is completed. Deposits count towards the deposit limits while they are pending already,
+// and are refused for self-excluded users, while withdrawals are always possible.
+func (t *Transaction) StartPayment(ctx context.Context, p *model.Payment) (*model.TransactionView, error) {
+	tr := p.Transaction()
+
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	user, err := t.userRepo.GetUserForUpdate(tx, ctx, tr.UserID)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to get user: %w", err))
+	}
+
+	existing, err := t.transactionRepo.GetTransactionByTransactionID(tx, ctx, tr.TransactionID)
+	if err != nil && !errors.Is(err, model.ErrorTransactionNotFound) {
+		return nil, t.rollback(tx, fmt.Errorf("failed to check transaction existance: %w", err))
+	}
+
+	if existing != nil {
+		if !existing.IsReplayOf(tr) {
+			return nil, t.rollback(tx, fmt.Errorf("failed because the transaction already exists: %w", model.ErrorTransactionAlreadyExists))
+		}
+
+		err = t.db.Rollback(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rollback the db tx of the replayed payment: %w", err)
+		}
+
+		return model.TransactionDaoToTransactionView(existing), nil
+	}
+
+	err = user.StatusError()
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed because the user cannot make payments: %w", err))
+	}
+
+	wallet, err := t.userRepo.GetWalletForUpdate(tx, ctx, tr.UserID, tr.Currency)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to get wallet: %w", err))
+	}
+
+	m := &model.WalletMovement{}
+
+	if p.Kind == model.PaymentKindDeposit {
+		err = t.checkExclusion(tx, ctx, tr.UserID)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed because the user cannot deposit: %w", err))
+		}
+
+		err = t.checkLimits(tx, ctx, tr, model.DirectionCredit)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed because of a responsible gambling limit: %w", err))
+		}
+	} else {
+		m, err = wallet.Reserve(tr.Amount)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed because balance of the user is not enough: %w", err))
+		}
+	}
+
+	trDao := model.TransactionToTransactionDao(tr)
+
+	err = t.transactionRepo.CreateTransaction(tx, ctx, trDao)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to create the transaction: %w", err))
+	}
+
+	err = t.changeBalance(tx, ctx, wallet, m, model.LedgerReasonTransaction, &trDao.ID)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to update user balance: %w", err))
+	}
+
+	err = t.db.Commit(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return model.TransactionDaoToTransactionView(trDao), nil
+}
+
+// ConfirmPayment confirms a pending payment, a deposit is credited and a withdrawal takes its reserved amount.
+func (t *Transaction) ConfirmPayment(ctx context.Context, id string) (*model.TransactionView, error) {
+	return t.completePayment(ctx, id, true)
+}
+
+// FailPayment fails a pending payment, a withdrawal releases its reserved amount.
+func (t *Transaction) FailPayment(ctx context.Context, id string) (*model.TransactionView, error) {
+	return t.completePayment(ctx, id, false)
+}
+
+// completePayment moves a pending payment to its confirmed or failed state and applies it to the wallet.
+func (t *Transaction) completePayment(ctx context.Context, id string, confirmed bool) (*model.TransactionView, error) {
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	tr, err := t.transactionRepo.GetTransactionForUpdate(tx, ctx, id)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to get the transaction: %w", err))
+	}
+
+	state := tr.CompletedState(confirmed)
+	if state == "" {
+		return nil, t.rollback(tx, fmt.Errorf("failed because transaction %s is %s: %w", tr.ID, tr.State, model.ErrorPaymentNotPending))
+	}
+
+	wallet, err := t.userRepo.GetWalletForUpdate(tx, ctx, tr.UserID, tr.Currency)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to get wallet: %w", err))
+	}
+
+	err = t.transactionRepo.UpdateTransactionState(tx, ctx, tr.ID, state)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to update the transaction state: %w", err))
+	}
+
+	tr.State = state
+
+	err = t.changeBalance(tx, ctx, wallet, wallet.CompletePayment(state, tr.Amount), model.LedgerReasonTransaction, &tr.ID)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to update user balance: %w", err))
+	}
+
+	err = t.db.Commit(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return model.TransactionDaoToTransactionView(tr), nil
+}
+
+// Adjust credits or debits real money of a user by hand and stores it as an adjustment transaction with
+// the operator and the reason, so it is unique by its transactionId, recorded in the ledger and listed in
+// the history like any other transaction. Support staff fix balances, so the status, exclusion and limits
+// of the user do not apply, while a debit can still not take more than the available real money.
+func (t *Transaction) Adjust(ctx context.Context, a *model.Adjustment) (*model.TransactionView, error) {
+	tr := a.Transaction()
+
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	_, err = t.userRepo.GetUserForUpdate(tx, ctx, tr.UserID)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to get user: %w", err))
+	}
+
+	existing, err := t.transactionRepo.GetTransactionByTransactionID(tx, ctx, tr.TransactionID)
+	if err != nil && !errors.Is(err, model.ErrorTransactionNotFound) {
+		return nil, t.rollback(tx, fmt.Errorf("failed to check transaction existance: %w", err))
+	}
+
+	if existing != nil {
+		if !existing.IsReplayOf(tr) {
+			return nil, t.rollback(tx, fmt.Errorf("failed because the transaction already exists: %w", model.ErrorTransactionAlreadyExists))
+		}
+
+		err = t.db.Rollback(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rollback the db tx of the replayed adjustment: %w", err)
+		}
+
+		return model.TransactionDaoToTransactionView(existing), nil
+	}
+
+	wallet, err := t.userRepo.GetWalletForUpdate(tx, ctx, tr.UserID, tr.Currency)
 	if err != nil {
-		return fmt.Errorf("failed to check transaction existance: %w", err)
+		return nil, t.rollback(tx, fmt.Errorf("failed to get wallet: %w", err))
 	}
 
-	if exist {
-		return fmt.Errorf("failed because the transaction already exists: %w", model.ErrorTransactionAlreadyExists)
+	m, err := wallet.MoveReal(tr.State, tr.Amount)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed because balance of the user is not enough: %w", err))
 	}
 
-	user, err := t.userRepo.GetUser(ctx, tr.UserID)
+	trDao := model.TransactionToTransactionDao(tr)
+	trDao.OperatorID = &a.OperatorID
+	trDao.Reason = &a.Reason
+
+	err = t.transactionRepo.CreateTransaction(tx, ctx, trDao)
 	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
+		return nil, t.rollback(tx, fmt.Errorf("failed to create the transaction: %w", err))
 	}
 
-	switch tr.State {
-	case "win":
-		user.Balance += tr.Amount
-	case "lost":
-		user.Balance -= tr.Amount
+	err = t.changeBalance(tx, ctx, wallet, m, model.LedgerReasonAdjustment, &trDao.ID)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to update user balance: %w", err))
 	}
 
-	if user.Balance < 0 {
-		return fmt.Errorf("failed because balance of the user is not enough: %w", model.ErrorInsufficientBalance)
+	err = t.db.Commit(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit the db tx: %w", err)
 	}
 
+	return model.TransactionDaoToTransactionView(trDao), nil
+}
+
+// Transfer moves real money from the wallet of a user to the wallet of another one in a single db tx, recorded as
+// a debiting transaction of the sender and a crediting one of the receiver which the transfer links. Both users and
+// then both wallets are locked in the order of their ids, so opposite transfers between the same users cannot deadlock.
+// A retry with the same idempotency key returns the original transfer.
+func (t *Transaction) Transfer(ctx context.Context, req *model.Transfer) (*model.TransferView, error) {
+	debit, credit := req.Transactions()
+
+	userIDs := []string{req.FromUserID, req.ToUserID}
+	sort.Strings(userIDs)
+
 	tx, err := t.db.BeginTx(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin a db tx: %w", err)
+		return nil, fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	users := make([]*model.UserDao, 0, len(userIDs))
+
+	for _, id := range userIDs {
+		user, err := t.userRepo.GetUserForUpdate(tx, ctx, id)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed to get user %s: %w", id, err))
+		}
+
+		users = append(users, user)
+	}
+
+	existing, err := t.transferRepo.GetTransferByIdempotencyKey(tx, ctx, req.IdempotencyKey)
+	if err != nil && !errors.Is(err, model.ErrorTransferNotFound) {
+		return nil, t.rollback(tx, fmt.Errorf("failed to check transfer existance: %w", err))
+	}
+
+	if existing != nil {
+		if !existing.IsReplayOf(req) {
+			return nil, t.rollback(tx, fmt.Errorf("failed because the transfer already exists: %w", model.ErrorTransferAlreadyExists))
+		}
+
+		err = t.db.Rollback(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rollback the db tx of the replayed transfer: %w", err)
+		}
+
+		return model.TransferDaoToTransferView(existing), nil
+	}
+
+	for _, user := range users {
+		err = user.StatusError()
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed because user %s cannot make transfers: %w", user.ID, err))
+		}
+	}
+
+	wallets := make(map[string]*model.WalletDao, len(userIDs))
+
+	for _, id := range userIDs {
+		wallet, err := t.userRepo.GetWalletForUpdate(tx, ctx, id, req.Currency)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed to get wallet of user %s: %w", id, err))
+		}
+
+		wallets[id] = wallet
+	}
+
+	transfer := &model.TransferDao{
+		IdempotencyKey: req.IdempotencyKey,
+		FromUserID:     req.FromUserID,
+		ToUserID:       req.ToUserID,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+	}
+
+	for _, tr := range []*model.Transaction{debit, credit} {
+		wallet := wallets[tr.UserID]
+
+		m, err := wallet.MoveReal(tr.State, tr.Amount)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed because balance of the user is not enough: %w", err))
+		}
+
+		trDao := model.TransactionToTransactionDao(tr)
+
+		err = t.transactionRepo.CreateTransaction(tx, ctx, trDao)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed to create the transaction: %w", err))
+		}
+
+		err = t.changeBalance(tx, ctx, wallet, m, model.LedgerReasonTransaction, &trDao.ID)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed to update balance of user %s: %w", tr.UserID, err))
+		}
+
+		if tr == debit {
+			transfer.DebitTransactionID = trDao.ID
+		} else {
+			transfer.CreditTransactionID = trDao.ID
+		}
+	}
+
+	err = t.transferRepo.CreateTransfer(tx, ctx, transfer)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to create the transfer: %w", err))
+	}
+
+	err = t.db.Commit(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit the db tx: %w", err)
 	}
 
-	err = t.userRepo.UpdateUserBalance(tx, ctx, user)
+	return model.TransferDaoToTransferView(transfer), nil
+}
+
+// PlaceBet reserves the stake of a bet from the available real money of the user until the bet is settled,
+// voided or expires. A loss of the whole stake must fit in the loss limits of the user when the bet is placed.
+func (t *Transaction) PlaceBet(ctx context.Context, req *model.PlaceBet) (*model.Bet, error) {
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	user, err := t.userRepo.GetUserForUpdate(tx, ctx, req.UserID)
 	if err != nil {
-		errTx := t.db.Rollback(tx)
-		if errTx != nil {
-			return fmt.Errorf("failed to rollback the update user balance tx: %w %w", errTx, err)
+		return nil, t.rollback(tx, fmt.Errorf("failed to get user: %w", err))
+	}
+
+	existing, err := t.betRepo.GetBetByBetID(tx, ctx, req.BetID)
+	if err != nil && !errors.Is(err, model.ErrorBetNotFound) {
+		return nil, t.rollback(tx, fmt.Errorf("failed to check bet existance: %w", err))
+	}
+
+	if existing != nil {
+		if !existing.IsReplayOf(req) {
+			return nil, t.rollback(tx, fmt.Errorf("failed because the bet already exists: %w", model.ErrorBetAlreadyExists))
+		}
+
+		err = t.db.Rollback(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rollback the db tx of the replayed bet: %w", err)
 		}
 
-		return fmt.Errorf("failed to update user balance: %w", err)
+		return model.BetDaoToBet(existing), nil
 	}
 
-	trDao := model.TransactionToTransactionDao(tr)
+	err = user.StatusError()
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed because the user cannot place bets: %w", err))
+	}
 
-	err = t.transactionRepo.CreateTransaction(tx, ctx, trDao)
+	err = t.checkExclusion(tx, ctx, req.UserID)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed because the user cannot place bets: %w", err))
+	}
+
+	wallet, err := t.userRepo.GetWalletForUpdate(tx, ctx, req.UserID, req.Currency)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to get wallet: %w", err))
+	}
+
+	m, err := wallet.Reserve(req.Stake)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed because balance of the user is not enough: %w", err))
+	}
+
+	loss := &model.Transaction{
+		State:      model.StateLost,
+		Amount:     req.Stake,
+		Currency:   req.Currency,
+		UserID:     req.UserID,
+		SourceType: model.SourceTypeGame,
+	}
+
+	err = t.checkLimits(tx, ctx, loss, model.DirectionDebit)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed because of a responsible gambling limit: %w", err))
+	}
+
+	ttl := t.betConf.TTL
+	if ttl <= 0 {
+		ttl = defaultBetTTL
+	}
+
+	b := &model.BetDao{
+		UserID:    req.UserID,
+		Currency:  req.Currency,
+		BetID:     req.BetID,
+		Stake:     req.Stake,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	err = t.betRepo.CreateBet(tx, ctx, b)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to create the bet: %w", err))
+	}
+
+	err = t.changeBalance(tx, ctx, wallet, m, model.LedgerReasonTransaction, nil)
+	if err != nil {
+		return nil, t.rollback(tx, fmt.Errorf("failed to update user balance: %w", err))
+	}
+
+	err = t.db.Commit(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return model.BetDaoToBet(b), nil
+}
+
+// SettleBet releases the reserved stake of a placed bet and stores its outcome as a game transaction,
+// a lost bet debits its stake and a won bet credits the won amount. The bet was checked against the
+// status, exclusion and limits of the user when it was placed, so its settlement is never refused for them.
+func (t *Transaction) SettleBet(ctx context.Context, req *model.SettleBet) (*model.Bet, error) {
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	b, wallet, err := t.releaseBet(tx, ctx, req.ID)
 	if err != nil {
-		errTx := t.db.Rollback(tx)
-		if errTx != nil {
-			return fmt.Errorf("failed to rollback the create transaction tx: %w %w", errTx, err)
+		return nil, t.rollback(tx, err)
+	}
+
+	b.State = model.BetStateLost
+	if req.Outcome == model.BetOutcomeWin {
+		b.State = model.BetStateWon
+		b.WinAmount = req.Amount
+	}
+
+	tr := b.Transaction(req)
+	if tr != nil {
+		direction, err := t.direction(ctx, tr.State)
+		if err != nil {
+			return nil, t.rollback(tx, err)
+		}
+
+		m, err := wallet.Settle(direction, tr.Amount)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed because balance of the user is not enough: %w", err))
 		}
 
-		return fmt.Errorf("failed to create the transaction: %w", err)
+		trDao := model.TransactionToTransactionDao(tr)
+		trDao.BonusAmount = m.BonusPart()
+
+		err = t.transactionRepo.CreateTransaction(tx, ctx, trDao)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed to create the transaction: %w", err))
+		}
+
+		err = t.changeBalance(tx, ctx, wallet, m, model.LedgerReasonTransaction, &trDao.ID)
+		if err != nil {
+			return nil, t.rollback(tx, fmt.Errorf("failed to update user balance: %w", err))
+		}
+
+		b.TransactionID = &trDao.ID
+	}
+
+	err = t.finishBet(tx, ctx, b)
+	if err != nil {
+		return nil, t.rollback(tx, err)
 	}
 
 	err = t.db.Commit(tx)
 	if err != nil {
-		return fmt.Errorf("failed to commit the db tx: %w", err)
+		return nil, fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return model.BetDaoToBet(b), nil
+}
+
+// VoidBet releases the reserved stake of a placed bet without a transaction, e.g. when its game round is aborted.
+func (t *Transaction) VoidBet(ctx context.Context, id string) (*model.Bet, error) {
+	return t.closeBet(ctx, id, model.BetStateVoid)
+}
+
+// closeBet releases the reserved stake of a placed bet and moves it to the given state.
+func (t *Transaction) closeBet(ctx context.Context, id, state string) (*model.Bet, error) {
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a db tx: %w", err)
+	}
+
+	b, _, err := t.releaseBet(tx, ctx, id)
+	if err != nil {
+		return nil, t.rollback(tx, err)
+	}
+
+	b.State = state
+
+	err = t.finishBet(tx, ctx, b)
+	if err != nil {
+		return nil, t.rollback(tx, err)
+	}
+
+	err = t.db.Commit(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return model.BetDaoToBet(b), nil
+}
+
+// releaseBet locks a placed bet and the wallet of its user and releases the reserved stake.
+func (t *Transaction) releaseBet(tx *sql.Tx, ctx context.Context, id string) (*model.BetDao, *model.WalletDao, error) {
+	b, err := t.betRepo.GetBetForUpdate(tx, ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the bet: %w", err)
+	}
+
+	if b.State != model.BetStatePlaced {
+		return nil, nil, fmt.Errorf("failed because bet %s is %s: %w", b.ID, b.State, model.ErrorBetNotPlaced)
+	}
+
+	wallet, err := t.userRepo.GetWalletForUpdate(tx, ctx, b.UserID, b.Currency)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	err = t.changeBalance(tx, ctx, wallet, &model.WalletMovement{Reserved: -b.Stake}, model.LedgerReasonTransaction, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to release the stake: %w", err)
+	}
+
+	return b, wallet, nil
+}
+
+// finishBet stores the final state of a bet.
+func (t *Transaction) finishBet(tx *sql.Tx, ctx context.Context, b *model.BetDao) error {
+	now := time.Now()
+	b.SettledAt = &now
+
+	err := t.betRepo.UpdateBet(tx, ctx, b)
+	if err != nil {
+		return fmt.Errorf("failed to update the bet: %w", err)
 	}
 
 	return nil
 }
 
-// PostProcess cancels odd and uncancelled transactions in every interval.
+// changeBalance is the only way the usecase moves the balances of a wallet,
+// it applies the movement and writes a ledger entry for every bucket it changes in the same db tx.
+// When the movement ends the wagering of the wallet, the release is applied and recorded right after it.
+func (t *Transaction) changeBalance(
+	tx *sql.Tx,
+	ctx context.Context,
+	wallet *model.WalletDao,
+	m *model.WalletMovement,
+	reason string,
+	transactionID *string,
+) error {
+	err := t.userRepo.UpdateWalletBalance(tx, ctx, wallet, m)
+	if err != nil {
+		return err
+	}
+
+	buckets := []struct {
+		name    string
+		amount  model.Money
+		balance model.Money
+	}{
+		{name: model.LedgerBucketReal, amount: m.Real, balance: wallet.Balance},
+		{name: model.LedgerBucketBonus, amount: m.Bonus, balance: wallet.BonusBalance},
+	}
+
+	for _, b := range buckets {
+		if b.amount == 0 {
+			continue
+		}
+
+		entry := &model.LedgerEntryDao{
+			UserID:        wallet.UserID,
+			TransactionID: transactionID,
+			Reason:        reason,
+			Currency:      wallet.Currency,
+			Bucket:        b.name,
+			Amount:        b.amount,
+			BalanceBefore: b.balance - b.amount,
+			BalanceAfter:  b.balance,
+		}
+
+		err = t.ledgerRepo.CreateEntry(tx, ctx, entry)
+		if err != nil {
+			return fmt.Errorf("failed to create the ledger entry: %w", err)
+		}
+	}
+
+	release := wallet.Release()
+	if release == nil {
+		return nil
+	}
+
+	return t.changeBalance(tx, ctx, wallet, release, model.LedgerReasonConversion, transactionID)
+}
+
+// rollback aborts the given db tx and returns the error which caused it.
+func (t *Transaction) rollback(tx *sql.Tx, err error) error {
+	errTx := t.db.Rollback(tx)
+	if errTx != nil {
+		return fmt.Errorf("failed to rollback the db tx: %w %w", errTx, err)
+	}
+
+	return err
+}
+
+// PostProcess cancels odd and uncancelled transactions in every configured interval until ctx is done.
+// A batch which is already in flight is not interrupted by ctx, PostProcess returns once it has finished.
 func (t *Transaction) PostProcess(ctx context.Context) {
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(time.Second * interval):
-				transactions, err := t.transactionRepo.GetLatestOddAndUncancelledTransactions(ctx, 10)
-				if err != nil {
-					t.log.Error("failed to get latest odd and uncancelled transactions", "error", err)
-
-					continue
-				}
+	if !t.running.CompareAndSwap(false, true) {
+		t.log.Warn("post process is already running")
+
+		return
+	}
+
+	defer t.running.Store(false)
+
+	interval := t.conf.Interval
+	if interval <= 0 {
+		t.log.Warn("invalid post process interval, the default one is used", "interval", interval, "default", defaultInterval)
 
-				for _, tr := range transactions {
-					err := t.transactionRepo.CancelTransaction(ctx, tr.ID)
-					if err != nil {
-						t.log.Error("failed to cancel transaction", "error", err)
+		interval = defaultInterval
+	}
+
+	batchSize := t.conf.BatchSize
+	if batchSize <= 0 {
+		t.log.Warn("invalid post process batch size, the default one is used", "batchSize", batchSize, "default", defaultBatchSize)
+
+		batchSize = defaultBatchSize
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	defer func() {
+		err := t.elector.Resign(context.Background())
+		if err != nil {
+			t.log.Error("failed to resign the post process leadership", "error", err)
+		}
+	}()
 
-						continue
-					}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if t.conf.Jitter > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Duration(rand.Int63n(int64(t.conf.Jitter)))):
 				}
 			}
+
+			t.cancelOddTransactions(context.WithoutCancel(ctx), batchSize)
 		}
-	}()
+	}
+}
+
+// IsPostProcessRunning reports whether the post process loop is running.
+func (t *Transaction) IsPostProcessRunning() bool {
+	return t.running.Load()
+}
+
+// cancelOddTransactions runs a single post process iteration and logs its statistics.
+// Only the instance which holds the leadership cancels transactions.
+func (t *Transaction) cancelOddTransactions(ctx context.Context, batchSize int) {
+	start := time.Now()
+
+	leader, err := t.elector.IsLeader(ctx)
+	if err != nil {
+		t.log.Error("failed to check the post process leadership", "error", err)
+
+		return
+	}
+
+	if !leader {
+		t.log.Debug("skipped post process run because another instance is the leader")
+
+		return
+	}
+
+	transactions, err := t.transactionRepo.GetLatestOddAndUncancelledTransactions(ctx, batchSize)
+	if err != nil {
+		t.log.Error("failed to get latest odd and uncancelled transactions", "error", err)
+
+		return
+	}
+
+	cancelled, skipped, failed := 0, 0, 0
+
+	for _, tr := range transactions {
+		err := t.Cancel(ctx, tr.ID)
+		if err != nil {
+			if errors.Is(err, model.ErrorCancellationInsufficientBalance) {
+				t.log.Warn("skipped cancellation of transaction", "id", tr.ID, "error", err)
+
+				skipped++
+
+				continue
+			}
+
+			t.log.Error("failed to cancel transaction", "id", tr.ID, "error", err)
+
+			failed++
+
+			continue
+		}
+
+		cancelled++
+	}
+
+	t.log.Info("post process run finished",
+		"selected", len(transactions),
+		"cancelled", cancelled,
+		"skipped", skipped,
+		"failed", failed,
+		"duration", time.Since(start),
+	)
+}
+
+// ExpireBets releases the stakes of the placed bets which were not settled in time, in every configured interval
+// until ctx is done. Every bet is locked while it expires, so instances may run it side by side without a leader.
+func (t *Transaction) ExpireBets(ctx context.Context) {
+	if !t.expiryRunning.CompareAndSwap(false, true) {
+		t.log.Warn("bet expiry is already running")
+
+		return
+	}
+
+	defer t.expiryRunning.Store(false)
+
+	interval := t.betConf.Interval
+	if interval <= 0 {
+		t.log.Warn("invalid bet expiry interval, the default one is used", "interval", interval, "default", defaultInterval)
+
+		interval = defaultInterval
+	}
+
+	batchSize := t.betConf.BatchSize
+	if batchSize <= 0 {
+		t.log.Warn("invalid bet expiry batch size, the default one is used", "batchSize", batchSize, "default", defaultBatchSize)
+
+		batchSize = defaultBatchSize
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.expireBets(context.WithoutCancel(ctx), batchSize)
+		}
+	}
+}
+
+// IsBetExpiryRunning reports whether the bet expiry loop is running.
+func (t *Transaction) IsBetExpiryRunning() bool {
+	return t.expiryRunning.Load()
+}
+
+// expireBets runs a single bet expiry iteration and logs its statistics.
+func (t *Transaction) expireBets(ctx context.Context, batchSize int) {
+	start := time.Now()
+
+	bets, err := t.betRepo.GetExpiredBets(ctx, batchSize)
+	if err != nil {
+		t.log.Error("failed to get expired bets", "error", err)
+
+		return
+	}
+
+	expired, skipped, failed := 0, 0, 0
+
+	for _, b := range bets {
+		_, err := t.closeBet(ctx, b.ID, model.BetStateExpired)
+		if err != nil {
+			// The bet was settled or voided since it was selected.
+			if errors.Is(err, model.ErrorBetNotPlaced) {
+				skipped++
+
+				continue
+			}
+
+			t.log.Error("failed to expire bet", "id", b.ID, "error", err)
+
+			failed++
+
+			continue
+		}
+
+		expired++
+	}
+
+	t.log.Info("bet expiry run finished",
+		"selected", len(bets),
+		"expired", expired,
+		"skipped", skipped,
+		"failed", failed,
+		"duration", time.Since(start),
+	)
 }
@@ -5,22 +5,55 @@ import (
 	"database/sql"
 	"errors"
 	"log/slog"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	betMocks "github.com/ttagiyeva/entain/internal/bet/mocks"
+	"github.com/ttagiyeva/entain/internal/config"
+	exclusionMocks "github.com/ttagiyeva/entain/internal/exclusion/mocks"
+	ledgerMocks "github.com/ttagiyeva/entain/internal/ledger/mocks"
+	limitMocks "github.com/ttagiyeva/entain/internal/limit/mocks"
 	"github.com/ttagiyeva/entain/internal/model"
+	registryMocks "github.com/ttagiyeva/entain/internal/registry/mocks"
 	"github.com/ttagiyeva/entain/internal/transaction/mocks"
+	transferMocks "github.com/ttagiyeva/entain/internal/transfer/mocks"
 	userMocks "github.com/ttagiyeva/entain/internal/user/mocks"
 )
 
+// newTestRegistry returns a registry usecase which knows the initial states and a "pending" state
+// which leaves the wallet as is.
+func newTestRegistry(ctrl *gomock.Controller) *registryMocks.MockRegistryUsecase {
+	reg := model.NewRegistry(
+		[]*model.SourceTypeDao{{Name: "game"}, {Name: "payment"}, {Name: "server"}},
+		[]*model.StateDao{
+			{Name: "lost", Direction: model.DirectionDebit},
+			{Name: "pending", Direction: model.DirectionNone},
+			{Name: "win", Direction: model.DirectionCredit},
+		},
+	)
+
+	r := registryMocks.NewMockRegistryUsecase(ctrl)
+	r.EXPECT().Get(gomock.Any()).Return(reg, nil).AnyTimes()
+
+	return r
+}
+
 func TestProcess(t *testing.T) {
 	user := &model.UserDao{
-		ID:      gofakeit.UUID(),
-		Balance: 10,
+		ID:     gofakeit.UUID(),
+		Status: model.UserStatusActive,
+	}
+
+	wallet := &model.WalletDao{
+		UserID:   user.ID,
+		Currency: "EUR",
+		Balance:  1000,
 	}
 
 	tx := &sql.Tx{}
@@ -30,35 +63,79 @@ func TestProcess(t *testing.T) {
 		UserID:        user.ID,
 		TransactionID: gofakeit.UUID(),
 		State:         "lost",
-		Amount:        1,
+		Amount:        100,
+		Currency:      wallet.Currency,
 	}
 
 	testCases := []struct {
 		name          string
 		body          *model.Transaction
-		buildStubs    func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase)
+		buildStubs    func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase)
 		checkResponse func(err error)
 	}{
 		{
 			name: "OK",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(false, nil)
-				userRepo.EXPECT().GetUser(gomock.Any(), tr.UserID).Return(user, nil)
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
 				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
-				userRepo.EXPECT().UpdateUserBalance(tx, gomock.Any(), user).Return(nil).Times(1)
-				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return(nil, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, trDao *model.TransactionDao) error {
+					trDao.ID = "1"
+
+					return nil
+				})
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -tr.Amount}).Return(nil).Times(1)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, entry *model.LedgerEntryDao) error {
+					require.Equal(t, user.ID, entry.UserID)
+					require.Equal(t, "1", *entry.TransactionID)
+					require.Equal(t, model.LedgerReasonTransaction, entry.Reason)
+					require.Equal(t, tr.Currency, entry.Currency)
+					require.Equal(t, model.LedgerBucketReal, entry.Bucket)
+					require.Equal(t, -tr.Amount, entry.Amount)
+					require.Equal(t, entry.BalanceBefore+entry.Amount, entry.BalanceAfter)
+
+					return nil
+				})
 				db.EXPECT().Commit(tx).Return(nil).Times(1)
 			},
 			checkResponse: func(err error) {
 				require.NoError(t, err)
 			},
 		},
+		{
+			name: "BeginTx error",
+			body: tr,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(nil, dummyErr)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+		{
+			name: "User not found",
+			body: tr,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorUserNotFound)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserNotFound))
+			},
+		},
 		{
 			name: "CheckExistance error",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(false, dummyErr)
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, dummyErr)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
 			},
 			checkResponse: func(err error) {
 				require.Equal(t, true, errors.Is(err, dummyErr))
@@ -67,76 +144,175 @@ func TestProcess(t *testing.T) {
 		{
 			name: "Existed transaction",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(true, nil)
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(&model.TransactionDao{
+					UserID:        tr.UserID,
+					TransactionID: tr.TransactionID,
+					State:         "win",
+					Amount:        tr.Amount,
+				}, nil)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
 			},
 			checkResponse: func(err error) {
 				require.Equal(t, true, errors.Is(err, model.ErrorTransactionAlreadyExists))
 			},
 		},
 		{
-			name: "User not found",
+			name: "Replayed transaction",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(false, nil).Times(1)
-				userRepo.EXPECT().GetUser(gomock.Any(), tr.UserID).Return(nil, model.ErrorUserNotFound)
-
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(&model.TransactionDao{
+					UserID:        tr.UserID,
+					TransactionID: tr.TransactionID,
+					SourceType:    tr.SourceType,
+					State:         tr.State,
+					Amount:        tr.Amount,
+					Currency:      tr.Currency,
+				}, nil)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
 			},
 			checkResponse: func(err error) {
-				require.Equal(t, true, errors.Is(err, model.ErrorUserNotFound))
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Suspended user",
+			body: tr,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(&model.UserDao{
+					ID:     user.ID,
+					Status: model.UserStatusSuspended,
+				}, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserSuspended))
+			},
+		},
+		{
+			name: "Closed user",
+			body: tr,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(&model.UserDao{
+					ID:     user.ID,
+					Status: model.UserStatusClosed,
+				}, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserClosed))
+			},
+		},
+		{
+			name: "Self-excluded user",
+			body: tr,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(&model.ExclusionDao{
+					UserID:    tr.UserID,
+					Period:    model.ExclusionPeriodPermanent,
+					StartedAt: time.Now(),
+				}, nil)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserSelfExcluded))
+			},
+		},
+		{
+			name: "Wallet not found",
+			body: tr,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(nil, model.ErrorWalletNotFound)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorWalletNotFound))
 			},
 		},
 		{
 			name: "Insufficient balance",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(false, nil).Times(1)
-				userRepo.EXPECT().GetUser(gomock.Any(), tr.UserID).Return(&model.UserDao{
-					ID:      user.ID,
-					Balance: 0,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(&model.WalletDao{
+					UserID:   user.ID,
+					Currency: tr.Currency,
+					Balance:  0,
 				}, nil)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
 			},
 			checkResponse: func(err error) {
 				require.Equal(t, true, errors.Is(err, model.ErrorInsufficientBalance))
 			},
 		},
 		{
-			name: "BeginTx error",
+			name: "UpdateWalletBalance error",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(false, nil)
-				userRepo.EXPECT().GetUser(gomock.Any(), tr.UserID).Return(user, nil)
-				db.EXPECT().BeginTx(gomock.Any()).Return(nil, dummyErr)
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return(nil, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -tr.Amount}).Return(dummyErr)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
 			},
 			checkResponse: func(err error) {
 				require.Equal(t, true, errors.Is(err, dummyErr))
 			},
 		},
 		{
-			name: "UpdateUserBalance error",
+			name: "Rollback of UpdateWalletBalance error",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(false, nil)
-				userRepo.EXPECT().GetUser(gomock.Any(), tr.UserID).Return(user, nil)
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
 				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
-				userRepo.EXPECT().UpdateUserBalance(tx, gomock.Any(), user).Return(dummyErr)
-				db.EXPECT().Rollback(tx).Return(nil).Times(1)
-
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return(nil, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -tr.Amount}).Return(dummyErr)
+				db.EXPECT().Rollback(tx).Return(errors.New("rollback error")).Times(1)
 			},
 			checkResponse: func(err error) {
 				require.Equal(t, true, errors.Is(err, dummyErr))
 			},
 		},
 		{
-			name: "Rollback of UpdateUserBalance error",
+			name: "CreateEntry error",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(false, nil)
-				userRepo.EXPECT().GetUser(gomock.Any(), tr.UserID).Return(user, nil)
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
 				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
-				userRepo.EXPECT().UpdateUserBalance(tx, gomock.Any(), user).Return(dummyErr)
-				db.EXPECT().Rollback(tx).Return(errors.New("rollback error")).Times(1)
-
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return(nil, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -tr.Amount}).Return(nil).Times(1)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(dummyErr)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
 			},
 			checkResponse: func(err error) {
 				require.Equal(t, true, errors.Is(err, dummyErr))
@@ -145,11 +321,13 @@ func TestProcess(t *testing.T) {
 		{
 			name: "CreateTransaction error",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(false, nil)
-				userRepo.EXPECT().GetUser(gomock.Any(), tr.UserID).Return(user, nil)
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
 				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
-				userRepo.EXPECT().UpdateUserBalance(tx, gomock.Any(), user).Return(nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return(nil, nil)
 				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(dummyErr)
 				db.EXPECT().Rollback(tx).Return(nil).Times(1)
 			},
@@ -160,11 +338,13 @@ func TestProcess(t *testing.T) {
 		{
 			name: "Rollback of CreateTransaction error",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(false, nil)
-				userRepo.EXPECT().GetUser(gomock.Any(), tr.UserID).Return(user, nil)
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
 				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
-				userRepo.EXPECT().UpdateUserBalance(tx, gomock.Any(), user).Return(nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return(nil, nil)
 				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(dummyErr)
 				db.EXPECT().Rollback(tx).Return(errors.New("rollback error"))
 			},
@@ -172,15 +352,193 @@ func TestProcess(t *testing.T) {
 				require.Equal(t, true, errors.Is(err, dummyErr))
 			},
 		},
+		{
+			name: "Payment state",
+			body: &model.Transaction{
+				UserID:        user.ID,
+				TransactionID: tr.TransactionID,
+				State:         model.StateDepositConfirmed,
+				Amount:        100,
+				Currency:      wallet.Currency,
+			},
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorPaymentState))
+			},
+		},
+		{
+			name: "Adjustment",
+			body: &model.Transaction{
+				UserID:        user.ID,
+				TransactionID: tr.TransactionID,
+				SourceType:    model.SourceTypeAdjustment,
+				State:         "win",
+				Amount:        100,
+				Currency:      wallet.Currency,
+			},
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorAdjustmentState))
+			},
+		},
+		{
+			name: "Transfer",
+			body: &model.Transaction{
+				UserID:        user.ID,
+				TransactionID: tr.TransactionID,
+				SourceType:    "server",
+				State:         model.StateTransferIn,
+				Amount:        100,
+				Currency:      wallet.Currency,
+			},
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorTransferState))
+			},
+		},
+		{
+			name: "Unregistered state",
+			body: &model.Transaction{
+				UserID:        user.ID,
+				TransactionID: tr.TransactionID,
+				State:         "refund",
+				Amount:        100,
+				Currency:      wallet.Currency,
+			},
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUnknownState))
+			},
+		},
+		{
+			name: "State without balance change",
+			body: &model.Transaction{
+				UserID:        user.ID,
+				TransactionID: tr.TransactionID,
+				State:         "pending",
+				Amount:        100000,
+				Currency:      wallet.Currency,
+			},
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{}).Return(nil).Times(1)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Loss meeting the wagering",
+			body: tr,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				bonusWallet := &model.WalletDao{
+					UserID:           user.ID,
+					Currency:         tr.Currency,
+					Balance:          50,
+					BonusBalance:     200,
+					WageringRequired: 100,
+				}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(bonusWallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return(nil, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, trDao *model.TransactionDao) error {
+					require.Equal(t, model.Money(50), trDao.BonusAmount)
+
+					return nil
+				})
+				gomock.InOrder(
+					userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), bonusWallet, &model.WalletMovement{Real: -50, Bonus: -50, Wagered: 100}).
+						DoAndReturn(applyMovement),
+					userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), bonusWallet, &model.WalletMovement{Real: 150, Bonus: -150, WageringRequired: -100, Wagered: -100}).
+						DoAndReturn(applyMovement),
+				)
+
+				var reasons []string
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, entry *model.LedgerEntryDao) error {
+					reasons = append(reasons, entry.Reason+"/"+entry.Bucket)
+					require.Equal(t, entry.BalanceBefore+entry.Amount, entry.BalanceAfter)
+
+					return nil
+				}).Times(4)
+				db.EXPECT().Commit(tx).DoAndReturn(func(_ *sql.Tx) error {
+					require.Equal(t, []string{"transaction/real", "transaction/bonus", "conversion/real", "conversion/bonus"}, reasons)
+					require.Equal(t, model.Money(150), bonusWallet.Balance)
+					require.Equal(t, model.Money(0), bonusWallet.BonusBalance)
+
+					return nil
+				})
+			},
+			checkResponse: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Loss limit exceeded",
+			body: tr,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return([]*model.LimitDao{
+					{UserID: user.ID, Currency: tr.Currency, Kind: model.LimitKindLoss, Period: model.LimitPeriodDay, Amount: 1000},
+					{UserID: user.ID, Currency: tr.Currency, Kind: model.LimitKindLoss, Period: model.LimitPeriodWeek, Amount: 2000},
+				}, nil)
+				limitRepo.EXPECT().GetUsage(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss, gomock.Any()).Return(model.Money(900), nil)
+				limitRepo.EXPECT().GetUsage(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss, gomock.Any()).Return(model.Money(1950), nil)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorLossLimitExceeded))
+			},
+		},
+		{
+			name: "GetUsage error",
+			body: tr,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return([]*model.LimitDao{
+					{UserID: user.ID, Currency: tr.Currency, Kind: model.LimitKindLoss, Period: model.LimitPeriodDay, Amount: 1000},
+				}, nil)
+				limitRepo.EXPECT().GetUsage(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss, gomock.Any()).Return(model.Money(0), dummyErr)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
 		{
 			name: "Commit error",
 			body: tr,
-			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, db *mocks.MockDatabase) {
-				trRepo.EXPECT().CheckExistance(gomock.Any(), tr.TransactionID).Return(false, nil)
-				userRepo.EXPECT().GetUser(gomock.Any(), tr.UserID).Return(user, nil)
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
 				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
-				userRepo.EXPECT().UpdateUserBalance(tx, gomock.Any(), user).Return(nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), tr.UserID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return(nil, nil)
 				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -tr.Amount}).Return(nil).Times(1)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
 				db.EXPECT().Commit(tx).Return(dummyErr)
 			},
 			checkResponse: func(err error) {
@@ -198,12 +556,15 @@ func TestProcess(t *testing.T) {
 
 			trRepo := mocks.NewMockRepository(ctrl)
 			userRepo := userMocks.NewMockUserRepository(ctrl)
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository(ctrl)
+			limitRepo := limitMocks.NewMockLimitRepository(ctrl)
+			exclusionRepo := exclusionMocks.NewMockExclusionRepository(ctrl)
 			db := mocks.NewMockDatabase(ctrl)
 
-			tc.buildStubs(trRepo, userRepo, db)
+			tc.buildStubs(trRepo, userRepo, ledgerRepo, limitRepo, exclusionRepo, db)
 
-			usecase := New(nil, trRepo, userRepo, db)
-			err := usecase.Process(context.Background(), tr)
+			usecase := New(nil, &config.Config{}, trRepo, userRepo, ledgerRepo, limitRepo, exclusionRepo, newTestRegistry(ctrl), nil, nil, db, nil)
+			err := usecase.Process(context.Background(), tc.body)
 
 			tc.checkResponse(err)
 
@@ -212,30 +573,131 @@ func TestProcess(t *testing.T) {
 
 }
 
-func TestPostProcess(t *testing.T) {
-	transactions := []*model.TransactionDao{
-		{
-			ID:            gofakeit.UUID(),
-			UserID:        gofakeit.UUID(),
-			TransactionID: gofakeit.UUID(),
-			State:         "win",
-			Amount:        gofakeit.Float32(),
-		},
+func TestProcessBatch(t *testing.T) {
+	tx := &sql.Tx{}
+
+	// The second user sorts before the first one, so it is locked first in an atomic batch.
+	first := &model.UserDao{ID: "b", Status: model.UserStatusActive}
+	second := &model.UserDao{ID: "a", Status: model.UserStatusActive}
+
+	win := &model.Transaction{UserID: first.ID, TransactionID: "1", SourceType: "game", State: "win", Amount: 100, Currency: "EUR"}
+	lost := &model.Transaction{UserID: second.ID, TransactionID: "2", SourceType: "game", State: "lost", Amount: 100, Currency: "EUR"}
+
+	// processed stubs the processing of a win or of an affordable loss after the user row is locked.
+	processed := func(tr *model.Transaction, trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository) {
+		trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), tr.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+		exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), tr.UserID).Return(nil, model.ErrorExclusionNotFound)
+		userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(&model.WalletDao{UserID: tr.UserID, Currency: tr.Currency, Balance: 1000}, nil)
+		if tr.State == "lost" {
+			limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), tr.UserID, tr.Currency, model.LimitKindLoss).Return(nil, nil)
+		}
+		trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(nil)
+		userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil)
 	}
 
 	testCases := []struct {
-		name       string
-		buildStubs func(trRepo *mocks.MockRepository, wg *sync.WaitGroup)
+		name          string
+		trs           []*model.Transaction
+		atomic        bool
+		buildStubs    func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase)
+		checkResponse func(results []*model.BatchResult, err error)
 	}{
 		{
-			name: "OK",
-			buildStubs: func(trRepo *mocks.MockRepository, wg *sync.WaitGroup) {
-				trRepo.EXPECT().GetLatestOddAndUncancelledTransactions(gomock.Any(), gomock.Any()).Return(transactions, nil).Do(func(arg0, ar1 interface{}) {
-					defer wg.Done()
-				})
-				trRepo.EXPECT().CancelTransaction(gomock.Any(), gomock.Any()).Return(nil).Do(func(arg0, ar1 interface{}) {
-					defer wg.Done()
-				})
+			name: "Each transaction on its own",
+			trs:  []*model.Transaction{win, lost, {UserID: "c", TransactionID: "3", SourceType: "game", State: "win", Amount: 100, Currency: "EUR"}},
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(3)
+
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), first.ID).Return(first, nil)
+				processed(win, trRepo, userRepo, ledgerRepo, limitRepo, exclusionRepo)
+				db.EXPECT().Commit(tx).Return(nil)
+
+				// The loss has been processed before.
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), second.ID).Return(second, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), lost.TransactionID).Return(model.TransactionToTransactionDao(lost), nil)
+
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), "c").Return(nil, model.ErrorUserNotFound)
+				db.EXPECT().Rollback(tx).Return(nil).Times(2)
+			},
+			checkResponse: func(results []*model.BatchResult, err error) {
+				require.NoError(t, err)
+				require.Len(t, results, 3)
+				require.Equal(t, model.BatchStatusProcessed, results[0].Status)
+				require.Equal(t, model.BatchStatusDuplicate, results[1].Status)
+				require.Equal(t, model.BatchStatusFailed, results[2].Status)
+				require.Equal(t, true, errors.Is(results[2].Err, model.ErrorUserNotFound))
+			},
+		},
+		{
+			name:   "Atomic",
+			trs:    []*model.Transaction{win, lost},
+			atomic: true,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				gomock.InOrder(
+					userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), second.ID).Return(second, nil),
+					userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), first.ID).Return(first, nil),
+					userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), first.ID).Return(first, nil),
+					userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), second.ID).Return(second, nil),
+				)
+				processed(win, trRepo, userRepo, ledgerRepo, limitRepo, exclusionRepo)
+				processed(lost, trRepo, userRepo, ledgerRepo, limitRepo, exclusionRepo)
+				db.EXPECT().Commit(tx).Return(nil).Times(1)
+			},
+			checkResponse: func(results []*model.BatchResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.BatchStatusProcessed, results[0].Status)
+				require.Equal(t, model.BatchStatusProcessed, results[1].Status)
+			},
+		},
+		{
+			name:   "Atomic with a failing transaction",
+			trs:    []*model.Transaction{win, lost},
+			atomic: true,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), first.ID).Return(first, nil).Times(2)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), second.ID).Return(second, nil).Times(2)
+				processed(win, trRepo, userRepo, ledgerRepo, limitRepo, exclusionRepo)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), lost.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), second.ID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), second.ID, "EUR").Return(&model.WalletDao{Balance: 50}, nil)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
+			},
+			checkResponse: func(results []*model.BatchResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.BatchStatusSkipped, results[0].Status)
+				require.Equal(t, model.BatchStatusFailed, results[1].Status)
+				require.Equal(t, true, errors.Is(results[1].Err, model.ErrorInsufficientBalance))
+			},
+		},
+		{
+			name:   "Atomic with a payment state",
+			trs:    []*model.Transaction{win, {UserID: second.ID, TransactionID: "3", SourceType: "game", State: model.StateDepositConfirmed, Amount: 100, Currency: "EUR"}},
+			atomic: true,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+			},
+			checkResponse: func(results []*model.BatchResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.BatchStatusSkipped, results[0].Status)
+				require.Equal(t, true, errors.Is(results[1].Err, model.ErrorPaymentState))
+			},
+		},
+		{
+			name:   "Atomic with an unknown user",
+			trs:    []*model.Transaction{win, lost},
+			atomic: true,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(1)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), second.ID).Return(second, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), first.ID).Return(nil, model.ErrorUserNotFound)
+				db.EXPECT().Rollback(tx).Return(nil).Times(1)
+			},
+			checkResponse: func(results []*model.BatchResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, true, errors.Is(results[0].Err, model.ErrorUserNotFound))
+				require.Equal(t, model.BatchStatusSkipped, results[1].Status)
 			},
 		},
 	}
@@ -243,26 +705,1568 @@ func TestPostProcess(t *testing.T) {
 	for i := range testCases {
 		tc := testCases[i]
 		t.Run(tc.name, func(t *testing.T) {
-			ctx, cancel := context.WithCancel(context.Background())
-
-			defer cancel()
-
 			ctrl := gomock.NewController(t)
 
-			wg := sync.WaitGroup{}
-			wg.Add(2)
-
 			defer ctrl.Finish()
 
 			trRepo := mocks.NewMockRepository(ctrl)
 			userRepo := userMocks.NewMockUserRepository(ctrl)
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository(ctrl)
+			limitRepo := limitMocks.NewMockLimitRepository(ctrl)
+			exclusionRepo := exclusionMocks.NewMockExclusionRepository(ctrl)
 			db := mocks.NewMockDatabase(ctrl)
 
-			tc.buildStubs(trRepo, &wg)
+			tc.buildStubs(trRepo, userRepo, ledgerRepo, limitRepo, exclusionRepo, db)
 
-			usecase := New(slog.Default(), trRepo, userRepo, db)
-			usecase.PostProcess(ctx)
-			wg.Wait()
+			usecase := New(nil, &config.Config{}, trRepo, userRepo, ledgerRepo, limitRepo, exclusionRepo, newTestRegistry(ctrl), nil, nil, db, nil)
+			tc.checkResponse(usecase.ProcessBatch(context.Background(), tc.trs, tc.atomic))
+		})
+	}
+}
+
+// importRecorder records what an import reports.
+type importRecorder struct {
+	progress []model.ImportProgress
+	failures []*model.ImportFailure
+}
+
+func (r *importRecorder) Progress(p model.ImportProgress) {
+	r.progress = append(r.progress, p)
+}
+
+func (r *importRecorder) Failure(f *model.ImportFailure) {
+	r.failures = append(r.failures, f)
+}
+
+func TestImport(t *testing.T) {
+	tx := &sql.Tx{}
+	user := &model.UserDao{ID: "00000000-0000-0000-0000-000000000001", Status: model.UserStatusActive}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	trRepo := mocks.NewMockRepository(ctrl)
+	userRepo := userMocks.NewMockUserRepository(ctrl)
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository(ctrl)
+	exclusionRepo := exclusionMocks.NewMockExclusionRepository(ctrl)
+	db := mocks.NewMockDatabase(ctrl)
+
+	// The first line is processed and the last one has been processed before.
+	db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(2)
+	userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil).Times(2)
+	trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), "1").Return(nil, model.ErrorTransactionNotFound)
+	exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), user.ID).Return(nil, model.ErrorExclusionNotFound)
+	userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(&model.WalletDao{UserID: user.ID, Currency: "EUR"}, nil)
+	trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, trDao *model.TransactionDao) error {
+		require.Equal(t, "server", trDao.SourceType)
+
+		return nil
+	})
+	userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), gomock.Any(), &model.WalletMovement{Real: 1000}).Return(nil)
+	ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil)
+	db.EXPECT().Commit(tx).Return(nil)
+	trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), "3").Return(&model.TransactionDao{
+		UserID:     user.ID,
+		SourceType: "server",
+		State:      "win",
+		Amount:     500,
+		Currency:   "EUR",
+	}, nil)
+	db.EXPECT().Rollback(tx).Return(nil)
+
+	input := strings.Join([]string{
+		`{"transactionId":"1","userId":"00000000-0000-0000-0000-000000000001","sourceType":"game","state":"win","amount":10,"currency":"EUR"}`,
+		`{"transactionId":"2",`,
+		``,
+		`{"transactionId":"2","userId":"00000000-0000-0000-0000-000000000001","state":"won","amount":10,"currency":"EUR"}`,
+		`{"transactionId":"3","userId":"00000000-0000-0000-0000-000000000001","state":"win","amount":5,"currency":"EUR"}`,
+	}, "\n")
+
+	usecase := New(nil, &config.Config{Import: config.Import{ProgressEvery: 2}}, trRepo, userRepo, ledgerRepo, nil, exclusionRepo, newTestRegistry(ctrl), nil, nil, db, nil)
+
+	reporter := &importRecorder{}
+	progress, err := usecase.Import(context.Background(), strings.NewReader(input), "server", reporter)
+	require.NoError(t, err)
+	require.Equal(t, &model.ImportProgress{Lines: 5, Processed: 1, Duplicates: 1, Failed: 2, Done: true}, progress)
+
+	require.Len(t, reporter.failures, 2)
+	require.Equal(t, 2, reporter.failures[0].Line)
+	require.Equal(t, true, errors.Is(reporter.failures[0].Err, model.ErrorInvalidLine))
+	require.Equal(t, 4, reporter.failures[1].Line)
+	require.Equal(t, "2", reporter.failures[1].TransactionID)
+
+	require.Equal(t, []model.ImportProgress{
+		{Lines: 2, Processed: 1, Failed: 1},
+		{Lines: 4, Processed: 1, Failed: 2},
+		*progress,
+	}, reporter.progress)
+
+	// A line longer than the limit stops the import.
+	_, err = usecase.Import(context.Background(), strings.NewReader(strings.Repeat("x", maxImportLineSize+1)), "", reporter)
+	require.Equal(t, true, errors.Is(err, model.ErrorInvalidLine))
+}
+
+func TestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filter := &model.ExportFilter{Format: model.ExportFormatCSV}
+
+	trRepo := mocks.NewMockRepository(ctrl)
+	trRepo.EXPECT().ExportTransactions(gomock.Any(), filter, gomock.Any()).DoAndReturn(func(_ context.Context, _ *model.ExportFilter, fn func(*model.TransactionDao) error) error {
+		for _, id := range []string{"1", "2", "3"} {
+			err := fn(&model.TransactionDao{ID: id})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	usecase := New(nil, &config.Config{}, trRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// The error of the consumer stops the export.
+	dummyErr := errors.New("dummy error")
+	ids := []string{}
+
+	err := usecase.Export(context.Background(), filter, func(tr *model.TransactionView) error {
+		if tr.ID == "3" {
+			return dummyErr
+		}
+
+		ids = append(ids, tr.ID)
+
+		return nil
+	})
+	require.Equal(t, true, errors.Is(err, dummyErr))
+	require.Equal(t, []string{"1", "2"}, ids)
+}
+
+// applyMovement stands in for the database when a test needs the wallet to change.
+func applyMovement(_ *sql.Tx, _ context.Context, wallet *model.WalletDao, m *model.WalletMovement) error {
+	wallet.Balance += m.Real
+	wallet.Reserved += m.Reserved
+	wallet.BonusBalance += m.Bonus
+	wallet.WageringRequired += m.WageringRequired
+	wallet.Wagered += m.Wagered
+
+	return nil
+}
+
+func TestGrantBonus(t *testing.T) {
+	tx := &sql.Tx{}
+	dummyErr := errors.New("dummy error")
+
+	bonus := &model.Bonus{
+		UserID:   gofakeit.UUID(),
+		Currency: "EUR",
+		Amount:   500,
+		Wagering: 2000,
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase)
+		checkResponse func(err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				wallet := &model.WalletDao{UserID: bonus.UserID, Currency: bonus.Currency}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), bonus.UserID).Return(&model.UserDao{ID: bonus.UserID, Status: model.UserStatusActive}, nil)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), bonus.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), bonus.UserID, bonus.Currency).Return(wallet, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Bonus: 500, WageringRequired: 2000}).DoAndReturn(applyMovement)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, entry *model.LedgerEntryDao) error {
+					require.Nil(t, entry.TransactionID)
+					require.Equal(t, model.LedgerReasonBonus, entry.Reason)
+					require.Equal(t, model.LedgerBucketBonus, entry.Bucket)
+					require.Equal(t, bonus.Amount, entry.Amount)
+					require.Equal(t, bonus.Amount, entry.BalanceAfter)
+
+					return nil
+				})
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Suspended user",
+			buildStubs: func(userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), bonus.UserID).Return(&model.UserDao{ID: bonus.UserID, Status: model.UserStatusSuspended}, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserSuspended))
+			},
+		},
+		{
+			name: "Self-excluded user",
+			buildStubs: func(userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), bonus.UserID).Return(&model.UserDao{ID: bonus.UserID, Status: model.UserStatusActive}, nil)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), bonus.UserID).Return(&model.ExclusionDao{UserID: bonus.UserID, Period: model.ExclusionPeriodDay}, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserSelfExcluded))
+			},
+		},
+		{
+			name: "Wallet not found",
+			buildStubs: func(userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), bonus.UserID).Return(&model.UserDao{ID: bonus.UserID, Status: model.UserStatusActive}, nil)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), bonus.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), bonus.UserID, bonus.Currency).Return(nil, model.ErrorWalletNotFound)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorWalletNotFound))
+			},
+		},
+		{
+			name: "Commit error",
+			buildStubs: func(userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				wallet := &model.WalletDao{UserID: bonus.UserID, Currency: bonus.Currency}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), bonus.UserID).Return(&model.UserDao{ID: bonus.UserID, Status: model.UserStatusActive}, nil)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), bonus.UserID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), bonus.UserID, bonus.Currency).Return(wallet, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, gomock.Any()).DoAndReturn(applyMovement)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil)
+				db.EXPECT().Commit(tx).Return(dummyErr)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository(ctrl)
+			exclusionRepo := exclusionMocks.NewMockExclusionRepository(ctrl)
+			db := mocks.NewMockDatabase(ctrl)
+
+			tc.buildStubs(userRepo, ledgerRepo, exclusionRepo, db)
+
+			usecase := New(nil, &config.Config{}, nil, userRepo, ledgerRepo, nil, exclusionRepo, nil, nil, nil, db, nil)
+			err := usecase.GrantBonus(context.Background(), bonus)
+
+			tc.checkResponse(err)
+		})
+	}
+}
+
+func TestStartPayment(t *testing.T) {
+	tx := &sql.Tx{}
+	user := &model.UserDao{ID: gofakeit.UUID(), Status: model.UserStatusActive}
+
+	deposit := &model.Payment{TransactionID: gofakeit.UUID(), Amount: 500, Currency: "EUR", UserID: user.ID, Kind: model.PaymentKindDeposit}
+	withdrawal := &model.Payment{TransactionID: gofakeit.UUID(), Amount: 500, Currency: "EUR", UserID: user.ID, Kind: model.PaymentKindWithdrawal}
+
+	testCases := []struct {
+		name          string
+		payment       *model.Payment
+		buildStubs    func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase)
+		checkResponse func(tr *model.TransactionView, err error)
+	}{
+		{
+			name:    "Deposit",
+			payment: deposit,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				wallet := &model.WalletDao{UserID: user.ID, Currency: "EUR"}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), deposit.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(wallet, nil)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), user.ID).Return(nil, model.ErrorExclusionNotFound)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), user.ID, "EUR", model.LimitKindDeposit).Return(nil, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, trDao *model.TransactionDao) error {
+					trDao.ID = "1"
+
+					return nil
+				})
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{}).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1", tr.ID)
+				require.Equal(t, model.StateDepositPending, tr.State)
+				require.Equal(t, model.SourceTypePayment, tr.SourceType)
+			},
+		},
+		{
+			name:    "Deposit of a self-excluded user",
+			payment: deposit,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), deposit.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(&model.WalletDao{}, nil)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), user.ID).Return(&model.ExclusionDao{UserID: user.ID, Period: model.ExclusionPeriodDay}, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserSelfExcluded))
+			},
+		},
+		{
+			name:    "Withdrawal",
+			payment: withdrawal,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				wallet := &model.WalletDao{UserID: user.ID, Currency: "EUR", Balance: 800, Reserved: 300}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), withdrawal.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(wallet, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Reserved: 500}).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.StateWithdrawalPending, tr.State)
+			},
+		},
+		{
+			name:    "Withdrawal over the available balance",
+			payment: withdrawal,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), withdrawal.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(&model.WalletDao{Balance: 800, Reserved: 301, BonusBalance: 1000}, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorInsufficientBalance))
+			},
+		},
+		{
+			name:    "Replayed payment",
+			payment: withdrawal,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				existing := model.TransactionToTransactionDao(withdrawal.Transaction())
+				existing.ID = "1"
+				existing.State = model.StateWithdrawalConfirmed
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), withdrawal.TransactionID).Return(existing, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorTransactionAlreadyExists))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			trRepo := mocks.NewMockRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			limitRepo := limitMocks.NewMockLimitRepository(ctrl)
+			exclusionRepo := exclusionMocks.NewMockExclusionRepository(ctrl)
+			db := mocks.NewMockDatabase(ctrl)
+
+			tc.buildStubs(trRepo, userRepo, limitRepo, exclusionRepo, db)
+
+			usecase := New(nil, &config.Config{}, trRepo, userRepo, nil, limitRepo, exclusionRepo, nil, nil, nil, db, nil)
+			tc.checkResponse(usecase.StartPayment(context.Background(), tc.payment))
+		})
+	}
+}
+
+func TestCompletePayment(t *testing.T) {
+	tx := &sql.Tx{}
+	dummyErr := errors.New("dummy error")
+
+	pending := &model.TransactionDao{
+		ID:         "1",
+		UserID:     gofakeit.UUID(),
+		SourceType: model.SourceTypePayment,
+		State:      model.StateWithdrawalPending,
+		Amount:     500,
+		Currency:   "EUR",
+	}
+
+	testCases := []struct {
+		name          string
+		confirmed     bool
+		buildStubs    func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase)
+		checkResponse func(tr *model.TransactionView, err error)
+	}{
+		{
+			name:      "Confirmed withdrawal",
+			confirmed: true,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				tr := *pending
+				wallet := &model.WalletDao{UserID: tr.UserID, Currency: "EUR", Balance: 800, Reserved: 500}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().GetTransactionForUpdate(tx, gomock.Any(), tr.ID).Return(&tr, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, "EUR").Return(wallet, nil)
+				trRepo.EXPECT().UpdateTransactionState(tx, gomock.Any(), tr.ID, model.StateWithdrawalConfirmed).Return(nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -500, Reserved: -500}).DoAndReturn(applyMovement)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, entry *model.LedgerEntryDao) error {
+					require.Equal(t, tr.ID, *entry.TransactionID)
+					require.Equal(t, model.LedgerBucketReal, entry.Bucket)
+					require.Equal(t, model.Money(-500), entry.Amount)
+					require.Equal(t, model.Money(300), entry.BalanceAfter)
+
+					return nil
+				})
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.StateWithdrawalConfirmed, tr.State)
+			},
+		},
+		{
+			name: "Failed withdrawal",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				tr := *pending
+				wallet := &model.WalletDao{UserID: tr.UserID, Currency: "EUR", Balance: 800, Reserved: 500}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().GetTransactionForUpdate(tx, gomock.Any(), tr.ID).Return(&tr, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, "EUR").Return(wallet, nil)
+				trRepo.EXPECT().UpdateTransactionState(tx, gomock.Any(), tr.ID, model.StateWithdrawalFailed).Return(nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Reserved: -500}).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.StateWithdrawalFailed, tr.State)
+			},
+		},
+		{
+			name:      "Not pending",
+			confirmed: true,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				tr := *pending
+				tr.State = model.StateWithdrawalFailed
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().GetTransactionForUpdate(tx, gomock.Any(), tr.ID).Return(&tr, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorPaymentNotPending))
+			},
+		},
+		{
+			name:      "Transaction not found",
+			confirmed: true,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().GetTransactionForUpdate(tx, gomock.Any(), pending.ID).Return(nil, model.ErrorTransactionNotFound)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorTransactionNotFound))
+			},
+		},
+		{
+			name:      "Commit error",
+			confirmed: true,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				tr := *pending
+				tr.State = model.StateDepositPending
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().GetTransactionForUpdate(tx, gomock.Any(), tr.ID).Return(&tr, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, "EUR").Return(&model.WalletDao{}, nil)
+				trRepo.EXPECT().UpdateTransactionState(tx, gomock.Any(), tr.ID, model.StateDepositConfirmed).Return(nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), gomock.Any(), &model.WalletMovement{Real: 500}).DoAndReturn(applyMovement)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil)
+				db.EXPECT().Commit(tx).Return(dummyErr)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			trRepo := mocks.NewMockRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository(ctrl)
+			db := mocks.NewMockDatabase(ctrl)
+
+			tc.buildStubs(trRepo, userRepo, ledgerRepo, db)
+
+			usecase := New(nil, &config.Config{}, trRepo, userRepo, ledgerRepo, nil, nil, nil, nil, nil, db, nil)
+
+			if tc.confirmed {
+				tc.checkResponse(usecase.ConfirmPayment(context.Background(), pending.ID))
+			} else {
+				tc.checkResponse(usecase.FailPayment(context.Background(), pending.ID))
+			}
+		})
+	}
+}
+
+func TestAdjust(t *testing.T) {
+	tx := &sql.Tx{}
+	dummyErr := errors.New("dummy error")
+	user := &model.UserDao{ID: gofakeit.UUID(), Status: model.UserStatusClosed}
+
+	credit := &model.Adjustment{TransactionID: gofakeit.UUID(), Direction: model.DirectionCredit, Amount: 500, Currency: "EUR", Reason: "lost win", OperatorID: "agent-7", UserID: user.ID}
+	debit := &model.Adjustment{TransactionID: gofakeit.UUID(), Direction: model.DirectionDebit, Amount: 500, Currency: "EUR", Reason: "duplicate win", OperatorID: "agent-7", UserID: user.ID}
+
+	testCases := []struct {
+		name          string
+		adjustment    *model.Adjustment
+		buildStubs    func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase)
+		checkResponse func(tr *model.TransactionView, err error)
+	}{
+		{
+			name:       "Credit",
+			adjustment: credit,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				// Adjustments apply to closed users and to wagering wallets as real money.
+				wallet := &model.WalletDao{UserID: user.ID, Currency: "EUR", BonusBalance: 100, WageringRequired: 1000}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), credit.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(wallet, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, trDao *model.TransactionDao) error {
+					require.Equal(t, model.SourceTypeAdjustment, trDao.SourceType)
+					require.Equal(t, model.StateAdjustmentCredit, trDao.State)
+					require.Equal(t, "agent-7", *trDao.OperatorID)
+					require.Equal(t, "lost win", *trDao.Reason)
+
+					trDao.ID = "1"
+
+					return nil
+				})
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: 500}).DoAndReturn(applyMovement)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, entry *model.LedgerEntryDao) error {
+					require.Equal(t, "1", *entry.TransactionID)
+					require.Equal(t, model.LedgerReasonAdjustment, entry.Reason)
+					require.Equal(t, model.Money(500), entry.BalanceAfter)
+
+					return nil
+				})
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1", tr.ID)
+				require.Equal(t, "agent-7", *tr.OperatorID)
+			},
+		},
+		{
+			name:       "Debit over the available balance",
+			adjustment: debit,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), debit.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(&model.WalletDao{Balance: 800, Reserved: 301, BonusBalance: 1000}, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorInsufficientBalance))
+			},
+		},
+		{
+			name:       "Replayed adjustment",
+			adjustment: debit,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				existing := model.TransactionToTransactionDao(debit.Transaction())
+				existing.ID = "1"
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), debit.TransactionID).Return(existing, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1", tr.ID)
+			},
+		},
+		{
+			name:       "TransactionID of another transaction",
+			adjustment: credit,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				existing := model.TransactionToTransactionDao(credit.Transaction())
+				existing.SourceType = model.SourceTypeGame
+				existing.State = model.StateWin
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), credit.TransactionID).Return(existing, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorTransactionAlreadyExists))
+			},
+		},
+		{
+			name:       "CreateTransaction error",
+			adjustment: credit,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				trRepo.EXPECT().GetTransactionByTransactionID(tx, gomock.Any(), credit.TransactionID).Return(nil, model.ErrorTransactionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(&model.WalletDao{}, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(dummyErr)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(tr *model.TransactionView, err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			trRepo := mocks.NewMockRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository(ctrl)
+			db := mocks.NewMockDatabase(ctrl)
+
+			tc.buildStubs(trRepo, userRepo, ledgerRepo, db)
+
+			usecase := New(nil, &config.Config{}, trRepo, userRepo, ledgerRepo, nil, nil, nil, nil, nil, db, nil)
+			tc.checkResponse(usecase.Adjust(context.Background(), tc.adjustment))
+		})
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	tx := &sql.Tx{}
+	dummyErr := errors.New("dummy error")
+
+	// The sender sorts after the receiver, so the receiver is locked first.
+	sender := &model.UserDao{ID: "b", Status: model.UserStatusActive}
+	receiver := &model.UserDao{ID: "a", Status: model.UserStatusActive}
+
+	req := &model.Transfer{IdempotencyKey: "t-1", FromUserID: sender.ID, ToUserID: receiver.ID, Amount: 500, Currency: "EUR"}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, transferRepo *transferMocks.MockTransferRepository, db *mocks.MockDatabase)
+		checkResponse func(transfer *model.TransferView, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, transferRepo *transferMocks.MockTransferRepository, db *mocks.MockDatabase) {
+				// Transferred money is real money even while the sender is wagering a bonus.
+				from := &model.WalletDao{UserID: sender.ID, Currency: "EUR", Balance: 800, BonusBalance: 100, WageringRequired: 1000}
+				to := &model.WalletDao{UserID: receiver.ID, Currency: "EUR"}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				gomock.InOrder(
+					userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), receiver.ID).Return(receiver, nil),
+					userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), sender.ID).Return(sender, nil),
+					transferRepo.EXPECT().GetTransferByIdempotencyKey(tx, gomock.Any(), "t-1").Return(nil, model.ErrorTransferNotFound),
+					userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), receiver.ID, "EUR").Return(to, nil),
+					userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), sender.ID, "EUR").Return(from, nil),
+				)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, trDao *model.TransactionDao) error {
+					require.Equal(t, model.SourceTypeTransfer, trDao.SourceType)
+
+					trDao.ID = trDao.State
+
+					return nil
+				}).Times(2)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), from, &model.WalletMovement{Real: -500}).DoAndReturn(applyMovement)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), to, &model.WalletMovement{Real: 500}).DoAndReturn(applyMovement)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				transferRepo.EXPECT().CreateTransfer(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, transfer *model.TransferDao) error {
+					require.Equal(t, model.StateTransferOut, transfer.DebitTransactionID)
+					require.Equal(t, model.StateTransferIn, transfer.CreditTransactionID)
+
+					transfer.ID = "1"
+
+					return nil
+				})
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(transfer *model.TransferView, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1", transfer.ID)
+				require.Equal(t, sender.ID, transfer.FromUserID)
+			},
+		},
+		{
+			name: "Insufficient balance",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, transferRepo *transferMocks.MockTransferRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), gomock.Any()).Return(sender, nil).Times(2)
+				transferRepo.EXPECT().GetTransferByIdempotencyKey(tx, gomock.Any(), "t-1").Return(nil, model.ErrorTransferNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), receiver.ID, "EUR").Return(&model.WalletDao{UserID: receiver.ID}, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), sender.ID, "EUR").Return(&model.WalletDao{UserID: sender.ID, Balance: 800, Reserved: 301, BonusBalance: 1000}, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(transfer *model.TransferView, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorInsufficientBalance))
+			},
+		},
+		{
+			name: "Suspended receiver",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, transferRepo *transferMocks.MockTransferRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), receiver.ID).Return(&model.UserDao{ID: receiver.ID, Status: model.UserStatusSuspended}, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), sender.ID).Return(sender, nil)
+				transferRepo.EXPECT().GetTransferByIdempotencyKey(tx, gomock.Any(), "t-1").Return(nil, model.ErrorTransferNotFound)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(transfer *model.TransferView, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserSuspended))
+			},
+		},
+		{
+			name: "Replayed transfer",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, transferRepo *transferMocks.MockTransferRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), gomock.Any()).Return(sender, nil).Times(2)
+				transferRepo.EXPECT().GetTransferByIdempotencyKey(tx, gomock.Any(), "t-1").Return(&model.TransferDao{
+					ID:             "1",
+					IdempotencyKey: "t-1",
+					FromUserID:     sender.ID,
+					ToUserID:       receiver.ID,
+					Amount:         500,
+					Currency:       "EUR",
+				}, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(transfer *model.TransferView, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1", transfer.ID)
+			},
+		},
+		{
+			name: "Idempotency key of another transfer",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, transferRepo *transferMocks.MockTransferRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), gomock.Any()).Return(sender, nil).Times(2)
+				transferRepo.EXPECT().GetTransferByIdempotencyKey(tx, gomock.Any(), "t-1").Return(&model.TransferDao{
+					IdempotencyKey: "t-1",
+					FromUserID:     sender.ID,
+					ToUserID:       receiver.ID,
+					Amount:         100,
+					Currency:       "EUR",
+				}, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(transfer *model.TransferView, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorTransferAlreadyExists))
+			},
+		},
+		{
+			name: "CreateTransfer error",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, transferRepo *transferMocks.MockTransferRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), gomock.Any()).Return(sender, nil).Times(2)
+				transferRepo.EXPECT().GetTransferByIdempotencyKey(tx, gomock.Any(), "t-1").Return(nil, model.ErrorTransferNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), receiver.ID, "EUR").Return(&model.WalletDao{UserID: receiver.ID}, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), sender.ID, "EUR").Return(&model.WalletDao{UserID: sender.ID, Balance: 500}, nil)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(applyMovement).Times(2)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				transferRepo.EXPECT().CreateTransfer(tx, gomock.Any(), gomock.Any()).Return(dummyErr)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(transfer *model.TransferView, err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			trRepo := mocks.NewMockRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository(ctrl)
+			transferRepo := transferMocks.NewMockTransferRepository(ctrl)
+			db := mocks.NewMockDatabase(ctrl)
+
+			tc.buildStubs(trRepo, userRepo, ledgerRepo, transferRepo, db)
+
+			usecase := New(nil, &config.Config{}, trRepo, userRepo, ledgerRepo, nil, nil, nil, nil, transferRepo, db, nil)
+			tc.checkResponse(usecase.Transfer(context.Background(), req))
+		})
+	}
+}
+
+func TestPlaceBet(t *testing.T) {
+	tx := &sql.Tx{}
+	dummyErr := errors.New("dummy error")
+	user := &model.UserDao{ID: gofakeit.UUID(), Status: model.UserStatusActive}
+
+	req := &model.PlaceBet{BetID: gofakeit.UUID(), Stake: 300, Currency: "EUR", UserID: user.ID}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(betRepo *betMocks.MockBetRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase)
+		checkResponse func(b *model.Bet, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(betRepo *betMocks.MockBetRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				wallet := &model.WalletDao{UserID: user.ID, Currency: "EUR", Balance: 500, Reserved: 200}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				betRepo.EXPECT().GetBetByBetID(tx, gomock.Any(), req.BetID).Return(nil, model.ErrorBetNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), user.ID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(wallet, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), user.ID, "EUR", model.LimitKindLoss).Return(nil, nil)
+				betRepo.EXPECT().CreateBet(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, b *model.BetDao) error {
+					require.Equal(t, req.BetID, b.BetID)
+					require.Equal(t, req.Stake, b.Stake)
+					require.WithinDuration(t, time.Now().Add(defaultBetTTL), b.ExpiresAt, time.Second)
+
+					b.ID = "1"
+					b.State = model.BetStatePlaced
+
+					return nil
+				})
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Reserved: 300}).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1", b.ID)
+				require.Equal(t, model.BetStatePlaced, b.State)
+			},
+		},
+		{
+			name: "Stake over the available balance",
+			buildStubs: func(betRepo *betMocks.MockBetRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				betRepo.EXPECT().GetBetByBetID(tx, gomock.Any(), req.BetID).Return(nil, model.ErrorBetNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), user.ID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(&model.WalletDao{Balance: 500, Reserved: 201}, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorInsufficientBalance))
+			},
+		},
+		{
+			name: "Loss limit exceeded",
+			buildStubs: func(betRepo *betMocks.MockBetRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				betRepo.EXPECT().GetBetByBetID(tx, gomock.Any(), req.BetID).Return(nil, model.ErrorBetNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), user.ID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(&model.WalletDao{Balance: 500}, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), user.ID, "EUR", model.LimitKindLoss).Return([]*model.LimitDao{{Period: model.LimitPeriodDay, Amount: 400}}, nil)
+				limitRepo.EXPECT().GetUsage(tx, gomock.Any(), user.ID, "EUR", model.LimitKindLoss, gomock.Any()).Return(model.Money(200), nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorLossLimitExceeded))
+			},
+		},
+		{
+			name: "Replayed bet",
+			buildStubs: func(betRepo *betMocks.MockBetRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				existing := &model.BetDao{ID: "1", UserID: user.ID, Currency: "EUR", BetID: req.BetID, Stake: req.Stake, State: model.BetStateWon}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				betRepo.EXPECT().GetBetByBetID(tx, gomock.Any(), req.BetID).Return(existing, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1", b.ID)
+				require.Equal(t, model.BetStateWon, b.State)
+			},
+		},
+		{
+			name: "BetID with another payload",
+			buildStubs: func(betRepo *betMocks.MockBetRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				existing := &model.BetDao{ID: "1", UserID: user.ID, Currency: "EUR", BetID: req.BetID, Stake: req.Stake + 1}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				betRepo.EXPECT().GetBetByBetID(tx, gomock.Any(), req.BetID).Return(existing, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorBetAlreadyExists))
+			},
+		},
+		{
+			name: "CreateBet error",
+			buildStubs: func(betRepo *betMocks.MockBetRepository, userRepo *userMocks.MockUserRepository, limitRepo *limitMocks.MockLimitRepository, exclusionRepo *exclusionMocks.MockExclusionRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				userRepo.EXPECT().GetUserForUpdate(tx, gomock.Any(), user.ID).Return(user, nil)
+				betRepo.EXPECT().GetBetByBetID(tx, gomock.Any(), req.BetID).Return(nil, model.ErrorBetNotFound)
+				exclusionRepo.EXPECT().GetActiveExclusion(tx, gomock.Any(), user.ID).Return(nil, model.ErrorExclusionNotFound)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), user.ID, "EUR").Return(&model.WalletDao{Balance: 500}, nil)
+				limitRepo.EXPECT().GetLimitsByKind(tx, gomock.Any(), user.ID, "EUR", model.LimitKindLoss).Return(nil, nil)
+				betRepo.EXPECT().CreateBet(tx, gomock.Any(), gomock.Any()).Return(dummyErr)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			betRepo := betMocks.NewMockBetRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			limitRepo := limitMocks.NewMockLimitRepository(ctrl)
+			exclusionRepo := exclusionMocks.NewMockExclusionRepository(ctrl)
+			db := mocks.NewMockDatabase(ctrl)
+
+			tc.buildStubs(betRepo, userRepo, limitRepo, exclusionRepo, db)
+
+			usecase := New(nil, &config.Config{}, nil, userRepo, nil, limitRepo, exclusionRepo, nil, betRepo, nil, db, nil)
+			tc.checkResponse(usecase.PlaceBet(context.Background(), req))
+		})
+	}
+}
+
+func TestSettleBet(t *testing.T) {
+	tx := &sql.Tx{}
+	dummyErr := errors.New("dummy error")
+
+	placed := &model.BetDao{
+		ID:       "1",
+		UserID:   gofakeit.UUID(),
+		Currency: "EUR",
+		BetID:    gofakeit.UUID(),
+		Stake:    300,
+		State:    model.BetStatePlaced,
+	}
+
+	testCases := []struct {
+		name          string
+		req           *model.SettleBet
+		buildStubs    func(betRepo *betMocks.MockBetRepository, trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase)
+		checkResponse func(b *model.Bet, err error)
+	}{
+		{
+			name: "Loss",
+			req:  &model.SettleBet{ID: placed.ID, Outcome: model.BetOutcomeLoss},
+			buildStubs: func(betRepo *betMocks.MockBetRepository, trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				b := *placed
+				wallet := &model.WalletDao{UserID: b.UserID, Currency: "EUR", Balance: 300, Reserved: 300}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				betRepo.EXPECT().GetBetForUpdate(tx, gomock.Any(), b.ID).Return(&b, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), b.UserID, "EUR").Return(wallet, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Reserved: -300}).DoAndReturn(applyMovement)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, trDao *model.TransactionDao) error {
+					require.Equal(t, "bet:"+b.BetID, trDao.TransactionID)
+					require.Equal(t, model.StateLost, trDao.State)
+					require.Equal(t, model.Money(300), trDao.Amount)
+
+					trDao.ID = "2"
+
+					return nil
+				})
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -300}).DoAndReturn(applyMovement)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil)
+				betRepo.EXPECT().UpdateBet(tx, gomock.Any(), gomock.Any()).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.BetStateLost, b.State)
+				require.Equal(t, "2", *b.TransactionID)
+				require.NotNil(t, b.SettledAt)
+			},
+		},
+		{
+			name: "Win",
+			req:  &model.SettleBet{ID: placed.ID, Outcome: model.BetOutcomeWin, Amount: 600},
+			buildStubs: func(betRepo *betMocks.MockBetRepository, trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				b := *placed
+				wallet := &model.WalletDao{UserID: b.UserID, Currency: "EUR", Balance: 300, Reserved: 300}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				betRepo.EXPECT().GetBetForUpdate(tx, gomock.Any(), b.ID).Return(&b, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), b.UserID, "EUR").Return(wallet, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Reserved: -300}).DoAndReturn(applyMovement)
+				trRepo.EXPECT().CreateTransaction(tx, gomock.Any(), gomock.Any()).Return(nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: 600}).DoAndReturn(applyMovement)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil)
+				betRepo.EXPECT().UpdateBet(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, b *model.BetDao) error {
+					require.Equal(t, model.BetStateWon, b.State)
+					require.Equal(t, model.Money(600), b.WinAmount)
+
+					return nil
+				})
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.BetStateWon, b.State)
+			},
+		},
+		{
+			name: "Win of nothing",
+			req:  &model.SettleBet{ID: placed.ID, Outcome: model.BetOutcomeWin},
+			buildStubs: func(betRepo *betMocks.MockBetRepository, trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				b := *placed
+				wallet := &model.WalletDao{UserID: b.UserID, Currency: "EUR", Balance: 300, Reserved: 300}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				betRepo.EXPECT().GetBetForUpdate(tx, gomock.Any(), b.ID).Return(&b, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), b.UserID, "EUR").Return(wallet, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Reserved: -300}).DoAndReturn(applyMovement)
+				betRepo.EXPECT().UpdateBet(tx, gomock.Any(), gomock.Any()).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.BetStateWon, b.State)
+				require.Nil(t, b.TransactionID)
+			},
+		},
+		{
+			name: "Not placed",
+			req:  &model.SettleBet{ID: placed.ID, Outcome: model.BetOutcomeLoss},
+			buildStubs: func(betRepo *betMocks.MockBetRepository, trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				b := *placed
+				b.State = model.BetStateExpired
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				betRepo.EXPECT().GetBetForUpdate(tx, gomock.Any(), b.ID).Return(&b, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorBetNotPlaced))
+			},
+		},
+		{
+			name: "Bet not found",
+			req:  &model.SettleBet{ID: placed.ID, Outcome: model.BetOutcomeLoss},
+			buildStubs: func(betRepo *betMocks.MockBetRepository, trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				betRepo.EXPECT().GetBetForUpdate(tx, gomock.Any(), placed.ID).Return(nil, model.ErrorBetNotFound)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorBetNotFound))
+			},
+		},
+		{
+			name: "UpdateBet error",
+			req:  &model.SettleBet{ID: placed.ID, Outcome: model.BetOutcomeWin},
+			buildStubs: func(betRepo *betMocks.MockBetRepository, trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				b := *placed
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				betRepo.EXPECT().GetBetForUpdate(tx, gomock.Any(), b.ID).Return(&b, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), b.UserID, "EUR").Return(&model.WalletDao{Balance: 300, Reserved: 300}, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), gomock.Any(), &model.WalletMovement{Reserved: -300}).DoAndReturn(applyMovement)
+				betRepo.EXPECT().UpdateBet(tx, gomock.Any(), gomock.Any()).Return(dummyErr)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(b *model.Bet, err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			betRepo := betMocks.NewMockBetRepository(ctrl)
+			trRepo := mocks.NewMockRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository(ctrl)
+			db := mocks.NewMockDatabase(ctrl)
+
+			tc.buildStubs(betRepo, trRepo, userRepo, ledgerRepo, db)
+
+			usecase := New(nil, &config.Config{}, trRepo, userRepo, ledgerRepo, nil, nil, newTestRegistry(ctrl), betRepo, nil, db, nil)
+			tc.checkResponse(usecase.SettleBet(context.Background(), tc.req))
+		})
+	}
+}
+
+func TestVoidBet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	tx := &sql.Tx{}
+	b := &model.BetDao{ID: "1", UserID: gofakeit.UUID(), Currency: "EUR", Stake: 300, State: model.BetStatePlaced}
+	wallet := &model.WalletDao{UserID: b.UserID, Currency: "EUR", Balance: 300, Reserved: 300}
+
+	betRepo := betMocks.NewMockBetRepository(ctrl)
+	userRepo := userMocks.NewMockUserRepository(ctrl)
+	db := mocks.NewMockDatabase(ctrl)
+
+	db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+	betRepo.EXPECT().GetBetForUpdate(tx, gomock.Any(), b.ID).Return(b, nil)
+	userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), b.UserID, "EUR").Return(wallet, nil)
+	userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Reserved: -300}).DoAndReturn(applyMovement)
+	betRepo.EXPECT().UpdateBet(tx, gomock.Any(), b).Return(nil)
+	db.EXPECT().Commit(tx).Return(nil)
+
+	usecase := New(nil, &config.Config{}, nil, userRepo, nil, nil, nil, nil, betRepo, nil, db, nil)
+
+	voided, err := usecase.VoidBet(context.Background(), b.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.BetStateVoid, voided.State)
+	require.Equal(t, model.Money(0), wallet.Reserved)
+}
+
+func TestExpireBets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	tx := &sql.Tx{}
+	expired := &model.BetDao{ID: "1", UserID: gofakeit.UUID(), Currency: "EUR", Stake: 300, State: model.BetStatePlaced}
+	settled := &model.BetDao{ID: "2", State: model.BetStateWon}
+
+	betRepo := betMocks.NewMockBetRepository(ctrl)
+	userRepo := userMocks.NewMockUserRepository(ctrl)
+	db := mocks.NewMockDatabase(ctrl)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	betRepo.EXPECT().GetExpiredBets(gomock.Any(), 5).Return([]*model.BetDao{expired, settled}, nil).Times(1)
+	betRepo.EXPECT().GetExpiredBets(gomock.Any(), 5).Return(nil, nil).AnyTimes()
+	db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil).Times(2)
+	betRepo.EXPECT().GetBetForUpdate(tx, gomock.Any(), expired.ID).Return(expired, nil)
+	userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), expired.UserID, "EUR").Return(&model.WalletDao{Balance: 300, Reserved: 300}, nil)
+	userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), gomock.Any(), &model.WalletMovement{Reserved: -300}).Return(nil)
+	betRepo.EXPECT().UpdateBet(tx, gomock.Any(), expired).DoAndReturn(func(_ *sql.Tx, _ context.Context, b *model.BetDao) error {
+		require.Equal(t, model.BetStateExpired, b.State)
+
+		return nil
+	})
+	db.EXPECT().Commit(tx).Return(nil)
+	// The second bet was settled since it was selected.
+	betRepo.EXPECT().GetBetForUpdate(tx, gomock.Any(), settled.ID).Return(settled, nil)
+	db.EXPECT().Rollback(tx).DoAndReturn(func(*sql.Tx) error {
+		wg.Done()
+
+		return nil
+	})
+
+	usecase := New(slog.Default(), &config.Config{
+		Bets: config.Bets{
+			Interval:  time.Millisecond * 10,
+			BatchSize: 5,
+		},
+	}, nil, userRepo, nil, nil, nil, nil, betRepo, nil, db, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go usecase.ExpireBets(ctx)
+
+	wg.Wait()
+	cancel()
+
+	require.Eventually(t, func() bool {
+		return !usecase.IsBetExpiryRunning()
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestCancel(t *testing.T) {
+	tx := &sql.Tx{}
+	dummyErr := errors.New("dummy error")
+
+	tr := &model.TransactionDao{
+		ID:            gofakeit.UUID(),
+		UserID:        gofakeit.UUID(),
+		TransactionID: gofakeit.UUID(),
+		State:         "win",
+		Amount:        100,
+		Currency:      "EUR",
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase)
+		checkResponse func(err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				wallet := &model.WalletDao{UserID: tr.UserID, Currency: tr.Currency, Balance: 1000}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().CancelTransaction(tx, gomock.Any(), tr.ID).Return(tr, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -tr.Amount}).Return(nil)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *sql.Tx, _ context.Context, entry *model.LedgerEntryDao) error {
+					require.Equal(t, tr.ID, *entry.TransactionID)
+					require.Equal(t, model.LedgerReasonCancellation, entry.Reason)
+					require.Equal(t, -tr.Amount, entry.Amount)
+
+					return nil
+				})
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Transaction not found",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().CancelTransaction(tx, gomock.Any(), tr.ID).Return(nil, model.ErrorTransactionNotFound)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorTransactionNotFound))
+			},
+		},
+		{
+			name: "Pending withdrawal",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				withdrawal := *tr
+				withdrawal.SourceType = model.SourceTypePayment
+				withdrawal.State = model.StateWithdrawalPending
+				wallet := &model.WalletDao{UserID: tr.UserID, Currency: tr.Currency, Balance: 1000, Reserved: 100, BonusBalance: 10, WageringRequired: 500, Wagered: 100}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().CancelTransaction(tx, gomock.Any(), tr.ID).Return(&withdrawal, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Reserved: -tr.Amount}).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Transfer leg",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				leg := *tr
+				leg.SourceType = model.SourceTypeTransfer
+				leg.State = model.StateTransferIn
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().CancelTransaction(tx, gomock.Any(), tr.ID).Return(&leg, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorTransferState))
+			},
+		},
+		{
+			name: "Won amount already spent",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().CancelTransaction(tx, gomock.Any(), tr.ID).Return(tr, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(&model.WalletDao{UserID: tr.UserID, Currency: tr.Currency, Balance: 50}, nil)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorCancellationInsufficientBalance))
+			},
+		},
+		{
+			name: "Balance check constraint",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				wallet := &model.WalletDao{UserID: tr.UserID, Currency: tr.Currency, Balance: 1000}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().CancelTransaction(tx, gomock.Any(), tr.ID).Return(tr, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -tr.Amount}).Return(model.ErrorInsufficientBalance)
+				db.EXPECT().Rollback(tx).Return(nil)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorCancellationInsufficientBalance))
+			},
+		},
+		{
+			name: "Commit error",
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase) {
+				wallet := &model.WalletDao{UserID: tr.UserID, Currency: tr.Currency, Balance: 1000}
+
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().CancelTransaction(tx, gomock.Any(), tr.ID).Return(tr, nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), tr.UserID, tr.Currency).Return(wallet, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -tr.Amount}).Return(nil)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil)
+				db.EXPECT().Commit(tx).Return(dummyErr)
+			},
+			checkResponse: func(err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			trRepo := mocks.NewMockRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository(ctrl)
+			db := mocks.NewMockDatabase(ctrl)
+
+			tc.buildStubs(trRepo, userRepo, ledgerRepo, db)
+
+			usecase := New(nil, &config.Config{}, trRepo, userRepo, ledgerRepo, nil, nil, newTestRegistry(ctrl), nil, nil, db, nil)
+			err := usecase.Cancel(context.Background(), tr.ID)
+
+			tc.checkResponse(err)
+		})
+	}
+}
+
+func TestGetTransactions(t *testing.T) {
+	userID := gofakeit.UUID()
+	dummyErr := errors.New("dummy error")
+
+	transactions := []*model.TransactionDao{
+		{ID: gofakeit.UUID(), Seq: 3, UserID: userID, State: "win", Amount: 100},
+		{ID: gofakeit.UUID(), Seq: 2, UserID: userID, State: "lost", Amount: 50},
+		{ID: gofakeit.UUID(), Seq: 1, UserID: userID, State: "win", Amount: 10},
+	}
+
+	testCases := []struct {
+		name          string
+		limit         int
+		buildStubs    func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository)
+		checkResponse func(page *model.TransactionPage, err error)
+	}{
+		{
+			name:  "Next page",
+			limit: 2,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), userID).Return(&model.UserDao{ID: userID}, nil)
+				trRepo.EXPECT().GetUserTransactions(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, filter *model.TransactionFilter) ([]*model.TransactionDao, error) {
+						require.Equal(t, 3, filter.Limit)
+
+						return transactions, nil
+					})
+			},
+			checkResponse: func(page *model.TransactionPage, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, len(page.Transactions))
+				require.Equal(t, transactions[0].ID, page.Transactions[0].ID)
+				require.Equal(t, model.EncodeCursor(2), page.NextCursor)
+			},
+		},
+		{
+			name:  "Last page",
+			limit: 3,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), userID).Return(&model.UserDao{ID: userID}, nil)
+				trRepo.EXPECT().GetUserTransactions(gomock.Any(), gomock.Any()).Return(transactions, nil)
+			},
+			checkResponse: func(page *model.TransactionPage, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 3, len(page.Transactions))
+				require.Empty(t, page.NextCursor)
+			},
+		},
+		{
+			name:  "User not found",
+			limit: 3,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), userID).Return(nil, model.ErrorUserNotFound)
+			},
+			checkResponse: func(page *model.TransactionPage, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserNotFound))
+				require.Nil(t, page)
+			},
+		},
+		{
+			name:  "GetUserTransactions error",
+			limit: 3,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), userID).Return(&model.UserDao{ID: userID}, nil)
+				trRepo.EXPECT().GetUserTransactions(gomock.Any(), gomock.Any()).Return(nil, dummyErr)
+			},
+			checkResponse: func(page *model.TransactionPage, err error) {
+				require.Equal(t, true, errors.Is(err, dummyErr))
+				require.Nil(t, page)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			trRepo := mocks.NewMockRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			db := mocks.NewMockDatabase(ctrl)
+
+			tc.buildStubs(trRepo, userRepo)
+
+			usecase := New(nil, &config.Config{}, trRepo, userRepo, nil, nil, nil, nil, nil, nil, db, nil)
+			page, err := usecase.GetTransactions(context.Background(), &model.TransactionFilter{
+				UserID: userID,
+				Limit:  tc.limit,
+			})
+
+			tc.checkResponse(page, err)
+		})
+	}
+}
+
+func TestPostProcess(t *testing.T) {
+	tx := &sql.Tx{}
+	transactions := []*model.TransactionDao{
+		{
+			ID:            gofakeit.UUID(),
+			UserID:        gofakeit.UUID(),
+			TransactionID: gofakeit.UUID(),
+			State:         "win",
+			Amount:        100,
+			Currency:      "EUR",
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		calls      int
+		buildStubs func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase, elector *mocks.MockElector, wg *sync.WaitGroup)
+	}{
+		{
+			name:  "OK",
+			calls: 2,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase, elector *mocks.MockElector, wg *sync.WaitGroup) {
+				wallet := &model.WalletDao{UserID: transactions[0].UserID, Currency: transactions[0].Currency, Balance: 1000}
+
+				elector.EXPECT().IsLeader(gomock.Any()).Return(true, nil).AnyTimes()
+				elector.EXPECT().Resign(gomock.Any()).Return(nil).AnyTimes()
+				trRepo.EXPECT().GetLatestOddAndUncancelledTransactions(gomock.Any(), gomock.Any()).Return(transactions, nil).Do(func(arg0, ar1 interface{}) {
+					defer wg.Done()
+				})
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				trRepo.EXPECT().CancelTransaction(tx, gomock.Any(), transactions[0].ID).Return(transactions[0], nil)
+				userRepo.EXPECT().GetWalletForUpdate(tx, gomock.Any(), wallet.UserID, wallet.Currency).Return(wallet, nil)
+				userRepo.EXPECT().UpdateWalletBalance(tx, gomock.Any(), wallet, &model.WalletMovement{Real: -transactions[0].Amount}).Return(nil)
+				ledgerRepo.EXPECT().CreateEntry(tx, gomock.Any(), gomock.Any()).Return(nil)
+				db.EXPECT().Commit(tx).Return(nil).Do(func(arg0 interface{}) {
+					defer wg.Done()
+				})
+				trRepo.EXPECT().GetLatestOddAndUncancelledTransactions(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			},
+		},
+		{
+			name:  "Not leader",
+			calls: 1,
+			buildStubs: func(trRepo *mocks.MockRepository, userRepo *userMocks.MockUserRepository, ledgerRepo *ledgerMocks.MockLedgerRepository, db *mocks.MockDatabase, elector *mocks.MockElector, wg *sync.WaitGroup) {
+				elector.EXPECT().IsLeader(gomock.Any()).Return(false, nil).Do(func(arg0 interface{}) {
+					defer wg.Done()
+				})
+				elector.EXPECT().IsLeader(gomock.Any()).Return(false, nil).AnyTimes()
+				elector.EXPECT().Resign(gomock.Any()).Return(nil).AnyTimes()
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+
+			wg := sync.WaitGroup{}
+			wg.Add(tc.calls)
+
+			defer ctrl.Finish()
+
+			trRepo := mocks.NewMockRepository(ctrl)
+			userRepo := userMocks.NewMockUserRepository(ctrl)
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository(ctrl)
+			db := mocks.NewMockDatabase(ctrl)
+			elector := mocks.NewMockElector(ctrl)
+
+			tc.buildStubs(trRepo, userRepo, ledgerRepo, db, elector, &wg)
+
+			usecase := New(slog.Default(), &config.Config{
+				PostProcess: config.PostProcess{
+					Enabled:   true,
+					Interval:  time.Millisecond * 10,
+					BatchSize: 10,
+				},
+			}, trRepo, userRepo, ledgerRepo, nil, nil, newTestRegistry(ctrl), nil, nil, db, elector)
+			require.Equal(t, false, usecase.IsPostProcessRunning())
+
+			go usecase.PostProcess(ctx)
+			wg.Wait()
+
+			require.Equal(t, true, usecase.IsPostProcessRunning())
+
+			cancel()
+
+			require.Eventually(t, func() bool {
+				return !usecase.IsPostProcessRunning()
+			}, time.Second, time.Millisecond*10)
 		})
 	}
 }
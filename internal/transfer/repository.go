@@ -0,0 +1,16 @@
+package transfer
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+//go:generate mockgen -source ./repository.go -mock_names Repository=MockTransferRepository -package mocks -destination mocks/transferRepository.mock.gen.go
+
+// Repository is a repository for the transfers between users.
+type Repository interface {
+	CreateTransfer(tx *sql.Tx, ctx context.Context, transfer *model.TransferDao) error
+	GetTransferByIdempotencyKey(tx *sql.Tx, ctx context.Context, key string) (*model.TransferDao, error)
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Transfer is the repository for transfers.
+type Transfer struct {
+	conn *sqlx.DB
+}
+
+// New returns a new Transfer object.
+func New(conn *sqlx.DB) *Transfer {
+	return &Transfer{
+		conn: conn,
+	}
+}
+
+// CreateTransfer inserts a transfer with its two transactions within the given db tx.
+func (t *Transfer) CreateTransfer(tx *sql.Tx, ctx context.Context, transfer *model.TransferDao) error {
+	query := `
+		INSERT INTO transfers (
+			idempotency_key,
+			from_user_id,
+			to_user_id,
+			amount,
+			currency,
+			debit_transaction_id,
+			credit_transaction_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at;
+	`
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		transfer.IdempotencyKey,
+		transfer.FromUserID,
+		transfer.ToUserID,
+		transfer.Amount,
+		transfer.Currency,
+		transfer.DebitTransactionID,
+		transfer.CreditTransactionID,
+	).Scan(&transfer.ID, &transfer.CreatedAt)
+
+	if err != nil {
+		var pqError *pq.Error
+		if errors.As(err, &pqError) && pqError.Constraint == "unique_idempotency_key" {
+			return fmt.Errorf("failed to insert transfer because of unique constraint: %w", model.ErrorTransferAlreadyExists)
+		}
+
+		return fmt.Errorf("failed to execute insert transfer query: %w", err)
+	}
+
+	return nil
+}
+
+// GetTransferByIdempotencyKey returns a transfer by its idempotency key.
+func (t *Transfer) GetTransferByIdempotencyKey(tx *sql.Tx, ctx context.Context, key string) (*model.TransferDao, error) {
+	query := `
+		SELECT
+			id,
+			idempotency_key,
+			from_user_id,
+			to_user_id,
+			amount,
+			currency,
+			debit_transaction_id,
+			credit_transaction_id,
+			created_at
+		FROM transfers
+		WHERE idempotency_key = $1;
+	`
+	transfer := &model.TransferDao{}
+
+	err := tx.QueryRowContext(ctx, query, key).Scan(
+		&transfer.ID,
+		&transfer.IdempotencyKey,
+		&transfer.FromUserID,
+		&transfer.ToUserID,
+		&transfer.Amount,
+		&transfer.Currency,
+		&transfer.DebitTransactionID,
+		&transfer.CreditTransactionID,
+		&transfer.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed because transfer not found: %w", model.ErrorTransferNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to execute get transfer query: %w", err)
+	}
+
+	return transfer, nil
+}
@@ -0,0 +1,112 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/ttagiyeva/entain/internal/database"
+	"github.com/ttagiyeva/entain/internal/model"
+	trRepo "github.com/ttagiyeva/entain/internal/transaction/repository"
+	"github.com/ttagiyeva/entain/internal/transfer/repository"
+	"github.com/ttagiyeva/entain/internal/util"
+)
+
+const userID = "00000000-0000-0000-0000-000000000001"
+
+type transferRepoTestSuite struct {
+	suite.Suite
+	testcontainers.Container
+	db   *database.Postgres
+	repo *repository.Transfer
+	ctx  context.Context
+}
+
+func TestTransferRepoTestSuite(t *testing.T) {
+	suite.Run(t, &transferRepoTestSuite{})
+}
+
+func (s *transferRepoTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	s.db = util.CreateTestContainer(s.ctx, &s.Suite)
+	s.repo = repository.New(s.db.Connection)
+}
+
+func (s *transferRepoTestSuite) SetupTest() {
+	if err := s.db.MigrateUp(); err != nil || errors.Is(err, migrate.ErrNoChange) {
+		s.Require().NoError(err)
+	}
+}
+
+func (s *transferRepoTestSuite) TearDownTest() {
+	s.NoError(s.db.MigrateDown())
+}
+
+// newTransfer creates a receiver with a euro wallet and the two transactions of a transfer to it.
+func (s *transferRepoTestSuite) newTransfer(tx *sql.Tx) *model.TransferDao {
+	receiver := faker.UUIDHyphenated()
+
+	_, err := tx.ExecContext(s.ctx, "INSERT INTO users (id) VALUES ($1)", receiver)
+	s.Require().NoError(err)
+
+	_, err = tx.ExecContext(s.ctx, "INSERT INTO wallets (user_id, currency) VALUES ($1, 'EUR')", receiver)
+	s.Require().NoError(err)
+
+	req := &model.Transfer{IdempotencyKey: faker.UUIDHyphenated(), FromUserID: userID, ToUserID: receiver, Amount: 100, Currency: "EUR"}
+	debit, credit := req.Transactions()
+
+	transactions := trRepo.New(s.db.Connection)
+
+	debitDao := model.TransactionToTransactionDao(debit)
+	s.Require().NoError(transactions.CreateTransaction(tx, s.ctx, debitDao))
+
+	creditDao := model.TransactionToTransactionDao(credit)
+	s.Require().NoError(transactions.CreateTransaction(tx, s.ctx, creditDao))
+
+	return &model.TransferDao{
+		IdempotencyKey:      req.IdempotencyKey,
+		FromUserID:          req.FromUserID,
+		ToUserID:            req.ToUserID,
+		Amount:              req.Amount,
+		Currency:            req.Currency,
+		DebitTransactionID:  debitDao.ID,
+		CreditTransactionID: creditDao.ID,
+	}
+}
+
+func (s *transferRepoTestSuite) TestCreateTransfer() {
+	tx := s.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
+
+	transfer := s.newTransfer(tx)
+	s.Require().NoError(s.repo.CreateTransfer(tx, s.ctx, transfer))
+	s.NotEmpty(transfer.ID)
+	s.NotEmpty(transfer.CreatedAt)
+
+	stored, err := s.repo.GetTransferByIdempotencyKey(tx, s.ctx, transfer.IdempotencyKey)
+	s.NoError(err)
+	s.Equal(transfer.ID, stored.ID)
+	s.Equal(transfer.DebitTransactionID, stored.DebitTransactionID)
+	s.Equal(transfer.CreditTransactionID, stored.CreditTransactionID)
+
+	_, err = s.repo.GetTransferByIdempotencyKey(tx, s.ctx, faker.UUIDHyphenated())
+	s.Equal(true, errors.Is(err, model.ErrorTransferNotFound))
+}
+
+func (s *transferRepoTestSuite) TestCreateTransferTwice() {
+	tx := s.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
+
+	transfer := s.newTransfer(tx)
+	s.Require().NoError(s.repo.CreateTransfer(tx, s.ctx, transfer))
+
+	again := *transfer
+	err := s.repo.CreateTransfer(tx, s.ctx, &again)
+	s.Equal(true, errors.Is(err, model.ErrorTransferAlreadyExists))
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/user"
+)
+
+// Handler is a structure which manages user http handlers.
+type Handler struct {
+	log     *slog.Logger
+	usecase user.Usecase
+}
+
+// NewHandler creates a new user http handler.
+func NewHandler(log *slog.Logger, u user.Usecase) *Handler {
+	return &Handler{
+		log:     log,
+		usecase: u,
+	}
+}
+
+// CreateUser creates a new user.
+func (h *Handler) CreateUser(ctx echo.Context) error {
+	user, err := h.usecase.CreateUser(ctx.Request().Context())
+	if err != nil {
+		h.log.Error("failed to create user", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusCreated, user)
+}
+
+// GetUser returns the user.
+func (h *Handler) GetUser(ctx echo.Context) error {
+	return h.respondUser(ctx, "failed to get user", h.usecase.GetUser)
+}
+
+// Suspend suspends the user.
+func (h *Handler) Suspend(ctx echo.Context) error {
+	return h.respondUser(ctx, "failed to suspend user", h.usecase.Suspend)
+}
+
+// Activate activates the suspended user.
+func (h *Handler) Activate(ctx echo.Context) error {
+	return h.respondUser(ctx, "failed to activate user", h.usecase.Activate)
+}
+
+// Close closes the wallet of the user.
+func (h *Handler) Close(ctx echo.Context) error {
+	return h.respondUser(ctx, "failed to close user", h.usecase.Close)
+}
+
+func (h *Handler) respondUser(ctx echo.Context, msg string, fn func(context.Context, string) (*model.User, error)) error {
+	id := ctx.Param("id")
+
+	user, err := fn(ctx.Request().Context(), id)
+	if err != nil {
+		h.log.With("id", id).Error(msg, "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+// GetBalance returns the current balance of the user.
+func (h *Handler) GetBalance(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	balance, err := h.usecase.GetBalance(ctx.Request().Context(), id)
+	if err != nil {
+		h.log.With("id", id).Error("failed to get user balance", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusOK, balance)
+}
+
+// OpenWallet opens a wallet for the user in the requested currency.
+func (h *Handler) OpenWallet(ctx echo.Context) error {
+	id := ctx.Param("id")
+	req := &model.OpenWallet{}
+
+	err := ctx.Bind(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: model.ErrorBadRequest,
+		})
+	}
+
+	err = model.NewValidator().Struct(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, model.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Value of the Currency field must be an ISO 4217 currency code",
+		})
+	}
+
+	wallet, err := h.usecase.OpenWallet(ctx.Request().Context(), id, req.Currency)
+	if err != nil {
+		h.log.With("id", id, "currency", req.Currency).Error("failed to open wallet", "error", err)
+
+		resp := getError(err)
+
+		return ctx.JSON(resp.Code, resp)
+	}
+
+	return ctx.JSON(http.StatusCreated, wallet)
+}
+
+func getError(err error) model.Error {
+	switch {
+	case errors.Is(err, model.ErrorUserNotFound):
+		return model.Error{Code: http.StatusNotFound, Message: model.ErrorUserNotFound.Error()}
+	case errors.Is(err, model.ErrorUserStatusConflict):
+		return model.Error{Code: http.StatusConflict, Message: model.ErrorUserStatusConflict.Error()}
+	case errors.Is(err, model.ErrorUserHasFunds):
+		return model.Error{Code: http.StatusConflict, Message: model.ErrorUserHasFunds.Error()}
+	case errors.Is(err, model.ErrorUserClosed):
+		return model.Error{Code: http.StatusGone, Message: model.ErrorUserClosed.Error()}
+	case errors.Is(err, model.ErrorWalletAlreadyExists):
+		return model.Error{Code: http.StatusConflict, Message: model.ErrorWalletAlreadyExists.Error()}
+	default:
+		return model.Error{Code: http.StatusInternalServerError, Message: model.ErrorInternalServerError.Error()}
+	}
+}
@@ -0,0 +1,299 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/user/mocks"
+)
+
+// TestUserHandler_GetBalance tests the user handler get balance method.
+func TestUserHandler_GetBalance(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(userUsecase *mocks.MockUserUsecase)
+		expectedCode  int
+		expectedBody  string
+		expectedError model.Error
+	}{
+		{
+			name: "OK",
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().GetBalance(gomock.Any(), "1").Return(&model.Balance{
+					UserID: "1",
+					Wallets: []*model.Wallet{
+						{Currency: "EUR", Balance: "10.15", Reserved: "3.00", BonusBalance: "5.00", WageringRequired: "20.00", Wagered: "2.50", UpdatedAt: updatedAt},
+						{Currency: "USD", Balance: "0.00", Reserved: "0.00", BonusBalance: "0.00", WageringRequired: "0.00", Wagered: "0.00", UpdatedAt: updatedAt},
+					},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"userId":"1","wallets":[` +
+				`{"currency":"EUR","balance":"10.15","reserved":"3.00","bonusBalance":"5.00","wageringRequired":"20.00","wagered":"2.50","updatedAt":"2024-05-01T10:00:00Z"},` +
+				`{"currency":"USD","balance":"0.00","reserved":"0.00","bonusBalance":"0.00","wageringRequired":"0.00","wagered":"0.00","updatedAt":"2024-05-01T10:00:00Z"}]}`,
+		},
+		{
+			name: "User not found",
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().GetBalance(gomock.Any(), "1").Return(nil, model.ErrorUserNotFound)
+			},
+			expectedError: getError(model.ErrorUserNotFound),
+		},
+		{
+			name: "Internal server error",
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().GetBalance(gomock.Any(), "1").Return(nil, fmt.Errorf("unexpected error"))
+			},
+			expectedError: getError(fmt.Errorf("unexpected error")),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userUsecase := mocks.NewMockUserUsecase(ctrl)
+			tc.buildStubs(userUsecase)
+
+			handler := NewHandler(slog.Default(), userUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/users/1/balance", nil)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			err := handler.GetBalance(c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+				require.JSONEq(t, tc.expectedBody, rec.Body.String())
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+// TestUserHandler_Lifecycle tests the user handler create, get and status change methods.
+func TestUserHandler_Lifecycle(t *testing.T) {
+	user := &model.User{ID: "1", Status: model.UserStatusActive}
+
+	testCases := []struct {
+		name          string
+		handle        func(h *Handler, c echo.Context) error
+		buildStubs    func(userUsecase *mocks.MockUserUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name: "Create",
+			handle: func(h *Handler, c echo.Context) error {
+				return h.CreateUser(c)
+			},
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().CreateUser(gomock.Any()).Return(user, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "Get",
+			handle: func(h *Handler, c echo.Context) error {
+				return h.GetUser(c)
+			},
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().GetUser(gomock.Any(), "1").Return(user, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "Get not found",
+			handle: func(h *Handler, c echo.Context) error {
+				return h.GetUser(c)
+			},
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().GetUser(gomock.Any(), "1").Return(nil, model.ErrorUserNotFound)
+			},
+			expectedError: getError(model.ErrorUserNotFound),
+		},
+		{
+			name: "Suspend",
+			handle: func(h *Handler, c echo.Context) error {
+				return h.Suspend(c)
+			},
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().Suspend(gomock.Any(), "1").Return(user, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "Activate",
+			handle: func(h *Handler, c echo.Context) error {
+				return h.Activate(c)
+			},
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().Activate(gomock.Any(), "1").Return(user, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "Close conflict",
+			handle: func(h *Handler, c echo.Context) error {
+				return h.Close(c)
+			},
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().Close(gomock.Any(), "1").Return(nil, model.ErrorUserStatusConflict)
+			},
+			expectedError: model.Error{Code: http.StatusConflict, Message: model.ErrorUserStatusConflict.Error()},
+		},
+		{
+			name: "Close with funds",
+			handle: func(h *Handler, c echo.Context) error {
+				return h.Close(c)
+			},
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().Close(gomock.Any(), "1").Return(nil, fmt.Errorf("failed to close user: %w", model.ErrorUserHasFunds))
+			},
+			expectedError: model.Error{Code: http.StatusConflict, Message: model.ErrorUserHasFunds.Error()},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userUsecase := mocks.NewMockUserUsecase(ctrl)
+			tc.buildStubs(userUsecase)
+
+			handler := NewHandler(slog.Default(), userUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/1", nil)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			err := tc.handle(handler, c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
+
+// TestUserHandler_OpenWallet tests the user handler open wallet method.
+func TestUserHandler_OpenWallet(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(userUsecase *mocks.MockUserUsecase)
+		expectedCode  int
+		expectedError model.Error
+	}{
+		{
+			name: "OK",
+			body: []byte(`{"currency":"USD"}`),
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().OpenWallet(gomock.Any(), "1", "USD").Return(&model.Wallet{Currency: "USD", Balance: "0.00"}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:       "Invalid currency",
+			body:       []byte(`{"currency":"XXY"}`),
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {},
+			expectedError: model.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Value of the Currency field must be an ISO 4217 currency code",
+			},
+		},
+		{
+			name: "Wallet already exists",
+			body: []byte(`{"currency":"USD"}`),
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().OpenWallet(gomock.Any(), "1", "USD").Return(nil, model.ErrorWalletAlreadyExists)
+			},
+			expectedError: model.Error{Code: http.StatusConflict, Message: model.ErrorWalletAlreadyExists.Error()},
+		},
+		{
+			name: "Closed user",
+			body: []byte(`{"currency":"USD"}`),
+			buildStubs: func(userUsecase *mocks.MockUserUsecase) {
+				userUsecase.EXPECT().OpenWallet(gomock.Any(), "1", "USD").Return(nil, model.ErrorUserClosed)
+			},
+			expectedError: model.Error{Code: http.StatusGone, Message: model.ErrorUserClosed.Error()},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userUsecase := mocks.NewMockUserUsecase(ctrl)
+			tc.buildStubs(userUsecase)
+
+			handler := NewHandler(slog.Default(), userUsecase)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/users/1/wallets", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			err := handler.OpenWallet(c)
+			require.NoError(t, err)
+
+			if tc.expectedCode != 0 {
+				require.Equal(t, tc.expectedCode, rec.Code)
+			} else {
+				require.Equal(t, tc.expectedError.Code, rec.Code)
+
+				expectedErr, err := json.Marshal(tc.expectedError)
+				require.NoError(t, err)
+
+				require.JSONEq(t, rec.Body.String(), string(expectedErr))
+			}
+		})
+	}
+}
@@ -36,6 +36,54 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CloseUser mocks base method.
+func (m *MockUserRepository) CloseUser(ctx context.Context, id string, from ...string) (*model.UserDao, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, id}
+	for _, a := range from {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CloseUser", varargs...)
+	ret0, _ := ret[0].(*model.UserDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseUser indicates an expected call of CloseUser.
+func (mr *MockUserRepositoryMockRecorder) CloseUser(ctx, id interface{}, from ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, id}, from...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseUser", reflect.TypeOf((*MockUserRepository)(nil).CloseUser), varargs...)
+}
+
+// CreateUser mocks base method.
+func (m *MockUserRepository) CreateUser(ctx context.Context, user *model.UserDao) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserRepositoryMockRecorder) CreateUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepository)(nil).CreateUser), ctx, user)
+}
+
+// CreateWallet mocks base method.
+func (m *MockUserRepository) CreateWallet(ctx context.Context, wallet *model.WalletDao) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWallet", ctx, wallet)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWallet indicates an expected call of CreateWallet.
+func (mr *MockUserRepositoryMockRecorder) CreateWallet(ctx, wallet interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockUserRepository)(nil).CreateWallet), ctx, wallet)
+}
+
 // GetUser mocks base method.
 func (m *MockUserRepository) GetUser(ctx context.Context, id string) (*model.UserDao, error) {
 	m.ctrl.T.Helper()
@@ -51,16 +99,81 @@ func (mr *MockUserRepositoryMockRecorder) GetUser(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserRepository)(nil).GetUser), ctx, id)
 }
 
-// UpdateUserBalance mocks base method.
-func (m *MockUserRepository) UpdateUserBalance(tx *sql.Tx, ctx context.Context, user *model.UserDao) error {
+// GetUserForUpdate mocks base method.
+func (m *MockUserRepository) GetUserForUpdate(tx *sql.Tx, ctx context.Context, id string) (*model.UserDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserForUpdate", tx, ctx, id)
+	ret0, _ := ret[0].(*model.UserDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserForUpdate indicates an expected call of GetUserForUpdate.
+func (mr *MockUserRepositoryMockRecorder) GetUserForUpdate(tx, ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForUpdate", reflect.TypeOf((*MockUserRepository)(nil).GetUserForUpdate), tx, ctx, id)
+}
+
+// GetWalletForUpdate mocks base method.
+func (m *MockUserRepository) GetWalletForUpdate(tx *sql.Tx, ctx context.Context, userID, currency string) (*model.WalletDao, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUserBalance", tx, ctx, user)
+	ret := m.ctrl.Call(m, "GetWalletForUpdate", tx, ctx, userID, currency)
+	ret0, _ := ret[0].(*model.WalletDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletForUpdate indicates an expected call of GetWalletForUpdate.
+func (mr *MockUserRepositoryMockRecorder) GetWalletForUpdate(tx, ctx, userID, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletForUpdate", reflect.TypeOf((*MockUserRepository)(nil).GetWalletForUpdate), tx, ctx, userID, currency)
+}
+
+// GetWallets mocks base method.
+func (m *MockUserRepository) GetWallets(ctx context.Context, userID string) ([]*model.WalletDao, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWallets", ctx, userID)
+	ret0, _ := ret[0].([]*model.WalletDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWallets indicates an expected call of GetWallets.
+func (mr *MockUserRepositoryMockRecorder) GetWallets(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWallets", reflect.TypeOf((*MockUserRepository)(nil).GetWallets), ctx, userID)
+}
+
+// UpdateUserStatus mocks base method.
+func (m *MockUserRepository) UpdateUserStatus(ctx context.Context, id, status string, from ...string) (*model.UserDao, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, id, status}
+	for _, a := range from {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateUserStatus", varargs...)
+	ret0, _ := ret[0].(*model.UserDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserStatus indicates an expected call of UpdateUserStatus.
+func (mr *MockUserRepositoryMockRecorder) UpdateUserStatus(ctx, id, status interface{}, from ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, id, status}, from...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStatus", reflect.TypeOf((*MockUserRepository)(nil).UpdateUserStatus), varargs...)
+}
+
+// UpdateWalletBalance mocks base method.
+func (m_2 *MockUserRepository) UpdateWalletBalance(tx *sql.Tx, ctx context.Context, wallet *model.WalletDao, m *model.WalletMovement) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "UpdateWalletBalance", tx, ctx, wallet, m)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateUserBalance indicates an expected call of UpdateUserBalance.
-func (mr *MockUserRepositoryMockRecorder) UpdateUserBalance(tx, ctx, user interface{}) *gomock.Call {
+// UpdateWalletBalance indicates an expected call of UpdateWalletBalance.
+func (mr *MockUserRepositoryMockRecorder) UpdateWalletBalance(tx, ctx, wallet, m interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserBalance", reflect.TypeOf((*MockUserRepository)(nil).UpdateUserBalance), tx, ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWalletBalance", reflect.TypeOf((*MockUserRepository)(nil).UpdateWalletBalance), tx, ctx, wallet, m)
 }
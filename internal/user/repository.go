@@ -11,6 +11,13 @@ import (
 
 // Repository is a repository for users
 type Repository interface {
+	CreateUser(ctx context.Context, user *model.UserDao) error
 	GetUser(ctx context.Context, id string) (*model.UserDao, error)
-	UpdateUserBalance(tx *sql.Tx, ctx context.Context, user *model.UserDao) error
+	GetUserForUpdate(tx *sql.Tx, ctx context.Context, id string) (*model.UserDao, error)
+	UpdateUserStatus(ctx context.Context, id, status string, from ...string) (*model.UserDao, error)
+	CloseUser(ctx context.Context, id string, from ...string) (*model.UserDao, error)
+	CreateWallet(ctx context.Context, wallet *model.WalletDao) error
+	GetWallets(ctx context.Context, userID string) ([]*model.WalletDao, error)
+	GetWalletForUpdate(tx *sql.Tx, ctx context.Context, userID, currency string) (*model.WalletDao, error)
+	UpdateWalletBalance(tx *sql.Tx, ctx context.Context, wallet *model.WalletDao, m *model.WalletMovement) error
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
@@ -23,14 +24,39 @@ func New(conn *sqlx.DB) *User {
 	}
 }
 
+// CreateUser creates a new active user without wallets.
+func (a *User) CreateUser(ctx context.Context, user *model.UserDao) error {
+	query := `
+		INSERT INTO users DEFAULT VALUES
+		RETURNING id, status, created_at, updated_at;
+	`
+	err := a.conn.QueryRowContext(
+		ctx,
+		query,
+	).Scan(
+		&user.ID,
+		&user.Status,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to execute insert user query: %w", err)
+	}
+
+	return nil
+}
+
 // GetUser returns a user by id.
 func (a *User) GetUser(ctx context.Context, id string) (*model.UserDao, error) {
 	query := `
 		SELECT
 			id,
-			balance
+			status,
+			created_at,
+			updated_at
 		FROM users
-		WHERE id = $1 FOR UPDATE;
+		WHERE id = $1;
 	`
 	user := &model.UserDao{}
 
@@ -40,7 +66,9 @@ func (a *User) GetUser(ctx context.Context, id string) (*model.UserDao, error) {
 		id,
 	).Scan(
 		&user.ID,
-		&user.Balance,
+		&user.Status,
+		&user.CreatedAt,
+		&user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -54,31 +82,138 @@ func (a *User) GetUser(ctx context.Context, id string) (*model.UserDao, error) {
 	return user, nil
 }
 
-// UpdateUserBalance updates the balance of a user.
-func (a *User) UpdateUserBalance(tx *sql.Tx, ctx context.Context, user *model.UserDao) error {
+// GetUserForUpdate returns a user by id and locks the row until the given db tx ends.
+func (a *User) GetUserForUpdate(tx *sql.Tx, ctx context.Context, id string) (*model.UserDao, error) {
+	query := `
+		SELECT
+			id,
+			status
+		FROM users
+		WHERE id = $1 FOR UPDATE;
+	`
+	user := &model.UserDao{}
+
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		id,
+	).Scan(
+		&user.ID,
+		&user.Status,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed because user not found: %w", model.ErrorUserNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to execute get user for update query: %w", err)
+	}
+
+	return user, nil
+}
+
+// UpdateUserStatus changes the status of a user, provided that the current status is one of from.
+func (a *User) UpdateUserStatus(ctx context.Context, id, status string, from ...string) (*model.UserDao, error) {
 	query := `
 		UPDATE users
-		SET balance = $1
-		WHERE id = $2
-		RETURNING id, balance;
+		SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = ANY($3)
+		RETURNING id, status, created_at, updated_at;
 	`
-	_, err := tx.ExecContext(
+	user := &model.UserDao{}
+
+	err := a.conn.QueryRowContext(
 		ctx,
 		query,
-		user.Balance,
-		user.ID,
+		id,
+		status,
+		pq.Array(from),
+	).Scan(
+		&user.ID,
+		&user.Status,
+		&user.CreatedAt,
+		&user.UpdatedAt,
 	)
 
 	if err != nil {
-		var pqError *pq.Error
-		if errors.As(err, &pqError) {
-			if pqError.Constraint == "users_balance_check" {
-				return fmt.Errorf("failed to update user balance because of balance check constraint: %w", model.ErrorInsufficientBalance)
+		if errors.Is(err, sql.ErrNoRows) {
+			_, err = a.GetUser(ctx, id)
+			if err != nil {
+				return nil, err
 			}
+
+			return nil, fmt.Errorf("failed to change user status to %s: %w", status, model.ErrorUserStatusConflict)
 		}
 
-		return fmt.Errorf("failed to execute update user balance query: %w", err)
+		return nil, fmt.Errorf("failed to execute update user status query: %w", err)
 	}
 
-	return nil
+	return user, nil
+}
+
+// CloseUser closes a user whose current status is one of from, provided that the wallets of the user are empty
+// and no bet of the user is open. The user row is locked first, like for a transaction, so no money can come in
+// between the check and the update.
+func (a *User) CloseUser(ctx context.Context, id string, from ...string) (*model.UserDao, error) {
+	tx, err := a.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin db tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	user, err := a.GetUserForUpdate(tx, ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(from, user.Status) {
+		return nil, fmt.Errorf("failed to change user status to %s: %w", model.UserStatusClosed, model.ErrorUserStatusConflict)
+	}
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1 FROM wallets
+				WHERE user_id = $1 AND (balance <> 0 OR reserved <> 0 OR bonus_balance <> 0)
+			)
+			OR EXISTS (
+				SELECT 1 FROM bets
+				WHERE user_id = $1 AND state = $2
+			);
+	`
+	var funded bool
+
+	err = tx.QueryRowContext(ctx, query, id, model.BetStatePlaced).Scan(&funded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute check user funds query: %w", err)
+	}
+
+	if funded {
+		return nil, fmt.Errorf("failed to close user: %w", model.ErrorUserHasFunds)
+	}
+
+	query = `
+		UPDATE users
+		SET status = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, status, created_at, updated_at;
+	`
+
+	err = tx.QueryRowContext(ctx, query, id, model.UserStatusClosed).Scan(
+		&user.ID,
+		&user.Status,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute close user query: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit the db tx: %w", err)
+	}
+
+	return user, nil
 }
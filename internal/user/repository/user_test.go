@@ -6,9 +6,7 @@ import (
 	"testing"
 
 	"github.com/bxcodec/faker/v3"
-	"github.com/golang-migrate/migrate"
-	_ "github.com/golang-migrate/migrate/database/postgres"
-	_ "github.com/golang-migrate/migrate/source/file"
+	"github.com/golang-migrate/migrate/v4"
 	"github.com/stretchr/testify/suite"
 	"github.com/testcontainers/testcontainers-go"
 
@@ -56,27 +54,177 @@ func (u *userRepoTestSuite) TestCreateTransaction() {
 	u.Nil(us)
 }
 
-func (u *userRepoTestSuite) TestUpdateUserBalance() {
-	user := &model.UserDao{
-		ID:      "00000000-0000-0000-0000-000000000001",
-		Balance: 100,
+func (u *userRepoTestSuite) TestGetWallets() {
+	wallet := &model.WalletDao{
+		UserID:   "00000000-0000-0000-0000-000000000001",
+		Currency: "USD",
 	}
 
+	u.NoError(u.repo.CreateWallet(u.ctx, wallet))
+	u.Equal(model.Money(0), wallet.Balance)
+
+	tx := u.db.Connection.MustBegin().Tx
+
+	eur, err := u.repo.GetWalletForUpdate(tx, u.ctx, wallet.UserID, "EUR")
+	u.NoError(err)
+	u.NoError(u.repo.UpdateWalletBalance(tx, u.ctx, eur, &model.WalletMovement{Real: 1015}))
+	u.NoError(tx.Commit())
+
+	wallets, err := u.repo.GetWallets(u.ctx, wallet.UserID)
+	u.NoError(err)
+	u.Len(wallets, 2)
+	u.Equal("EUR", wallets[0].Currency)
+	u.Equal(model.Money(1015), wallets[0].Balance)
+	u.Equal(eur.UpdatedAt.Unix(), wallets[0].UpdatedAt.Unix())
+	u.Equal("USD", wallets[1].Currency)
+	u.Equal(model.Money(0), wallets[1].Balance)
+
+	wallets, err = u.repo.GetWallets(u.ctx, faker.UUIDHyphenated())
+	u.NoError(err)
+	u.Empty(wallets)
+}
+
+func (u *userRepoTestSuite) TestCreateWallet() {
+	err := u.repo.CreateWallet(u.ctx, &model.WalletDao{UserID: "00000000-0000-0000-0000-000000000001", Currency: "EUR"})
+	u.Equal(true, errors.Is(err, model.ErrorWalletAlreadyExists))
+
+	err = u.repo.CreateWallet(u.ctx, &model.WalletDao{UserID: faker.UUIDHyphenated(), Currency: "EUR"})
+	u.Equal(true, errors.Is(err, model.ErrorUserNotFound))
+}
+
+func (u *userRepoTestSuite) TestGetUserForUpdate() {
 	tx := u.db.Connection.MustBegin().Tx
+	defer tx.Rollback()
 
-	err := u.repo.UpdateUserBalance(tx, u.ctx, user)
+	us, err := u.repo.GetUserForUpdate(tx, u.ctx, "00000000-0000-0000-0000-000000000001")
 	u.NoError(err)
-	u.Equal(float32(100), user.Balance)
+	u.Equal("00000000-0000-0000-0000-000000000001", us.ID)
+
+	us, err = u.repo.GetUserForUpdate(tx, u.ctx, faker.UUIDHyphenated())
+	u.Equal(true, errors.Is(err, model.ErrorUserNotFound))
+	u.Nil(us)
+}
+
+func (u *userRepoTestSuite) TestUpdateWalletBalance() {
+	tx := u.db.Connection.MustBegin().Tx
 
-	user.Balance = -100
-	err = u.repo.UpdateUserBalance(tx, u.ctx, user)
+	wallet, err := u.repo.GetWalletForUpdate(tx, u.ctx, "00000000-0000-0000-0000-000000000001", "EUR")
+	u.NoError(err)
+
+	err = u.repo.UpdateWalletBalance(tx, u.ctx, wallet, &model.WalletMovement{Real: 10000})
+	u.NoError(err)
+	u.Equal(model.Money(10000), wallet.Balance)
+
+	err = u.repo.UpdateWalletBalance(tx, u.ctx, wallet, &model.WalletMovement{Real: -2550})
+	u.NoError(err)
+	u.Equal(model.Money(7450), wallet.Balance)
+
+	err = u.repo.UpdateWalletBalance(tx, u.ctx, wallet, &model.WalletMovement{Bonus: 500, WageringRequired: 2000, Wagered: 300})
+	u.NoError(err)
+	u.Equal(model.Money(7450), wallet.Balance)
+	u.Equal(model.Money(500), wallet.BonusBalance)
+	u.Equal(model.Money(2000), wallet.WageringRequired)
+	u.Equal(model.Money(300), wallet.Wagered)
+
+	err = u.repo.UpdateWalletBalance(tx, u.ctx, wallet, &model.WalletMovement{Real: -10000})
 	u.Equal(true, errors.Is(err, model.ErrorInsufficientBalance))
 
+	err = tx.Rollback()
+	u.NoError(err)
+
 	tx = u.db.Connection.MustBegin().Tx
-	user.ID = faker.UUIDHyphenated()
-	err = u.repo.UpdateUserBalance(tx, u.ctx, user)
-	u.Nil(err)
+
+	wallet, err = u.repo.GetWalletForUpdate(tx, u.ctx, "00000000-0000-0000-0000-000000000001", "EUR")
+	u.NoError(err)
+
+	err = u.repo.UpdateWalletBalance(tx, u.ctx, wallet, &model.WalletMovement{Bonus: -1})
+	u.Equal(true, errors.Is(err, model.ErrorInsufficientBalance))
 
 	err = tx.Rollback()
 	u.NoError(err)
+
+	tx = u.db.Connection.MustBegin().Tx
+
+	_, err = u.repo.GetWalletForUpdate(tx, u.ctx, "00000000-0000-0000-0000-000000000001", "USD")
+	u.Equal(true, errors.Is(err, model.ErrorWalletNotFound))
+
+	err = u.repo.UpdateWalletBalance(tx, u.ctx, &model.WalletDao{UserID: faker.UUIDHyphenated(), Currency: "EUR"}, &model.WalletMovement{Real: 100})
+	u.Equal(true, errors.Is(err, model.ErrorWalletNotFound))
+
+	err = tx.Rollback()
+	u.NoError(err)
+}
+
+func (u *userRepoTestSuite) TestCreateUser() {
+	user := &model.UserDao{}
+
+	u.NoError(u.repo.CreateUser(u.ctx, user))
+	u.NotEmpty(user.ID)
+	u.Equal(model.UserStatusActive, user.Status)
+
+	us, err := u.repo.GetUser(u.ctx, user.ID)
+	u.NoError(err)
+	u.Equal(user.ID, us.ID)
+}
+
+func (u *userRepoTestSuite) TestUpdateUserStatus() {
+	user := &model.UserDao{}
+	u.NoError(u.repo.CreateUser(u.ctx, user))
+
+	us, err := u.repo.UpdateUserStatus(u.ctx, user.ID, model.UserStatusSuspended, model.UserStatusActive)
+	u.NoError(err)
+	u.Equal(model.UserStatusSuspended, us.Status)
+
+	us, err = u.repo.UpdateUserStatus(u.ctx, user.ID, model.UserStatusSuspended, model.UserStatusActive)
+	u.Equal(true, errors.Is(err, model.ErrorUserStatusConflict))
+	u.Nil(us)
+
+	us, err = u.repo.UpdateUserStatus(u.ctx, user.ID, model.UserStatusClosed, model.UserStatusActive, model.UserStatusSuspended)
+	u.NoError(err)
+	u.Equal(model.UserStatusClosed, us.Status)
+
+	us, err = u.repo.UpdateUserStatus(u.ctx, faker.UUIDHyphenated(), model.UserStatusClosed, model.UserStatusActive)
+	u.Equal(true, errors.Is(err, model.ErrorUserNotFound))
+	u.Nil(us)
+}
+
+func (u *userRepoTestSuite) TestCloseUser() {
+	user := &model.UserDao{}
+	u.NoError(u.repo.CreateUser(u.ctx, user))
+	u.NoError(u.repo.CreateWallet(u.ctx, &model.WalletDao{UserID: user.ID, Currency: "EUR"}))
+
+	move := func(m *model.WalletMovement) {
+		tx := u.db.Connection.MustBegin().Tx
+
+		wallet, err := u.repo.GetWalletForUpdate(tx, u.ctx, user.ID, "EUR")
+		u.Require().NoError(err)
+		u.Require().NoError(u.repo.UpdateWalletBalance(tx, u.ctx, wallet, m))
+		u.Require().NoError(tx.Commit())
+	}
+
+	// A user with money left in a wallet stays open.
+	move(&model.WalletMovement{Real: 100, Bonus: 50})
+
+	us, err := u.repo.CloseUser(u.ctx, user.ID, model.UserStatusActive, model.UserStatusSuspended)
+	u.Equal(true, errors.Is(err, model.ErrorUserHasFunds))
+	u.Nil(us)
+
+	move(&model.WalletMovement{Real: -100})
+
+	_, err = u.repo.CloseUser(u.ctx, user.ID, model.UserStatusActive, model.UserStatusSuspended)
+	u.Equal(true, errors.Is(err, model.ErrorUserHasFunds))
+
+	move(&model.WalletMovement{Bonus: -50})
+
+	us, err = u.repo.CloseUser(u.ctx, user.ID, model.UserStatusActive, model.UserStatusSuspended)
+	u.NoError(err)
+	u.Equal(model.UserStatusClosed, us.Status)
+
+	us, err = u.repo.CloseUser(u.ctx, user.ID, model.UserStatusActive, model.UserStatusSuspended)
+	u.Equal(true, errors.Is(err, model.ErrorUserStatusConflict))
+	u.Nil(us)
+
+	us, err = u.repo.CloseUser(u.ctx, faker.UUIDHyphenated(), model.UserStatusActive)
+	u.Equal(true, errors.Is(err, model.ErrorUserNotFound))
+	u.Nil(us)
 }
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// CreateWallet opens a wallet with zero balance for a user in the currency of the given wallet.
+func (a *User) CreateWallet(ctx context.Context, wallet *model.WalletDao) error {
+	query := `
+		INSERT INTO wallets (user_id, currency)
+		VALUES ($1, $2)
+		RETURNING balance, reserved, bonus_balance, wagering_required, wagered, created_at, updated_at;
+	`
+	err := a.conn.QueryRowContext(
+		ctx,
+		query,
+		wallet.UserID,
+		wallet.Currency,
+	).Scan(
+		&wallet.Balance,
+		&wallet.Reserved,
+		&wallet.BonusBalance,
+		&wallet.WageringRequired,
+		&wallet.Wagered,
+		&wallet.CreatedAt,
+		&wallet.UpdatedAt,
+	)
+
+	if err != nil {
+		var pqError *pq.Error
+		if errors.As(err, &pqError) {
+			switch pqError.Constraint {
+			case "wallets_pkey":
+				return fmt.Errorf("failed to insert wallet because of unique constraint: %w", model.ErrorWalletAlreadyExists)
+			case "wallets_user_id_fkey":
+				return fmt.Errorf("failed because user not found: %w", model.ErrorUserNotFound)
+			}
+		}
+
+		return fmt.Errorf("failed to execute insert wallet query: %w", err)
+	}
+
+	return nil
+}
+
+// GetWallets returns the wallets of a user ordered by currency.
+func (a *User) GetWallets(ctx context.Context, userID string) ([]*model.WalletDao, error) {
+	query := `
+		SELECT
+			user_id,
+			currency,
+			balance,
+			reserved,
+			bonus_balance,
+			wagering_required,
+			wagered,
+			created_at,
+			updated_at
+		FROM wallets
+		WHERE user_id = $1
+		ORDER BY currency;
+	`
+	rows, err := a.conn.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get wallets query: %w", err)
+	}
+
+	defer rows.Close()
+
+	wallets := []*model.WalletDao{}
+
+	for rows.Next() {
+		wallet := &model.WalletDao{}
+		err = rows.Scan(
+			&wallet.UserID,
+			&wallet.Currency,
+			&wallet.Balance,
+			&wallet.Reserved,
+			&wallet.BonusBalance,
+			&wallet.WageringRequired,
+			&wallet.Wagered,
+			&wallet.CreatedAt,
+			&wallet.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan wallet row: %w", err)
+		}
+
+		wallets = append(wallets, wallet)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate wallet rows: %w", err)
+	}
+
+	return wallets, nil
+}
+
+// GetWalletForUpdate returns the wallet of a user in a currency and locks the row until the given db tx ends.
+func (a *User) GetWalletForUpdate(tx *sql.Tx, ctx context.Context, userID, currency string) (*model.WalletDao, error) {
+	query := `
+		SELECT
+			user_id,
+			currency,
+			balance,
+			reserved,
+			bonus_balance,
+			wagering_required,
+			wagered,
+			created_at,
+			updated_at
+		FROM wallets
+		WHERE user_id = $1 AND currency = $2 FOR UPDATE;
+	`
+	wallet := &model.WalletDao{}
+
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		userID,
+		currency,
+	).Scan(
+		&wallet.UserID,
+		&wallet.Currency,
+		&wallet.Balance,
+		&wallet.Reserved,
+		&wallet.BonusBalance,
+		&wallet.WageringRequired,
+		&wallet.Wagered,
+		&wallet.CreatedAt,
+		&wallet.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed because the user has no %s wallet: %w", currency, model.ErrorWalletNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to execute get wallet for update query: %w", err)
+	}
+
+	return wallet, nil
+}
+
+// UpdateWalletBalance applies the given movement, whose fields may be negative, to a wallet.
+// The change is applied by the database itself, the wallet is refreshed with the resulting balances.
+func (a *User) UpdateWalletBalance(tx *sql.Tx, ctx context.Context, wallet *model.WalletDao, m *model.WalletMovement) error {
+	query := `
+		UPDATE wallets
+		SET balance = balance + $1,
+			reserved = reserved + $2,
+			bonus_balance = bonus_balance + $3,
+			wagering_required = wagering_required + $4,
+			wagered = wagered + $5,
+			updated_at = NOW()
+		WHERE user_id = $6 AND currency = $7
+		RETURNING balance, reserved, bonus_balance, wagering_required, wagered, updated_at;
+	`
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		m.Real,
+		m.Reserved,
+		m.Bonus,
+		m.WageringRequired,
+		m.Wagered,
+		wallet.UserID,
+		wallet.Currency,
+	).Scan(
+		&wallet.Balance,
+		&wallet.Reserved,
+		&wallet.BonusBalance,
+		&wallet.WageringRequired,
+		&wallet.Wagered,
+		&wallet.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed because the user has no %s wallet: %w", wallet.Currency, model.ErrorWalletNotFound)
+		}
+
+		var pqError *pq.Error
+		if errors.As(err, &pqError) {
+			switch pqError.Constraint {
+			case "wallets_balance_check", "wallets_bonus_balance_check", "wallets_reserved_check":
+				return fmt.Errorf("failed to update wallet balance because of balance check constraint: %w", model.ErrorInsufficientBalance)
+			}
+		}
+
+		return fmt.Errorf("failed to execute update wallet balance query: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package user
+
+import (
+	"context"
+
+	"github.com/ttagiyeva/entain/internal/model"
+)
+
+// Usecase is a usecase interface for user.
+//
+//go:generate mockgen -source ./usecase.go -mock_names Usecase=MockUserUsecase -package mocks -destination mocks/userUsecase.mock.gen.go
+type Usecase interface {
+	CreateUser(ctx context.Context) (*model.User, error)
+	GetUser(ctx context.Context, id string) (*model.User, error)
+	GetBalance(ctx context.Context, id string) (*model.Balance, error)
+	OpenWallet(ctx context.Context, id, currency string) (*model.Wallet, error)
+	Suspend(ctx context.Context, id string) (*model.User, error)
+	Activate(ctx context.Context, id string) (*model.User, error)
+	Close(ctx context.Context, id string) (*model.User, error)
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/user"
+)
+
+// User is a structure which manages user usecase.
+type User struct {
+	log      *slog.Logger
+	userRepo user.Repository
+}
+
+// New creates a new user usecase.
+func New(log *slog.Logger, u user.Repository) *User {
+	return &User{
+		log:      log,
+		userRepo: u,
+	}
+}
+
+// CreateUser creates a new active user without wallets.
+func (u *User) CreateUser(ctx context.Context) (*model.User, error) {
+	user := &model.UserDao{}
+
+	err := u.userRepo.CreateUser(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return model.UserDaoToUser(user), nil
+}
+
+// GetUser returns a user by id.
+func (u *User) GetUser(ctx context.Context, id string) (*model.User, error) {
+	user, err := u.userRepo.GetUser(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return model.UserDaoToUser(user), nil
+}
+
+// Suspend temporarily refuses the transactions of an active user.
+func (u *User) Suspend(ctx context.Context, id string) (*model.User, error) {
+	return u.changeStatus(ctx, id, model.UserStatusSuspended, model.UserStatusActive)
+}
+
+// Activate allows the transactions of a suspended user again.
+func (u *User) Activate(ctx context.Context, id string) (*model.User, error) {
+	return u.changeStatus(ctx, id, model.UserStatusActive, model.UserStatusSuspended)
+}
+
+// Close closes the wallet of an active or suspended user, a closed user cannot be reopened.
+// A user with money in a wallet or an open bet cannot be closed, the funds would be stranded.
+func (u *User) Close(ctx context.Context, id string) (*model.User, error) {
+	user, err := u.userRepo.CloseUser(ctx, id, model.UserStatusActive, model.UserStatusSuspended)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close user: %w", err)
+	}
+
+	return model.UserDaoToUser(user), nil
+}
+
+func (u *User) changeStatus(ctx context.Context, id, status string, from ...string) (*model.User, error) {
+	user, err := u.userRepo.UpdateUserStatus(ctx, id, status, from...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user status: %w", err)
+	}
+
+	return model.UserDaoToUser(user), nil
+}
+
+// GetBalance returns the current balance of a user in every currency the user has a wallet in.
+func (u *User) GetBalance(ctx context.Context, id string) (*model.Balance, error) {
+	_, err := u.userRepo.GetUser(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	wallets, err := u.userRepo.GetWallets(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user wallets: %w", err)
+	}
+
+	return model.WalletDaosToBalance(id, wallets), nil
+}
+
+// OpenWallet opens a wallet with zero balance for a user in the given currency.
+// A closed user cannot open new wallets.
+func (u *User) OpenWallet(ctx context.Context, id, currency string) (*model.Wallet, error) {
+	user, err := u.userRepo.GetUser(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.Status == model.UserStatusClosed {
+		return nil, fmt.Errorf("failed to open %s wallet: %w", currency, model.ErrorUserClosed)
+	}
+
+	wallet := &model.WalletDao{
+		UserID:   id,
+		Currency: currency,
+	}
+
+	err = u.userRepo.CreateWallet(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+
+	return model.WalletDaoToWallet(wallet), nil
+}
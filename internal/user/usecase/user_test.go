@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ttagiyeva/entain/internal/model"
+	"github.com/ttagiyeva/entain/internal/user/mocks"
+)
+
+func TestGetBalance(t *testing.T) {
+	user := &model.UserDao{
+		ID:     gofakeit.UUID(),
+		Status: model.UserStatusActive,
+	}
+
+	wallets := []*model.WalletDao{
+		{UserID: user.ID, Currency: "EUR", Balance: 1015, UpdatedAt: time.Now()},
+		{UserID: user.ID, Currency: "USD", Balance: 250, UpdatedAt: time.Now()},
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(userRepo *mocks.MockUserRepository)
+		checkResponse func(balance *model.Balance, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+				userRepo.EXPECT().GetWallets(gomock.Any(), user.ID).Return(wallets, nil)
+			},
+			checkResponse: func(balance *model.Balance, err error) {
+				require.NoError(t, err)
+				require.Equal(t, user.ID, balance.UserID)
+				require.Len(t, balance.Wallets, 2)
+				require.Equal(t, "EUR", balance.Wallets[0].Currency)
+				require.Equal(t, "10.15", balance.Wallets[0].Balance)
+				require.Equal(t, wallets[0].UpdatedAt, balance.Wallets[0].UpdatedAt)
+				require.Equal(t, "USD", balance.Wallets[1].Currency)
+				require.Equal(t, "2.50", balance.Wallets[1].Balance)
+			},
+		},
+		{
+			name: "No wallets",
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+				userRepo.EXPECT().GetWallets(gomock.Any(), user.ID).Return([]*model.WalletDao{}, nil)
+			},
+			checkResponse: func(balance *model.Balance, err error) {
+				require.NoError(t, err)
+				require.Empty(t, balance.Wallets)
+			},
+		},
+		{
+			name: "User not found",
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), user.ID).Return(nil, model.ErrorUserNotFound)
+			},
+			checkResponse: func(balance *model.Balance, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserNotFound))
+				require.Nil(t, balance)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			userRepo := mocks.NewMockUserRepository(ctrl)
+
+			tc.buildStubs(userRepo)
+
+			usecase := New(nil, userRepo)
+			balance, err := usecase.GetBalance(context.Background(), user.ID)
+
+			tc.checkResponse(balance, err)
+		})
+	}
+}
+
+func TestOpenWallet(t *testing.T) {
+	id := gofakeit.UUID()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(userRepo *mocks.MockUserRepository)
+		checkResponse func(wallet *model.Wallet, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), id).Return(&model.UserDao{ID: id, Status: model.UserStatusActive}, nil)
+				userRepo.EXPECT().CreateWallet(gomock.Any(), &model.WalletDao{UserID: id, Currency: "USD"}).Return(nil)
+			},
+			checkResponse: func(wallet *model.Wallet, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "USD", wallet.Currency)
+				require.Equal(t, "0.00", wallet.Balance)
+			},
+		},
+		{
+			name: "Closed user",
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), id).Return(&model.UserDao{ID: id, Status: model.UserStatusClosed}, nil)
+			},
+			checkResponse: func(wallet *model.Wallet, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserClosed))
+				require.Nil(t, wallet)
+			},
+		},
+		{
+			name: "Wallet already exists",
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(gomock.Any(), id).Return(&model.UserDao{ID: id, Status: model.UserStatusActive}, nil)
+				userRepo.EXPECT().CreateWallet(gomock.Any(), gomock.Any()).Return(model.ErrorWalletAlreadyExists)
+			},
+			checkResponse: func(wallet *model.Wallet, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorWalletAlreadyExists))
+				require.Nil(t, wallet)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			userRepo := mocks.NewMockUserRepository(ctrl)
+
+			tc.buildStubs(userRepo)
+
+			wallet, err := New(nil, userRepo).OpenWallet(context.Background(), id, "USD")
+
+			tc.checkResponse(wallet, err)
+		})
+	}
+}
+
+func TestCreateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *model.UserDao) error {
+		user.ID = "1"
+		user.Status = model.UserStatusActive
+
+		return nil
+	})
+
+	usecase := New(nil, userRepo)
+
+	user, err := usecase.CreateUser(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "1", user.ID)
+	require.Equal(t, model.UserStatusActive, user.Status)
+}
+
+func TestChangeStatus(t *testing.T) {
+	id := gofakeit.UUID()
+
+	testCases := []struct {
+		name          string
+		call          func(u *User) (*model.User, error)
+		buildStubs    func(userRepo *mocks.MockUserRepository)
+		checkResponse func(user *model.User, err error)
+	}{
+		{
+			name: "Suspend",
+			call: func(u *User) (*model.User, error) {
+				return u.Suspend(context.Background(), id)
+			},
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().UpdateUserStatus(gomock.Any(), id, model.UserStatusSuspended, model.UserStatusActive).
+					Return(&model.UserDao{ID: id, Status: model.UserStatusSuspended}, nil)
+			},
+			checkResponse: func(user *model.User, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.UserStatusSuspended, user.Status)
+			},
+		},
+		{
+			name: "Activate",
+			call: func(u *User) (*model.User, error) {
+				return u.Activate(context.Background(), id)
+			},
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().UpdateUserStatus(gomock.Any(), id, model.UserStatusActive, model.UserStatusSuspended).
+					Return(&model.UserDao{ID: id, Status: model.UserStatusActive}, nil)
+			},
+			checkResponse: func(user *model.User, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.UserStatusActive, user.Status)
+			},
+		},
+		{
+			name: "Close",
+			call: func(u *User) (*model.User, error) {
+				return u.Close(context.Background(), id)
+			},
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().CloseUser(gomock.Any(), id, model.UserStatusActive, model.UserStatusSuspended).
+					Return(&model.UserDao{ID: id, Status: model.UserStatusClosed}, nil)
+			},
+			checkResponse: func(user *model.User, err error) {
+				require.NoError(t, err)
+				require.Equal(t, model.UserStatusClosed, user.Status)
+			},
+		},
+		{
+			name: "Close with funds",
+			call: func(u *User) (*model.User, error) {
+				return u.Close(context.Background(), id)
+			},
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().CloseUser(gomock.Any(), id, model.UserStatusActive, model.UserStatusSuspended).
+					Return(nil, model.ErrorUserHasFunds)
+			},
+			checkResponse: func(user *model.User, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserHasFunds))
+				require.Nil(t, user)
+			},
+		},
+		{
+			name: "Status conflict",
+			call: func(u *User) (*model.User, error) {
+				return u.Suspend(context.Background(), id)
+			},
+			buildStubs: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().UpdateUserStatus(gomock.Any(), id, model.UserStatusSuspended, model.UserStatusActive).
+					Return(nil, model.ErrorUserStatusConflict)
+			},
+			checkResponse: func(user *model.User, err error) {
+				require.Equal(t, true, errors.Is(err, model.ErrorUserStatusConflict))
+				require.Nil(t, user)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			userRepo := mocks.NewMockUserRepository(ctrl)
+
+			tc.buildStubs(userRepo)
+
+			user, err := tc.call(New(nil, userRepo))
+
+			tc.checkResponse(user, err)
+		})
+	}
+}